@@ -32,18 +32,28 @@
 package objmodel // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/objmodel"
 
 import (
+	"bytes"
 	"encoding/hex"
+	stdjson "encoding/json"
+	"fmt"
 	"io"
 	"maps"
 	"math"
+	"path"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/elastic/go-structform"
 	"github.com/elastic/go-structform/json"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/traceutil"
 )
 
 // Document is an intermediate representation for converting open telemetry records with arbitrary attributes
@@ -68,6 +78,18 @@ type Value struct {
 	arr  []Value
 	doc  Document
 	ts   time.Time
+	dur  time.Duration
+
+	// tsFormat is the serialization format of a KindTimestamp value.
+	tsFormat TimestampFormat
+
+	// slice holds the attributes of an array value that is converted
+	// lazily during serialization. Only used if lazyArr is set.
+	slice   pcommon.Slice
+	lazyArr bool
+
+	// raw holds the pre-serialized JSON of a KindRawJSON value.
+	raw []byte
 }
 
 // Kind represent the internal kind of a value stored in a Document.
@@ -86,39 +108,246 @@ const (
 	KindTimestamp
 	KindIgnore
 	KindUnflattenableObject // Unflattenable object is an object that should not be flattened at serialization time
+	KindDuration
+	KindNull    // Null is an explicit JSON null, which is serialized instead of being omitted like KindNil
+	KindRawJSON // RawJSON is pre-serialized JSON, which is written verbatim at serialization time
+)
+
+const (
+	tsLayout       = "2006-01-02T15:04:05.000000000Z"
+	tsLayoutMillis = "2006-01-02T15:04:05.000Z"
 )
 
-const tsLayout = "2006-01-02T15:04:05.000000000Z"
+// DefaultTimestampKey is the key of the timestamp field of documents built from telemetry.
+const DefaultTimestampKey = "@timestamp"
+
+// TimestampFormat selects how a timestamp value is serialized.
+type TimestampFormat uint8
+
+const (
+	// TimestampFormatNanos serializes timestamps as ISO 8601 strings with nanosecond
+	// precision, as accepted by date_nanos fields.
+	TimestampFormatNanos TimestampFormat = iota
+	// TimestampFormatMillis serializes timestamps as ISO 8601 strings with millisecond
+	// precision, as accepted by date fields with the default format.
+	TimestampFormatMillis
+	// TimestampFormatEpochMillis serializes timestamps as the number of milliseconds
+	// since the Unix epoch.
+	TimestampFormatEpochMillis
+)
 
 var (
 	nilValue    = Value{kind: KindNil}
+	nullValue   = Value{kind: KindNull}
 	ignoreValue = Value{kind: KindIgnore}
 )
 
+// DocumentOption configures optional behavior when creating a document from attributes.
+type DocumentOption func(*documentConfig)
+
+type documentConfig struct {
+	keepNulls        bool
+	geoPointPatterns []string
+
+	addTimestamp    bool
+	timestamp       pcommon.Timestamp
+	timestampKey    string
+	timestampFormat TimestampFormat
+}
+
+// WithNullValues keeps attributes without a value as explicit null fields, such
+// that they are serialized as JSON null instead of being dropped. This allows a
+// previously indexed value to be overwritten on update.
+func WithNullValues() DocumentOption {
+	return func(cfg *documentConfig) {
+		cfg.keepNulls = true
+	}
+}
+
+// WithGeoPointDetection serializes attributes whose flattened key matches one of
+// the given patterns in the Elasticsearch geo_point format {"lat": ..., "lon": ...}.
+// Patterns use the path.Match syntax, e.g. "*.geo" or "location". A matching
+// attribute is converted if it is a map holding numeric lat and lon entries or
+// a "lat,lon" string. Other values are kept as they are.
+func WithGeoPointDetection(patterns ...string) DocumentOption {
+	return func(cfg *documentConfig) {
+		cfg.geoPointPatterns = append(cfg.geoPointPatterns, patterns...)
+	}
+}
+
+// WithTimestamp adds ts as the timestamp field of the document, e.g. the
+// timestamp of the log record or metric data point the attributes belong to.
+// It is stored under DefaultTimestampKey unless configured otherwise with
+// WithTimestampKey.
+func WithTimestamp(ts pcommon.Timestamp) DocumentOption {
+	return func(cfg *documentConfig) {
+		cfg.addTimestamp = true
+		cfg.timestamp = ts
+	}
+}
+
+// WithTimestampKey stores the timestamp added by WithTimestamp under the given
+// key. The key is not prefixed with the path of the document.
+func WithTimestampKey(key string) DocumentOption {
+	return func(cfg *documentConfig) {
+		cfg.timestampKey = key
+	}
+}
+
+// WithTimestampFormat serializes the timestamp added by WithTimestamp in the
+// given format. It is serialized with nanosecond precision by default.
+func WithTimestampFormat(format TimestampFormat) DocumentOption {
+	return func(cfg *documentConfig) {
+		cfg.timestampFormat = format
+	}
+}
+
 // DocumentFromAttributes creates a document from a OpenTelemetry attribute
 // map. All nested maps will be flattened, with keys being joined using a `.` symbol.
-func DocumentFromAttributes(am pcommon.Map) Document {
-	return DocumentFromAttributesWithPath("", am)
+func DocumentFromAttributes(am pcommon.Map, opts ...DocumentOption) Document {
+	return DocumentFromAttributesWithPath("", am, opts...)
 }
 
 // DocumentFromAttributesWithPath creates a document from a OpenTelemetry attribute
 // map. All nested maps will be flattened, with keys being joined using a `.` symbol.
 //
 // All keys in the map will be prefixed with path.
-func DocumentFromAttributesWithPath(path string, am pcommon.Map) Document {
+func DocumentFromAttributesWithPath(path string, am pcommon.Map, opts ...DocumentOption) Document {
+	cfg := documentConfig{timestampKey: DefaultTimestampKey}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var doc Document
+	if cfg.addTimestamp {
+		doc.AddTimestampWithFormat(cfg.timestampKey, cfg.timestamp, cfg.timestampFormat)
+	}
 	if am.Len() == 0 {
-		return Document{}
+		return doc
+	}
+
+	doc.fields = appendAttributeFields(slices.Grow(doc.fields, am.Len()), path, am, cfg)
+	return doc
+}
+
+// SpanKeys holds the document keys under which DocumentFromSpan stores the
+// span fields. Fields with an empty key are not added to the document.
+type SpanKeys struct {
+	TraceID        string
+	SpanID         string
+	ParentSpanID   string
+	Name           string
+	Kind           string
+	StartTimestamp string
+	EndTimestamp   string
+	StatusCode     string
+	StatusMessage  string
+	Attributes     string
+}
+
+// DefaultSpanKeys returns the keys used by DocumentFromSpan if no keys are
+// configured. They match the field names of the raw span mapping.
+func DefaultSpanKeys() SpanKeys {
+	return SpanKeys{
+		TraceID:        "TraceId",
+		SpanID:         "SpanId",
+		ParentSpanID:   "ParentSpanId",
+		Name:           "Name",
+		Kind:           "Kind",
+		StartTimestamp: DefaultTimestampKey,
+		EndTimestamp:   "EndTimestamp",
+		StatusCode:     "TraceStatus",
+		StatusMessage:  "TraceStatusDescription",
+		Attributes:     "Attributes",
 	}
+}
+
+// SpanDocumentOption configures optional behavior when creating a document from a span.
+type SpanDocumentOption func(*spanDocumentConfig)
 
-	fields := make([]field, 0, am.Len())
-	fields = appendAttributeFields(fields, path, am)
-	return Document{fields: fields}
+type spanDocumentConfig struct {
+	keys            SpanKeys
+	timestampFormat TimestampFormat
 }
 
+// WithSpanKeys stores the span fields under the given keys instead of DefaultSpanKeys.
+func WithSpanKeys(keys SpanKeys) SpanDocumentOption {
+	return func(cfg *spanDocumentConfig) {
+		cfg.keys = keys
+	}
+}
+
+// WithSpanTimestampFormat serializes the start and end timestamps of the span in the
+// given format. They are serialized with nanosecond precision by default.
+func WithSpanTimestampFormat(format TimestampFormat) SpanDocumentOption {
+	return func(cfg *spanDocumentConfig) {
+		cfg.timestampFormat = format
+	}
+}
+
+// DocumentFromSpan creates a document holding the trace id, span id, parent span
+// id, name, kind, start and end timestamps, status and attributes of a span.
+// The span kind is added as its SPAN_KIND_* name and the status code as an
+// integer. Empty ids and strings are omitted. Attributes are flattened the same
+// way as by AddAttributes.
+func DocumentFromSpan(span ptrace.Span, opts ...SpanDocumentOption) Document {
+	cfg := spanDocumentConfig{keys: DefaultSpanKeys()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	keys := cfg.keys
+
+	var doc Document
+	if keys.StartTimestamp != "" {
+		doc.AddTimestampWithFormat(keys.StartTimestamp, span.StartTimestamp(), cfg.timestampFormat)
+	}
+	if keys.EndTimestamp != "" {
+		doc.AddTimestampWithFormat(keys.EndTimestamp, span.EndTimestamp(), cfg.timestampFormat)
+	}
+	if keys.TraceID != "" {
+		doc.AddTraceID(keys.TraceID, span.TraceID())
+	}
+	if keys.SpanID != "" {
+		doc.AddSpanID(keys.SpanID, span.SpanID())
+	}
+	if keys.ParentSpanID != "" {
+		doc.AddSpanID(keys.ParentSpanID, span.ParentSpanID())
+	}
+	if keys.Name != "" {
+		doc.AddString(keys.Name, span.Name())
+	}
+	if keys.Kind != "" {
+		doc.AddString(keys.Kind, traceutil.SpanKindStr(span.Kind()))
+	}
+	if keys.StatusCode != "" {
+		doc.AddInt(keys.StatusCode, int64(span.Status().Code()))
+	}
+	if keys.StatusMessage != "" {
+		doc.AddString(keys.StatusMessage, span.Status().Message())
+	}
+	if keys.Attributes != "" {
+		doc.AddAttributes(keys.Attributes, span.Attributes())
+	}
+	return doc
+}
+
+// Clone returns a deep copy of the document. Nested object and array values
+// are copied as well, such that the clone can be modified without affecting the
+// original document. This allows a base document to be reused for multiple records.
 func (doc *Document) Clone() *Document {
-	fields := make([]field, len(doc.fields))
-	copy(fields, doc.fields)
-	return &Document{fields: fields, dynamicTemplates: maps.Clone(doc.dynamicTemplates)}
+	clone := doc.clone()
+	return &clone
+}
+
+func (doc *Document) clone() Document {
+	var fields []field
+	if doc.fields != nil {
+		fields = make([]field, len(doc.fields))
+		for i := range doc.fields {
+			fields[i] = field{key: doc.fields[i].key, value: doc.fields[i].value.clone()}
+		}
+	}
+	return Document{fields: fields, dynamicTemplates: maps.Clone(doc.dynamicTemplates)}
 }
 
 func (doc *Document) AddDynamicTemplate(path, template string) {
@@ -132,16 +361,48 @@ func (doc *Document) DynamicTemplates() map[string]string {
 	return doc.dynamicTemplates
 }
 
+// Len returns the number of fields in the document.
+func (doc *Document) Len() int {
+	return len(doc.fields)
+}
+
+// Range calls fn for each field of the document, in order. Iteration stops
+// if fn returns false.
+func (doc *Document) Range(fn func(key string, v Value) bool) {
+	for _, fld := range doc.fields {
+		if !fn(fld.key, fld.value) {
+			return
+		}
+	}
+}
+
 // AddTimestamp adds a raw timestamp value to the Document.
 func (doc *Document) AddTimestamp(key string, ts pcommon.Timestamp) {
 	doc.Add(key, TimestampValue(ts.AsTime()))
 }
 
+// AddTimestampWithFormat adds a timestamp value to the Document, which is serialized
+// in the given format.
+func (doc *Document) AddTimestampWithFormat(key string, ts pcommon.Timestamp, format TimestampFormat) {
+	doc.Add(key, TimestampValueWithFormat(ts.AsTime(), format))
+}
+
 // Add adds a converted value to the document.
 func (doc *Document) Add(key string, v Value) {
 	doc.fields = append(doc.fields, field{key: key, value: v})
 }
 
+// AddRawJSON adds pre-serialized JSON to the document, which is written verbatim
+// when the document is serialized instead of being converted into values. The
+// field is sorted and deduplicated by its key like any other field. raw must be
+// a single valid JSON value and must not be modified until the document has been
+// serialized. If raw is empty, no value will be added.
+func (doc *Document) AddRawJSON(key string, raw stdjson.RawMessage) {
+	if len(raw) > 0 {
+		doc.Add(key, Value{kind: KindRawJSON, raw: raw})
+	}
+}
+
 // AddString adds a string to the document.
 func (doc *Document) AddString(key, v string) {
 	if v != "" {
@@ -165,6 +426,11 @@ func (doc *Document) AddTraceID(key string, id pcommon.TraceID) {
 	}
 }
 
+// AddDuration adds a duration value to the document.
+func (doc *Document) AddDuration(key string, d time.Duration) {
+	doc.Add(key, DurationValue(d))
+}
+
 // AddInt adds an integer value to the document.
 func (doc *Document) AddInt(key string, value int64) {
 	doc.Add(key, IntValue(value))
@@ -178,7 +444,7 @@ func (doc *Document) AddUInt(key string, value uint64) {
 // AddAttributes expands and flattens all key-value pairs from the input attribute map into
 // the document.
 func (doc *Document) AddAttributes(key string, attributes pcommon.Map) {
-	doc.fields = appendAttributeFields(doc.fields, key, attributes)
+	doc.fields = appendAttributeFields(doc.fields, key, attributes, documentConfig{})
 }
 
 // AddAttribute converts and adds a AttributeValue to the document. If the attribute represents a map,
@@ -219,9 +485,34 @@ func (doc *Document) AddLinks(key string, links ptrace.SpanLinkSlice) {
 	doc.Add(key, ArrValue(linkValues...))
 }
 
+// StripPrefix removes a leading `prefix.` from all keys in the document that
+// start with it. Other keys are left untouched. It is the inverse of the path
+// prefix applied by DocumentFromAttributesWithPath.
+func (doc *Document) StripPrefix(prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	prefix += "."
+	for i := range doc.fields {
+		fld := &doc.fields[i]
+		if len(fld.key) > len(prefix) && strings.HasPrefix(fld.key, prefix) {
+			fld.key = fld.key[len(prefix):]
+		}
+	}
+}
+
 func (doc *Document) sort() {
+	doc.sortBy(originalKey)
+}
+
+func originalKey(key string) string { return key }
+
+// sortBy sorts the fields by the keys returned by keyOf, e.g. the lowercased keys
+// if keys are compared case-insensitively.
+func (doc *Document) sortBy(keyOf func(string) string) {
 	sort.SliceStable(doc.fields, func(i, j int) bool {
-		return doc.fields[i].key < doc.fields[j].key
+		return keyOf(doc.fields[i].key) < keyOf(doc.fields[j].key)
 	})
 
 	for i := range doc.fields {
@@ -230,14 +521,51 @@ func (doc *Document) sort() {
 	}
 }
 
+// DedupOption configures optional behavior of Document.Dedup.
+type DedupOption func(*dedupConfig)
+
+type dedupConfig struct {
+	dropExactDuplicates bool
+	caseInsensitiveKeys bool
+}
+
+// WithDropExactDuplicates removes fields whose key and value are both equal to
+// those of another field from the document, instead of only marking them as
+// ignored.
+func WithDropExactDuplicates() DedupOption {
+	return func(cfg *dedupConfig) {
+		cfg.dropExactDuplicates = true
+	}
+}
+
+// WithCaseInsensitiveKeys compares keys case-insensitively while resolving
+// duplicates, such that keys only differing in case, like Host and host, are
+// treated as duplicates. The surviving field keeps its original key, keys without
+// a duplicate are not changed. Keys are compared case-sensitively by default.
+func WithCaseInsensitiveKeys() DedupOption {
+	return func(cfg *dedupConfig) {
+		cfg.caseInsensitiveKeys = true
+	}
+}
+
 // Dedup removes fields from the document, that have duplicate keys.
 // The filtering only keeps the last value for a key.
 //
 // Dedup ensure that keys are sorted.
-func (doc *Document) Dedup() {
+func (doc *Document) Dedup(opts ...DedupOption) {
+	var cfg dedupConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	keyOf := originalKey
+	if cfg.caseInsensitiveKeys {
+		keyOf = strings.ToLower
+	}
+
 	// 1. Always ensure the fields are sorted, Dedup support requires
 	// Fields to be sorted.
-	doc.sort()
+	doc.sortBy(keyOf)
 
 	// 2. rename fields if a primitive value is overwritten by an object.
 	//    For example the pair (path.x=1, path.x.a="test") becomes:
@@ -254,57 +582,354 @@ func (doc *Document) Dedup() {
 	//    This step removes potential conflicts when dedotting and serializing fields.
 	var renamed bool
 	for i := 0; i < len(doc.fields)-1; i++ {
-		key, nextKey := doc.fields[i].key, doc.fields[i+1].key
+		key, nextKey := keyOf(doc.fields[i].key), keyOf(doc.fields[i+1].key)
 		if len(key) < len(nextKey) && strings.HasPrefix(nextKey, key) && nextKey[len(key)] == '.' {
 			renamed = true
-			doc.fields[i].key = key + ".value"
+			doc.fields[i].key += ".value"
 		}
 	}
 	if renamed {
-		doc.sort()
+		doc.sortBy(keyOf)
 	}
 
 	// 3. mark duplicates as 'ignore'
 	//
 	//    This step ensures that we do not have duplicate fields names when serializing.
 	//    Elasticsearch JSON parser will fail otherwise.
-	for i := 0; i < len(doc.fields)-1; i++ {
-		if doc.fields[i].key == doc.fields[i+1].key {
+	//    With WithDropExactDuplicates, fields equal to the following field are removed instead.
+	fields := doc.fields[:0]
+	for i := range doc.fields {
+		if i+1 < len(doc.fields) && keyOf(doc.fields[i].key) == keyOf(doc.fields[i+1].key) {
+			if cfg.dropExactDuplicates && doc.fields[i].value.Equal(doc.fields[i+1].value) {
+				continue
+			}
 			doc.fields[i].value = ignoreValue
 		}
+		fields = append(fields, doc.fields[i])
+	}
+	doc.fields = fields
+	if cfg.caseInsensitiveKeys {
+		// The remaining keys may still differ in case, restore the case-sensitive
+		// order expected by the serializer.
+		doc.sort()
 	}
 
 	// 4. fix objects that might be stored in arrays
 	for i := range doc.fields {
-		doc.fields[i].value.Dedup()
+		doc.fields[i].value.Dedup(opts...)
+	}
+}
+
+// dedup runs Dedup, keeping the insertion order of the fields if configured.
+func (doc *Document) dedup(cfg serializeConfig, dedot bool) {
+	if cfg.indexedObjectArrays {
+		doc.flattenObjectArrays()
+	}
+	if cfg.insertionOrder && !dedot {
+		doc.dedupInsertionOrder()
+		return
+	}
+	doc.Dedup()
+}
+
+// flattenObjectArrays replaces fields holding an array of objects with the
+// fields of the objects, keyed by the array key and the element index. Arrays
+// of objects nested in the flattened objects are flattened as well.
+func (doc *Document) flattenObjectArrays() {
+	for i := range doc.fields {
+		if v := &doc.fields[i].value; v.kind == KindObject || v.isObjectArr() {
+			fields := make([]field, i, len(doc.fields))
+			copy(fields, doc.fields[:i])
+			for _, fld := range doc.fields[i:] {
+				fields = appendIndexedFields(fields, fld.key, fld.value)
+			}
+			doc.fields = fields
+			return
+		}
+	}
+}
+
+func appendIndexedFields(fields []field, key string, v Value) []field {
+	if !v.isObjectArr() {
+		if v.kind == KindObject {
+			v.doc.flattenObjectArrays()
+		}
+		return append(fields, field{key: key, value: v})
+	}
+	for i, elem := range v.values() {
+		elemKey := flattenKey(key, strconv.Itoa(i))
+		for _, fld := range elem.doc.fields {
+			fields = appendIndexedFields(fields, flattenKey(elemKey, fld.key), fld.value)
+		}
+	}
+	return fields
+}
+
+// dedupInsertionOrder resolves duplicate and conflicting keys like Dedup, but
+// keeps the fields in their original order. Dedup runs on a sorted copy of
+// the keys, whose values hold the original field positions.
+func (doc *Document) dedupInsertionOrder() {
+	sorted := Document{fields: make([]field, len(doc.fields))}
+	for i := range doc.fields {
+		sorted.fields[i] = field{key: doc.fields[i].key, value: IntValue(int64(i))}
+	}
+	sorted.Dedup()
+
+	// fields which were marked as duplicates in the sorted copy lost their position
+	kept := make([]bool, len(doc.fields))
+	for i := range sorted.fields {
+		fld := &sorted.fields[i]
+		if fld.value.kind != KindInt {
+			continue
+		}
+		kept[fld.value.i] = true
+		doc.fields[fld.value.i].key = fld.key
+	}
+	for i := range doc.fields {
+		fld := &doc.fields[i]
+		if !kept[i] {
+			fld.value = ignoreValue
+			continue
+		}
+		fld.value.sort()
+		fld.value.Dedup()
+	}
+}
+
+// SerializeOption configures optional behavior of Document.Serialize.
+type SerializeOption func(*serializeConfig)
+
+type serializeConfig struct {
+	durationFormat      DurationFormat
+	mixedArrayMode      MixedArrayMode
+	maxStringLength     int
+	truncateMarker      string
+	insertionOrder      bool
+	maxDoubleDigits     int
+	omitEmptyObjects    bool
+	indexedObjectArrays bool
+	arrayFormats        []arrayFormatRule
+	stringSanitization  StringSanitization
+}
+
+// DurationFormat selects how duration values are serialized.
+type DurationFormat uint8
+
+const (
+	// DurationFormatMillis serializes durations as a number of milliseconds.
+	DurationFormatMillis DurationFormat = iota
+	// DurationFormatISO8601 serializes durations as ISO 8601 duration strings (e.g. PT1.5S).
+	DurationFormatISO8601
+)
+
+// WithDurationFormat configures the serialization format used for duration values.
+// Durations are serialized as milliseconds by default.
+func WithDurationFormat(format DurationFormat) SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.durationFormat = format
+	}
+}
+
+// MixedArrayMode selects how arrays holding scalar values of different types
+// (e.g. [1, "two"]) are serialized. Elasticsearch can't index such arrays
+// under a single field.
+type MixedArrayMode uint8
+
+const (
+	// MixedArrayKeep serializes mixed arrays as they are.
+	MixedArrayKeep MixedArrayMode = iota
+	// MixedArrayAsStrings converts all scalar elements of mixed arrays to strings.
+	MixedArrayAsStrings
+	// MixedArrayDropNonConforming drops the scalar elements of mixed arrays
+	// whose type differs from the type of the first scalar element.
+	MixedArrayDropNonConforming
+)
+
+// WithMixedArrayMode configures how arrays of mixed scalar types are serialized.
+// Mixed arrays are kept as they are by default.
+func WithMixedArrayMode(mode MixedArrayMode) SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.mixedArrayMode = mode
+	}
+}
+
+// WithMaxStringLength truncates string values longer than maxLength characters,
+// such that they don't exceed the ignore_above limit of keyword fields. If marker
+// is not empty, it is appended to truncated strings and counts towards maxLength.
+// Strings are not truncated by default.
+func WithMaxStringLength(maxLength int, marker string) SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.maxStringLength = maxLength
+		cfg.truncateMarker = marker
+	}
+}
+
+// WithMaxSignificantDigits rounds double values to at most digits significant
+// digits, e.g. 3.14159265 is serialized as 3.14 with 3 digits, reducing the size
+// of the serialized documents. Doubles are serialized with the shortest
+// representation that round-trips by default.
+func WithMaxSignificantDigits(digits int) SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.maxDoubleDigits = digits
+	}
+}
+
+// WithInsertionOrder serializes the top-level fields of a document in the order
+// they were added instead of sorted by key. Duplicate keys are still resolved
+// like in Dedup. The option is ignored when dedotting, as nested objects require
+// the fields to be sorted.
+func WithInsertionOrder() SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.insertionOrder = true
+	}
+}
+
+// WithOmitEmptyObjects omits fields holding an object without any non-empty
+// fields from the serialized document, instead of serializing them as null or
+// as an empty object. Objects nested in arrays are not affected.
+func WithOmitEmptyObjects() SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.omitEmptyObjects = true
+	}
+}
+
+// WithIndexedObjectArrays flattens arrays whose elements are all objects into
+// fields keyed by the array key, the element index and the object field key,
+// e.g. an array "arr" of two objects with field "a" becomes the fields "arr.0.a"
+// and "arr.1.a". The generated fields are deduplicated like any other field.
+// Object arrays are serialized as JSON arrays by default.
+func WithIndexedObjectArrays() SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.indexedObjectArrays = true
+	}
+}
+
+// StringSanitization selects how invalid UTF-8 and control characters in string
+// values are handled, as they can break ingestion in Elasticsearch.
+type StringSanitization uint8
+
+const (
+	// StringSanitizationOff serializes string values as they are.
+	StringSanitizationOff StringSanitization = iota
+	// StringSanitizationReplace replaces invalid UTF-8 bytes and control characters
+	// with the Unicode replacement character U+FFFD.
+	StringSanitizationReplace
+	// StringSanitizationStrip removes invalid UTF-8 bytes and control characters.
+	StringSanitizationStrip
+)
+
+// WithStringSanitization configures how invalid UTF-8 and control characters,
+// other than tab, line feed and carriage return, are handled in string values.
+// Keys are not affected. String values are not sanitized by default.
+func WithStringSanitization(mode StringSanitization) SerializeOption {
+	return func(cfg *serializeConfig) {
+		cfg.stringSanitization = mode
 	}
 }
 
-func newJSONVisitor(w io.Writer) *json.Visitor {
+// ArrayFormat selects how arrays of scalar values are serialized.
+type ArrayFormat uint8
+
+const (
+	// ArrayFormatArray serializes scalar arrays as JSON arrays.
+	ArrayFormatArray ArrayFormat = iota
+	// ArrayFormatJoined serializes scalar arrays as a single string holding the
+	// elements joined by commas, e.g. ["a", "b"] as "a,b".
+	ArrayFormatJoined
+)
+
+type arrayFormatRule struct {
+	format  ArrayFormat
+	pattern string
+}
+
+// WithArrayFormat serializes the scalar arrays of fields whose key matches one of
+// the given patterns in the given format. Patterns use the path.Match syntax and
+// are matched against the field keys of each document, which are the flattened
+// keys for documents created from attributes. If the option is given multiple
+// times, the first matching pattern applies. Arrays holding objects or nested
+// arrays are always serialized as JSON arrays. Scalar arrays are serialized as
+// JSON arrays by default.
+func WithArrayFormat(format ArrayFormat, patterns ...string) SerializeOption {
+	return func(cfg *serializeConfig) {
+		for _, pattern := range patterns {
+			cfg.arrayFormats = append(cfg.arrayFormats, arrayFormatRule{format: format, pattern: pattern})
+		}
+	}
+}
+
+// visitor wraps the JSON visitor with the options to apply during serialization.
+type visitor struct {
+	*json.Visitor
+	cfg serializeConfig
+	// out is the writer of the JSON visitor, which is unbuffered, for writing raw JSON.
+	out io.Writer
+}
+
+func newJSONVisitor(w io.Writer, opts ...SerializeOption) *visitor {
 	v := json.NewVisitor(w)
 	// Enable ExplicitRadixPoint such that 1.0 is encoded as 1.0 instead of 1.
 	// This is required to generate the correct dynamic mapping in ES.
 	v.SetExplicitRadixPoint(true)
-	return v
+
+	vis := &visitor{Visitor: v, out: w}
+	for _, opt := range opts {
+		opt(&vis.cfg)
+	}
+	return vis
 }
 
 // Serialize writes the document to the given writer. The document fields will be
 // deduplicated and, if dedot is true, turned into nested objects prior to
 // serialization.
-func (doc *Document) Serialize(w io.Writer, dedot bool) error {
-	doc.Dedup()
-	v := newJSONVisitor(w)
+func (doc *Document) Serialize(w io.Writer, dedot bool, opts ...SerializeOption) error {
+	v := newJSONVisitor(w, opts...)
+	doc.dedup(v.cfg, dedot)
 	return doc.iterJSON(v, dedot)
 }
 
-func (doc *Document) iterJSON(v *json.Visitor, dedot bool) error {
+// Serializer serializes documents to a writer, reusing its JSON visitor across
+// documents to reduce per-document allocations. A Serializer is not safe for
+// concurrent use.
+type Serializer struct {
+	out     writerProxy
+	visitor *visitor
+}
+
+// writerProxy allows the writer of a JSON visitor to be swapped, as the visitor
+// itself can't be reset.
+type writerProxy struct {
+	io.Writer
+}
+
+// NewSerializer creates a Serializer writing to w, applying opts to all documents.
+func NewSerializer(w io.Writer, opts ...SerializeOption) *Serializer {
+	s := &Serializer{out: writerProxy{Writer: w}}
+	s.visitor = newJSONVisitor(&s.out, opts...)
+	return s
+}
+
+// Reset makes the Serializer write subsequent documents to w.
+func (s *Serializer) Reset(w io.Writer) {
+	s.out.Writer = w
+}
+
+// Serialize writes the document to the Serializer's writer. The document is
+// deduplicated and, if dedot is true, turned into nested objects prior to
+// serialization, like Document.Serialize.
+func (s *Serializer) Serialize(doc *Document, dedot bool) error {
+	doc.dedup(s.visitor.cfg, dedot)
+	return doc.iterJSON(s.visitor, dedot)
+}
+
+func (doc *Document) iterJSON(v *visitor, dedot bool) error {
 	if dedot {
 		return doc.iterJSONDedot(v)
 	}
 	return doc.iterJSONFlat(v)
 }
 
-func (doc *Document) iterJSONFlat(w *json.Visitor) error {
+func (doc *Document) iterJSONFlat(w *visitor) error {
 	err := w.OnObjectStart(-1, structform.AnyType)
 	if err != nil {
 		return err
@@ -315,7 +940,7 @@ func (doc *Document) iterJSONFlat(w *json.Visitor) error {
 
 	for i := range doc.fields {
 		fld := &doc.fields[i]
-		if fld.value.IsEmpty() {
+		if fld.value.isOmitted(w.cfg) {
 			continue
 		}
 
@@ -323,7 +948,7 @@ func (doc *Document) iterJSONFlat(w *json.Visitor) error {
 			return err
 		}
 
-		if err := fld.value.iterJSON(w, true); err != nil {
+		if err := fld.value.iterFieldJSON(w, fld.key); err != nil {
 			return err
 		}
 	}
@@ -331,7 +956,7 @@ func (doc *Document) iterJSONFlat(w *json.Visitor) error {
 	return nil
 }
 
-func (doc *Document) iterJSONDedot(w *json.Visitor) error {
+func (doc *Document) iterJSONDedot(w *visitor) error {
 	objPrefix := ""
 	level := 0
 
@@ -344,7 +969,7 @@ func (doc *Document) iterJSONDedot(w *json.Visitor) error {
 
 	for i := range doc.fields {
 		fld := &doc.fields[i]
-		if fld.value.IsEmpty() {
+		if fld.value.isOmitted(w.cfg) {
 			continue
 		}
 
@@ -405,7 +1030,7 @@ func (doc *Document) iterJSONDedot(w *json.Visitor) error {
 		if err := w.OnKey(fieldName); err != nil {
 			return err
 		}
-		if err := fld.value.iterJSON(w, true); err != nil {
+		if err := fld.value.iterFieldJSON(w, key); err != nil {
 			return err
 		}
 	}
@@ -446,11 +1071,31 @@ func ArrValue(values ...Value) Value {
 	return Value{kind: KindArr, arr: values}
 }
 
+// SliceValue creates an array value that is backed by the attribute slice.
+// In contrast to ValueFromAttribute, the slice elements are not converted upfront, but
+// streamed one at a time to the JSON visitor when the value is serialized. This avoids
+// materializing large arrays in memory. The slice must not be modified until the
+// document has been serialized.
+func SliceValue(s pcommon.Slice) Value {
+	return Value{kind: KindArr, slice: s, lazyArr: true}
+}
+
 // TimestampValue create a new value from a time.Time.
 func TimestampValue(ts time.Time) Value {
 	return Value{kind: KindTimestamp, ts: ts}
 }
 
+// TimestampValueWithFormat creates a new value from a time.Time, which is serialized
+// in the given format.
+func TimestampValueWithFormat(ts time.Time, format TimestampFormat) Value {
+	return Value{kind: KindTimestamp, ts: ts, tsFormat: format}
+}
+
+// DurationValue creates a new value from a time.Duration.
+func DurationValue(d time.Duration) Value {
+	return Value{kind: KindDuration, dur: d}
+}
+
 // UnflattenableObjectValue creates a unflattenable object from a map
 func UnflattenableObjectValue(m pcommon.Map) Value {
 	sub := DocumentFromAttributes(m)
@@ -479,6 +1124,22 @@ func ValueFromAttribute(attr pcommon.Value) Value {
 	}
 }
 
+func (v *Value) clone() Value {
+	clone := *v
+	switch v.kind {
+	case KindObject, KindUnflattenableObject:
+		clone.doc = v.doc.clone()
+	case KindArr:
+		if v.arr != nil {
+			clone.arr = make([]Value, len(v.arr))
+			for i := range v.arr {
+				clone.arr[i] = v.arr[i].clone()
+			}
+		}
+	}
+	return clone
+}
+
 func (v *Value) sort() {
 	switch v.kind {
 	case KindObject:
@@ -493,15 +1154,75 @@ func (v *Value) sort() {
 // Dedup recursively dedups keys in stored documents.
 //
 // NOTE: The value MUST be sorted.
-func (v *Value) Dedup() {
+func (v *Value) Dedup(opts ...DedupOption) {
 	switch v.kind {
 	case KindObject:
-		v.doc.Dedup()
+		v.doc.Dedup(opts...)
 	case KindArr:
 		for i := range v.arr {
-			v.arr[i].Dedup()
+			v.arr[i].Dedup(opts...)
+		}
+	}
+}
+
+// Equal reports whether v and other are of the same kind and hold the same value.
+// Objects are equal if they have the same fields in the same order.
+func (v *Value) Equal(other Value) bool {
+	if v.kind != other.kind {
+		return false
+	}
+	switch v.kind {
+	case KindBool, KindUInt:
+		return v.ui == other.ui
+	case KindInt:
+		return v.i == other.i
+	case KindDouble:
+		return v.dbl == other.dbl
+	case KindString:
+		return v.str == other.str
+	case KindTimestamp:
+		return v.ts.Equal(other.ts) && v.tsFormat == other.tsFormat
+	case KindDuration:
+		return v.dur == other.dur
+	case KindRawJSON:
+		return bytes.Equal(v.raw, other.raw)
+	case KindArr:
+		arr, otherArr := v.values(), other.values()
+		if len(arr) != len(otherArr) {
+			return false
 		}
+		for i := range arr {
+			if !arr[i].Equal(otherArr[i]) {
+				return false
+			}
+		}
+		return true
+	case KindObject, KindUnflattenableObject:
+		if len(v.doc.fields) != len(other.doc.fields) {
+			return false
+		}
+		for i := range v.doc.fields {
+			if v.doc.fields[i].key != other.doc.fields[i].key || !v.doc.fields[i].value.Equal(other.doc.fields[i].value) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// Type returns the kind of the value.
+func (v *Value) Type() Kind {
+	return v.kind
+}
+
+// values returns the elements of an array value, converting them if the array is lazy.
+func (v *Value) values() []Value {
+	if v.lazyArr {
+		return arrFromAttributes(v.slice)
 	}
+	return v.arr
 }
 
 func (v *Value) IsEmpty() bool {
@@ -509,17 +1230,59 @@ func (v *Value) IsEmpty() bool {
 	case KindNil, KindIgnore:
 		return true
 	case KindArr:
+		if v.lazyArr {
+			return v.slice.Len() == 0
+		}
 		return len(v.arr) == 0
 	case KindObject:
 		return len(v.doc.fields) == 0
+	case KindRawJSON:
+		return len(v.raw) == 0
 	default:
 		return false
 	}
 }
 
-func (v *Value) iterJSON(w *json.Visitor, dedot bool) error {
+// isObjectArr reports whether the value is a non-empty array holding only objects.
+func (v *Value) isObjectArr() bool {
+	if v.kind != KindArr || v.IsEmpty() {
+		return false
+	}
+	if v.lazyArr {
+		for _, elem := range v.slice.All() {
+			if elem.Type() != pcommon.ValueTypeMap {
+				return false
+			}
+		}
+		return true
+	}
+	for _, elem := range v.arr {
+		if elem.kind != KindObject {
+			return false
+		}
+	}
+	return true
+}
+
+// isOmitted reports whether a field holding the value is left out when serializing.
+func (v *Value) isOmitted(cfg serializeConfig) bool {
+	if v.IsEmpty() {
+		return true
+	}
+	if !cfg.omitEmptyObjects || (v.kind != KindObject && v.kind != KindUnflattenableObject) {
+		return false
+	}
+	for i := range v.doc.fields {
+		if !v.doc.fields[i].value.isOmitted(cfg) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *Value) iterJSON(w *visitor, dedot bool) error {
 	switch v.kind {
-	case KindNil:
+	case KindNil, KindNull:
 		return w.OnNil()
 	case KindBool:
 		return w.OnBool(v.ui == 1)
@@ -532,12 +1295,19 @@ func (v *Value) iterJSON(w *json.Visitor, dedot bool) error {
 			// NaN and Inf are undefined for JSON. Let's serialize to "null"
 			return w.OnNil()
 		}
-		return w.OnFloat64(v.dbl)
+		return w.OnFloat64(w.cfg.roundDouble(v.dbl))
 	case KindString:
-		return w.OnString(v.str)
+		return w.OnString(w.cfg.formatString(v.str))
 	case KindTimestamp:
-		str := v.ts.UTC().Format(tsLayout)
-		return w.OnString(str)
+		if v.tsFormat == TimestampFormatEpochMillis {
+			return w.OnInt64(v.ts.UnixMilli())
+		}
+		return w.OnString(v.formatTimestamp())
+	case KindDuration:
+		if w.cfg.durationFormat == DurationFormatISO8601 {
+			return w.OnString(formatISO8601Duration(v.dur))
+		}
+		return w.OnInt64(v.dur.Milliseconds())
 	case KindObject:
 		if len(v.doc.fields) == 0 {
 			return w.OnNil()
@@ -548,23 +1318,341 @@ func (v *Value) iterJSON(w *json.Visitor, dedot bool) error {
 			return w.OnNil()
 		}
 		return v.doc.iterJSON(w, true)
+	case KindRawJSON:
+		// raw JSON is only added as a field value, so the key and separator
+		// have already been written by the visitor
+		_, err := w.out.Write(v.raw)
+		return err
 	case KindArr:
+		if w.cfg.mixedArrayMode != MixedArrayKeep {
+			return iterJSONMixedArr(w, v, dedot)
+		}
 		if err := w.OnArrayStart(-1, structform.AnyType); err != nil {
 			return err
 		}
-		for i := range v.arr {
-			if err := v.arr[i].iterJSON(w, dedot); err != nil {
+		if v.lazyArr {
+			if err := iterJSONSlice(w, v.slice, dedot); err != nil {
 				return err
 			}
+		} else {
+			for i := range v.arr {
+				if err := v.arr[i].iterJSON(w, dedot); err != nil {
+					return err
+				}
+			}
 		}
 		if err := w.OnArrayFinished(); err != nil {
 			return err
 		}
+	case KindIgnore:
+		// ignored values are not serialized
+	default:
+		return &UnsupportedKindError{Kind: v.kind}
 	}
 
 	return nil
 }
 
+// UnsupportedKindError is returned when serializing a value of an unknown kind,
+// which indicates a bug in the mapping that created the value.
+type UnsupportedKindError struct {
+	Kind Kind
+}
+
+func (e *UnsupportedKindError) Error() string {
+	return fmt.Sprintf("objmodel: cannot serialize value of unsupported kind %d", e.Kind)
+}
+
+// iterFieldJSON serializes the value of the field with the given key, applying the
+// ArrayFormat configured for the key.
+func (v *Value) iterFieldJSON(w *visitor, key string) error {
+	if v.kind == KindArr && w.cfg.arrayFormat(key) == ArrayFormatJoined {
+		if joined, ok := v.joinScalars(w.cfg); ok {
+			return w.OnString(w.cfg.formatString(joined))
+		}
+	}
+	return v.iterJSON(w, true)
+}
+
+// joinScalars joins the elements of an array value by commas. It returns false if
+// the array holds elements which are not scalars.
+func (v *Value) joinScalars(cfg serializeConfig) (string, bool) {
+	arr := v.values()
+	elems := make([]string, len(arr))
+	for i := range arr {
+		if arr[i].scalarType(cfg) == noScalar {
+			return "", false
+		}
+		elems[i] = arr[i].scalarString(cfg)
+	}
+	return strings.Join(elems, ","), true
+}
+
+// iterJSONSlice converts and serializes the elements of the attribute slice one at a time.
+func iterJSONSlice(w *visitor, s pcommon.Slice, dedot bool) error {
+	for _, attr := range s.All() {
+		elem := ValueFromAttribute(attr)
+		elem.Dedup()
+		if err := elem.iterJSON(w, dedot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterJSONMixedArr serializes an array value, converting its elements according
+// to the configured MixedArrayMode if the array holds scalars of different types.
+func iterJSONMixedArr(w *visitor, v *Value, dedot bool) error {
+	arr := v.arr
+	if v.lazyArr {
+		arr = arrFromAttributes(v.slice)
+		for i := range arr {
+			arr[i].Dedup()
+		}
+	}
+	if isMixedArr(arr, w.cfg) {
+		arr = convertMixedArr(arr, w.cfg)
+	}
+
+	if err := w.OnArrayStart(-1, structform.AnyType); err != nil {
+		return err
+	}
+	for i := range arr {
+		if err := arr[i].iterJSON(w, dedot); err != nil {
+			return err
+		}
+	}
+	return w.OnArrayFinished()
+}
+
+// scalarType groups value kinds by the JSON type they are serialized as.
+type scalarType uint8
+
+const (
+	noScalar scalarType = iota
+	boolScalar
+	numberScalar
+	stringScalar
+)
+
+func (v *Value) scalarType(cfg serializeConfig) scalarType {
+	switch v.kind {
+	case KindBool:
+		return boolScalar
+	case KindInt, KindUInt, KindDouble:
+		return numberScalar
+	case KindString:
+		return stringScalar
+	case KindTimestamp:
+		if v.tsFormat == TimestampFormatEpochMillis {
+			return numberScalar
+		}
+		return stringScalar
+	case KindDuration:
+		if cfg.durationFormat == DurationFormatISO8601 {
+			return stringScalar
+		}
+		return numberScalar
+	default:
+		return noScalar
+	}
+}
+
+// formatTimestamp formats a timestamp value as an ISO 8601 string with the precision
+// of its format.
+func (v *Value) formatTimestamp() string {
+	if v.tsFormat == TimestampFormatMillis {
+		return v.ts.UTC().Format(tsLayoutMillis)
+	}
+	return v.ts.UTC().Format(tsLayout)
+}
+
+// scalarString formats a scalar value as a string.
+func (v *Value) scalarString(cfg serializeConfig) string {
+	switch v.kind {
+	case KindBool:
+		return strconv.FormatBool(v.ui == 1)
+	case KindInt:
+		return strconv.FormatInt(v.i, 10)
+	case KindUInt:
+		return strconv.FormatUint(v.ui, 10)
+	case KindDouble:
+		return strconv.FormatFloat(cfg.roundDouble(v.dbl), 'g', -1, 64)
+	case KindTimestamp:
+		if v.tsFormat == TimestampFormatEpochMillis {
+			return strconv.FormatInt(v.ts.UnixMilli(), 10)
+		}
+		return v.formatTimestamp()
+	case KindDuration:
+		if cfg.durationFormat == DurationFormatISO8601 {
+			return formatISO8601Duration(v.dur)
+		}
+		return strconv.FormatInt(v.dur.Milliseconds(), 10)
+	default:
+		return v.str
+	}
+}
+
+func isMixedArr(arr []Value, cfg serializeConfig) bool {
+	first := noScalar
+	for i := range arr {
+		typ := arr[i].scalarType(cfg)
+		if typ == noScalar {
+			continue
+		}
+		if first == noScalar {
+			first = typ
+		} else if typ != first {
+			return true
+		}
+	}
+	return false
+}
+
+// convertMixedArr returns a copy of the mixed array with its scalar elements
+// converted according to the configured MixedArrayMode.
+func convertMixedArr(arr []Value, cfg serializeConfig) []Value {
+	converted := make([]Value, 0, len(arr))
+	first := noScalar
+	for i := range arr {
+		typ := arr[i].scalarType(cfg)
+		switch {
+		case typ == noScalar:
+			converted = append(converted, arr[i])
+		case cfg.mixedArrayMode == MixedArrayAsStrings:
+			converted = append(converted, StringValue(arr[i].scalarString(cfg)))
+		case first == noScalar || typ == first:
+			first = typ
+			converted = append(converted, arr[i])
+		}
+	}
+	return converted
+}
+
+// arrayFormat returns the ArrayFormat of the arrays of fields with the given key.
+func (cfg serializeConfig) arrayFormat(key string) ArrayFormat {
+	for _, rule := range cfg.arrayFormats {
+		if ok, _ := path.Match(rule.pattern, key); ok {
+			return rule.format
+		}
+	}
+	return ArrayFormatArray
+}
+
+// roundDouble rounds d to the configured maximum number of significant digits.
+func (cfg serializeConfig) roundDouble(d float64) float64 {
+	if cfg.maxDoubleDigits <= 0 {
+		return d
+	}
+	// formatting to the number of digits rounds to the nearest decimal, parsing
+	// it back yields the closest double, which is serialized without extra digits
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(d, 'g', cfg.maxDoubleDigits, 64), 64)
+	if err != nil {
+		return d
+	}
+	return rounded
+}
+
+// formatString sanitizes and truncates a string value according to the configuration.
+func (cfg serializeConfig) formatString(str string) string {
+	return cfg.truncateString(cfg.sanitizeString(str))
+}
+
+// sanitizeString replaces or removes the invalid UTF-8 bytes and control
+// characters of str according to the configured StringSanitization.
+func (cfg serializeConfig) sanitizeString(str string) string {
+	if cfg.stringSanitization == StringSanitizationOff || !needsSanitization(str) {
+		return str
+	}
+	var sb strings.Builder
+	sb.Grow(len(str))
+	for i, r := range str {
+		invalid := r == utf8.RuneError && !strings.HasPrefix(str[i:], string(utf8.RuneError))
+		if !invalid && !isSanitizedControl(r) {
+			sb.WriteRune(r)
+			continue
+		}
+		if cfg.stringSanitization == StringSanitizationReplace {
+			sb.WriteRune(utf8.RuneError)
+		}
+	}
+	return sb.String()
+}
+
+// needsSanitization reports whether str holds invalid UTF-8 or control characters.
+func needsSanitization(str string) bool {
+	if !utf8.ValidString(str) {
+		return true
+	}
+	for _, r := range str {
+		if isSanitizedControl(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSanitizedControl reports whether r is a control character removed by
+// sanitization. Tab, line feed and carriage return are kept.
+func isSanitizedControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r'
+}
+
+// truncateString cuts str to the configured maximum number of characters,
+// appending the truncation marker if there's room for it.
+func (cfg serializeConfig) truncateString(str string) string {
+	if cfg.maxStringLength <= 0 || len(str) <= cfg.maxStringLength {
+		return str
+	}
+	if utf8.RuneCountInString(str) <= cfg.maxStringLength {
+		return str
+	}
+	keep := cfg.maxStringLength
+	marker := cfg.truncateMarker
+	if markerLen := utf8.RuneCountInString(marker); markerLen < keep {
+		keep -= markerLen
+	} else {
+		marker = ""
+	}
+	for i := range str {
+		if keep == 0 {
+			return str[:i] + marker
+		}
+		keep--
+	}
+	return str
+}
+
+// formatISO8601Duration formats d as an ISO 8601 duration using hours, minutes
+// and (fractional) seconds, e.g. PT1H2M3.5S.
+func formatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var sb strings.Builder
+	if d < 0 {
+		sb.WriteByte('-')
+		d = -d
+	}
+	sb.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		sb.WriteString(strconv.FormatInt(int64(h), 10))
+		sb.WriteByte('H')
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		sb.WriteString(strconv.FormatInt(int64(m), 10))
+		sb.WriteByte('M')
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		sb.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+		sb.WriteByte('S')
+	}
+	return sb.String()
+}
+
 func arrFromAttributes(aa pcommon.Slice) []Value {
 	if aa.Len() == 0 {
 		return nil
@@ -577,20 +1665,29 @@ func arrFromAttributes(aa pcommon.Slice) []Value {
 	return values
 }
 
-func appendAttributeFields(fields []field, path string, am pcommon.Map) []field {
+func appendAttributeFields(fields []field, path string, am pcommon.Map, cfg documentConfig) []field {
 	for k, val := range am.All() {
-		fields = appendAttributeValue(fields, path, k, val)
+		fields = appendAttributeValue(fields, path, k, val, cfg)
 	}
 	return fields
 }
 
-func appendAttributeValue(fields []field, path, key string, attr pcommon.Value) []field {
+func appendAttributeValue(fields []field, path, key string, attr pcommon.Value, cfg documentConfig) []field {
 	if attr.Type() == pcommon.ValueTypeEmpty {
+		if cfg.keepNulls {
+			return append(fields, field{key: flattenKey(path, key), value: nullValue})
+		}
 		return fields
 	}
 
+	if cfg.isGeoPointKey(flattenKey(path, key)) {
+		if geoPoint, ok := geoPointValue(attr); ok {
+			return append(fields, field{key: flattenKey(path, key), value: geoPoint})
+		}
+	}
+
 	if attr.Type() == pcommon.ValueTypeMap {
-		return appendAttributeFields(fields, flattenKey(path, key), attr.Map())
+		return appendAttributeFields(fields, flattenKey(path, key), attr.Map(), cfg)
 	}
 
 	return append(fields, field{
@@ -599,6 +1696,69 @@ func appendAttributeValue(fields []field, path, key string, attr pcommon.Value)
 	})
 }
 
+func (cfg documentConfig) isGeoPointKey(key string) bool {
+	for _, pattern := range cfg.geoPointPatterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// geoPointValue converts a map with lat and lon entries or a "lat,lon" string
+// into a geo_point object. It returns false if the attribute holds no valid
+// coordinates.
+func geoPointValue(attr pcommon.Value) (Value, bool) {
+	var lat, lon float64
+	switch attr.Type() {
+	case pcommon.ValueTypeMap:
+		var latOK, lonOK bool
+		if v, ok := attr.Map().Get("lat"); ok {
+			lat, latOK = numericAttribute(v)
+		}
+		if v, ok := attr.Map().Get("lon"); ok {
+			lon, lonOK = numericAttribute(v)
+		}
+		if !latOK || !lonOK || attr.Map().Len() != 2 {
+			return Value{}, false
+		}
+	case pcommon.ValueTypeStr:
+		latStr, lonStr, found := strings.Cut(attr.Str(), ",")
+		if !found {
+			return Value{}, false
+		}
+		var err error
+		if lat, err = strconv.ParseFloat(strings.TrimSpace(latStr), 64); err != nil {
+			return Value{}, false
+		}
+		if lon, err = strconv.ParseFloat(strings.TrimSpace(lonStr), 64); err != nil {
+			return Value{}, false
+		}
+	default:
+		return Value{}, false
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return Value{}, false
+	}
+
+	// the geo_point object must not be flattened into separate lat and lon fields
+	return Value{kind: KindUnflattenableObject, doc: Document{fields: []field{
+		{key: "lat", value: DoubleValue(lat)},
+		{key: "lon", value: DoubleValue(lon)},
+	}}}, true
+}
+
+func numericAttribute(v pcommon.Value) (float64, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeDouble:
+		return v.Double(), true
+	case pcommon.ValueTypeInt:
+		return float64(v.Int()), true
+	default:
+		return 0, false
+	}
+}
+
 func flattenKey(path, key string) string {
 	if path == "" {
 		return key
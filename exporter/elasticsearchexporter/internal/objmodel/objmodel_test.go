@@ -4,6 +4,9 @@
 package objmodel
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"math"
 	"strings"
 	"testing"
@@ -12,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 var dijkstra = time.Date(1930, 5, 11, 16, 33, 11, 123456789, time.UTC)
@@ -44,6 +48,16 @@ func TestObjectModel_CreateMap(t *testing.T) {
 			},
 			want: Document{fields: []field{{"str", StringValue("test")}}},
 		},
+		"keeps nil values as null": {
+			build: func() Document {
+				m := pcommon.NewMap()
+				m.PutEmpty("null")
+				m.PutStr("str", "test")
+				m.PutEmptyMap("nested").PutEmpty("null")
+				return DocumentFromAttributes(m, WithNullValues())
+			},
+			want: Document{fields: []field{{"null", nullValue}, {"str", StringValue("test")}, {"nested.null", nullValue}}},
+		},
 		"from map with prefix": {
 			build: func() Document {
 				m := pcommon.NewMap()
@@ -84,6 +98,275 @@ func TestObjectModel_CreateMap(t *testing.T) {
 	}
 }
 
+func TestDocument_Clone(t *testing.T) {
+	var nested Document
+	nested.AddString("a", "b")
+
+	var doc Document
+	doc.AddString("str", "test")
+	doc.Add("obj", Value{kind: KindObject, doc: nested})
+	doc.Add("arr", ArrValue(IntValue(1), Value{kind: KindObject, doc: nested.clone()}))
+	doc.AddDynamicTemplate("str", "keyword")
+
+	clone := doc.Clone()
+	assert.Equal(t, doc, *clone)
+
+	clone.AddInt("i", 42)
+	clone.fields[0].value = StringValue("changed")
+	clone.fields[1].value.doc.fields[0].value = StringValue("changed")
+	clone.fields[2].value.arr[0] = IntValue(2)
+	clone.fields[2].value.arr[1].doc.AddString("c", "d")
+	clone.AddDynamicTemplate("i", "long")
+
+	var original Document
+	original.AddString("str", "test")
+	original.Add("obj", Value{kind: KindObject, doc: nested})
+	original.Add("arr", ArrValue(IntValue(1), Value{kind: KindObject, doc: nested}))
+	original.AddDynamicTemplate("str", "keyword")
+	assert.Equal(t, original, doc)
+}
+
+func TestDocument_LenRange(t *testing.T) {
+	var doc Document
+	assert.Equal(t, 0, doc.Len())
+	doc.Range(func(string, Value) bool {
+		t.Fatal("unexpected field in empty document")
+		return true
+	})
+
+	doc.AddString("str", "test")
+	doc.AddInt("int", 42)
+	doc.AddString("str", "again")
+	doc.Add("arr", ArrValue(IntValue(1)))
+	assert.Equal(t, 4, doc.Len())
+
+	var keys []string
+	var values []Value
+	doc.Range(func(key string, v Value) bool {
+		keys = append(keys, key)
+		values = append(values, v)
+		return true
+	})
+	assert.Equal(t, []string{"str", "int", "str", "arr"}, keys)
+	assert.Equal(t, []Value{StringValue("test"), IntValue(42), StringValue("again"), ArrValue(IntValue(1))}, values)
+
+	keys = nil
+	doc.Range(func(key string, _ Value) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	assert.Equal(t, []string{"str", "int"}, keys)
+}
+
+func TestDocumentFromSpan(t *testing.T) {
+	span := ptrace.NewSpan()
+	span.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	span.SetName("GET /users")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(dijkstra))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(dijkstra.Add(time.Second)))
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Status().SetMessage("not found")
+	span.Attributes().PutStr("http.method", "GET")
+	span.Attributes().PutEmptyMap("http").PutInt("status_code", 404)
+
+	tests := map[string]struct {
+		opts []SpanDocumentOption
+		want func() Document
+	}{
+		"default keys": {
+			want: func() Document {
+				var doc Document
+				doc.AddTimestamp("@timestamp", pcommon.NewTimestampFromTime(dijkstra))
+				doc.AddTimestamp("EndTimestamp", pcommon.NewTimestampFromTime(dijkstra.Add(time.Second)))
+				doc.AddString("TraceId", "0102030405060708090a0b0c0d0e0f10")
+				doc.AddString("SpanId", "0102030405060708")
+				doc.AddString("ParentSpanId", "0807060504030201")
+				doc.AddString("Name", "GET /users")
+				doc.AddString("Kind", "SPAN_KIND_SERVER")
+				doc.AddInt("TraceStatus", 2)
+				doc.AddString("TraceStatusDescription", "not found")
+				doc.AddString("Attributes.http.method", "GET")
+				doc.AddInt("Attributes.http.status_code", 404)
+				return doc
+			},
+		},
+		"custom keys": {
+			opts: []SpanDocumentOption{WithSpanKeys(SpanKeys{
+				TraceID:        "trace.id",
+				SpanID:         "span.id",
+				Name:           "span.name",
+				StartTimestamp: "timestamp",
+				StatusCode:     "status.code",
+				Attributes:     "",
+			})},
+			want: func() Document {
+				var doc Document
+				doc.AddTimestamp("timestamp", pcommon.NewTimestampFromTime(dijkstra))
+				doc.AddString("trace.id", "0102030405060708090a0b0c0d0e0f10")
+				doc.AddString("span.id", "0102030405060708")
+				doc.AddString("span.name", "GET /users")
+				doc.AddInt("status.code", 2)
+				return doc
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want(), DocumentFromSpan(span, test.opts...))
+		})
+	}
+}
+
+func TestDocumentFromSpan_EmptyFields(t *testing.T) {
+	doc := DocumentFromSpan(ptrace.NewSpan())
+
+	var buf strings.Builder
+	require.NoError(t, doc.Serialize(&buf, false))
+	assert.Equal(t, `{"@timestamp":"1970-01-01T00:00:00.000000000Z","EndTimestamp":"1970-01-01T00:00:00.000000000Z","Kind":"SPAN_KIND_UNSPECIFIED","TraceStatus":0}`, buf.String())
+}
+
+func TestDocumentFromSpan_TimestampKeyAndFormat(t *testing.T) {
+	span := ptrace.NewSpan()
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(dijkstra))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(dijkstra.Add(1500 * time.Microsecond)))
+
+	tests := map[string]struct {
+		opts []SpanDocumentOption
+		want string
+	}{
+		"default format": {
+			want: `{"@timestamp":"1930-05-11T16:33:11.123456789Z","end":"1930-05-11T16:33:11.124956789Z"}`,
+		},
+		"custom key": {
+			opts: []SpanDocumentOption{WithSpanKeys(SpanKeys{StartTimestamp: "timestamp", EndTimestamp: "end"})},
+			want: `{"end":"1930-05-11T16:33:11.124956789Z","timestamp":"1930-05-11T16:33:11.123456789Z"}`,
+		},
+		"millis": {
+			opts: []SpanDocumentOption{WithSpanTimestampFormat(TimestampFormatMillis)},
+			want: `{"@timestamp":"1930-05-11T16:33:11.123Z","end":"1930-05-11T16:33:11.124Z"}`,
+		},
+		"epoch millis": {
+			opts: []SpanDocumentOption{WithSpanTimestampFormat(TimestampFormatEpochMillis)},
+			want: `{"@timestamp":-1251012408877,"end":-1251012408876}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := append([]SpanDocumentOption{WithSpanKeys(SpanKeys{StartTimestamp: DefaultTimestampKey, EndTimestamp: "end"})}, test.opts...)
+			doc := DocumentFromSpan(span, opts...)
+
+			var buf strings.Builder
+			require.NoError(t, doc.Serialize(&buf, false))
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocumentFromAttributes_TimestampKeyAndFormat(t *testing.T) {
+	ts := pcommon.NewTimestampFromTime(dijkstra)
+	am := pcommon.NewMap()
+	am.PutStr("a", "1")
+
+	tests := map[string]struct {
+		attrs pcommon.Map
+		path  string
+		opts  []DocumentOption
+		want  string
+	}{
+		"no timestamp": {
+			attrs: am,
+			want:  `{"a":"1"}`,
+		},
+		"default key and format": {
+			attrs: am,
+			opts:  []DocumentOption{WithTimestamp(ts)},
+			want:  `{"@timestamp":"1930-05-11T16:33:11.123456789Z","a":"1"}`,
+		},
+		"custom key": {
+			attrs: am,
+			opts:  []DocumentOption{WithTimestamp(ts), WithTimestampKey("timestamp")},
+			want:  `{"a":"1","timestamp":"1930-05-11T16:33:11.123456789Z"}`,
+		},
+		"key is not prefixed with path": {
+			attrs: am,
+			path:  "attributes",
+			opts:  []DocumentOption{WithTimestamp(ts), WithTimestampKey("timestamp")},
+			want:  `{"attributes":{"a":"1"},"timestamp":"1930-05-11T16:33:11.123456789Z"}`,
+		},
+		"millis": {
+			attrs: am,
+			opts:  []DocumentOption{WithTimestamp(ts), WithTimestampFormat(TimestampFormatMillis)},
+			want:  `{"@timestamp":"1930-05-11T16:33:11.123Z","a":"1"}`,
+		},
+		"epoch millis": {
+			attrs: am,
+			opts:  []DocumentOption{WithTimestamp(ts), WithTimestampFormat(TimestampFormatEpochMillis)},
+			want:  `{"@timestamp":-1251012408877,"a":"1"}`,
+		},
+		"empty attributes": {
+			attrs: pcommon.NewMap(),
+			opts:  []DocumentOption{WithTimestamp(ts), WithTimestampKey("timestamp"), WithTimestampFormat(TimestampFormatMillis)},
+			want:  `{"timestamp":"1930-05-11T16:33:11.123Z"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := DocumentFromAttributesWithPath(test.path, test.attrs, test.opts...)
+
+			var buf strings.Builder
+			require.NoError(t, doc.Serialize(&buf, true))
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_AddTimestampWithFormat(t *testing.T) {
+	ts := pcommon.NewTimestampFromTime(time.Date(2024, 3, 1, 12, 30, 45, 123456789, time.UTC))
+
+	var doc Document
+	doc.AddTimestamp("default", ts)
+	doc.AddTimestampWithFormat("nanos", ts, TimestampFormatNanos)
+	doc.AddTimestampWithFormat("millis", ts, TimestampFormatMillis)
+	doc.AddTimestampWithFormat("epoch", ts, TimestampFormatEpochMillis)
+	doc.Add("mixed", ArrValue(StringValue("a"), TimestampValueWithFormat(ts.AsTime(), TimestampFormatEpochMillis)))
+
+	var buf strings.Builder
+	require.NoError(t, doc.Serialize(&buf, false, WithMixedArrayMode(MixedArrayAsStrings)))
+	assert.Equal(t, `{"default":"2024-03-01T12:30:45.123456789Z","epoch":1709296245123,"millis":"2024-03-01T12:30:45.123Z","mixed":["a","1709296245123"],"nanos":"2024-03-01T12:30:45.123456789Z"}`, buf.String())
+}
+
+func TestDocument_StripPrefix(t *testing.T) {
+	am := pcommon.NewMap()
+	am.PutStr("a", "1")
+	am.PutEmptyMap("b").PutStr("c", "2")
+
+	doc := DocumentFromAttributesWithPath("attributes", am)
+	doc.AddString("attributes", "no separator")
+	doc.AddString("attributesx.d", "other prefix")
+	doc.AddString("resource.attributes.e", "nested prefix")
+	doc.AddString("attributes.", "empty key")
+
+	doc.StripPrefix("attributes")
+	assert.Equal(t, []field{
+		{"a", StringValue("1")},
+		{"b.c", StringValue("2")},
+		{"attributes", StringValue("no separator")},
+		{"attributesx.d", StringValue("other prefix")},
+		{"resource.attributes.e", StringValue("nested prefix")},
+		{"attributes.", StringValue("empty key")},
+	}, doc.fields)
+
+	roundTrip := DocumentFromAttributesWithPath("attributes", am)
+	roundTrip.StripPrefix("attributes")
+	assert.Equal(t, DocumentFromAttributes(am), roundTrip)
+}
+
 func TestObjectModel_Dedup(t *testing.T) {
 	tests := map[string]struct {
 		build func() Document
@@ -142,6 +425,25 @@ func TestObjectModel_Dedup(t *testing.T) {
 				{"c", IntValue(3)},
 			}}})}}},
 		},
+		"dedup in nested arrays": {
+			build: func() (doc Document) {
+				var embedded Document
+				embedded.AddInt("a", 1)
+				embedded.AddInt("c", 3)
+				embedded.AddInt("a", 2)
+
+				doc.Add("arr", ArrValue(ArrValue(Value{kind: KindObject, doc: embedded}), ArrValue(IntValue(1))))
+				return doc
+			},
+			want: Document{fields: []field{{"arr", ArrValue(
+				ArrValue(Value{kind: KindObject, doc: Document{fields: []field{
+					{"a", ignoreValue},
+					{"a", IntValue(2)},
+					{"c", IntValue(3)},
+				}}}),
+				ArrValue(IntValue(1)),
+			)}}},
+		},
 		"dedup mix of primitive and object lifts primitive": {
 			build: func() (doc Document) {
 				doc.AddInt("namespace", 1)
@@ -170,6 +472,200 @@ func TestObjectModel_Dedup(t *testing.T) {
 	}
 }
 
+func TestObjectModel_DedupDropExactDuplicates(t *testing.T) {
+	tests := map[string]struct {
+		build func() Document
+		want  Document
+	}{
+		"identical duplicates": {
+			build: func() (doc Document) {
+				doc.AddInt("a", 1)
+				doc.AddInt("c", 3)
+				doc.AddInt("a", 1)
+				return doc
+			},
+			want: Document{fields: []field{{"a", IntValue(1)}, {"c", IntValue(3)}}},
+		},
+		"differing duplicates": {
+			build: func() (doc Document) {
+				doc.AddInt("a", 1)
+				doc.AddInt("c", 3)
+				doc.AddInt("a", 2)
+				return doc
+			},
+			want: Document{fields: []field{{"a", ignoreValue}, {"a", IntValue(2)}, {"c", IntValue(3)}}},
+		},
+		"identical and differing duplicates": {
+			build: func() (doc Document) {
+				doc.AddString("a", "x")
+				doc.AddString("a", "x")
+				doc.AddString("a", "y")
+				return doc
+			},
+			want: Document{fields: []field{{"a", ignoreValue}, {"a", StringValue("y")}}},
+		},
+		"same value of different kind": {
+			build: func() (doc Document) {
+				doc.AddInt("a", 1)
+				doc.AddUInt("a", 1)
+				return doc
+			},
+			want: Document{fields: []field{{"a", ignoreValue}, {"a", UIntValue(1)}}},
+		},
+		"identical duplicates in arrays": {
+			build: func() (doc Document) {
+				var embedded Document
+				embedded.AddInt("a", 1)
+				embedded.AddInt("a", 1)
+
+				doc.Add("arr", ArrValue(Value{kind: KindObject, doc: embedded}))
+				return doc
+			},
+			want: Document{fields: []field{{"arr", ArrValue(Value{kind: KindObject, doc: Document{fields: []field{
+				{"a", IntValue(1)},
+			}}})}}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := test.build()
+			doc.Dedup(WithDropExactDuplicates())
+			assert.Equal(t, test.want, doc)
+		})
+	}
+}
+
+func TestDocument_Dedup_CaseInsensitiveKeys(t *testing.T) {
+	build := func() (doc Document) {
+		doc.AddString("host", "a")
+		doc.AddString("Host", "b")
+		doc.AddString("Service.Name", "c")
+		doc.AddString("service.name", "d")
+
+		var embedded Document
+		embedded.AddInt("Port", 1)
+		embedded.AddInt("port", 2)
+		doc.Add("arr", ArrValue(Value{kind: KindObject, doc: embedded}))
+		return doc
+	}
+
+	tests := map[string]struct {
+		opts []DedupOption
+		want Document
+	}{
+		"case-sensitive": {
+			want: Document{fields: []field{
+				{"Host", StringValue("b")},
+				{"Service.Name", StringValue("c")},
+				{"arr", ArrValue(Value{kind: KindObject, doc: Document{fields: []field{
+					{"Port", IntValue(1)},
+					{"port", IntValue(2)},
+				}}})},
+				{"host", StringValue("a")},
+				{"service.name", StringValue("d")},
+			}},
+		},
+		"case-insensitive": {
+			opts: []DedupOption{WithCaseInsensitiveKeys()},
+			want: Document{fields: []field{
+				{"Host", StringValue("b")},
+				{"Service.Name", ignoreValue},
+				{"arr", ArrValue(Value{kind: KindObject, doc: Document{fields: []field{
+					{"Port", ignoreValue},
+					{"port", IntValue(2)},
+				}}})},
+				{"host", ignoreValue},
+				{"service.name", StringValue("d")},
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := build()
+			doc.Dedup(test.opts...)
+			assert.Equal(t, test.want, doc)
+		})
+	}
+}
+
+func TestDocument_Dedup_CaseInsensitiveKeysKeepsCase(t *testing.T) {
+	var doc Document
+	doc.AddString("Host", "a")
+	doc.AddString("Service.Name", "b")
+	doc.AddString("service.Version", "c")
+	doc.AddString("User.name", "d")
+	doc.AddString("user.Name", "e")
+	doc.Dedup(WithCaseInsensitiveKeys())
+
+	var buf strings.Builder
+	require.NoError(t, doc.Serialize(&buf, true))
+	assert.Equal(t, `{"Host":"a","Service":{"Name":"b"},"service":{"Version":"c"},"user":{"Name":"e"}}`, buf.String())
+}
+
+func TestValue_Equal(t *testing.T) {
+	m := pcommon.NewMap()
+	require.NoError(t, m.FromRaw(map[string]any{"a": "b"}))
+	s := pcommon.NewSlice()
+	require.NoError(t, s.FromRaw([]any{1, "two"}))
+	ts := time.Unix(1, 0)
+
+	tests := map[string]struct {
+		a, b  Value
+		equal bool
+	}{
+		"equal strings":         {a: StringValue("a"), b: StringValue("a"), equal: true},
+		"different strings":     {a: StringValue("a"), b: StringValue("b")},
+		"different kinds":       {a: IntValue(1), b: DoubleValue(1)},
+		"equal bools":           {a: BoolValue(true), b: BoolValue(true), equal: true},
+		"equal timestamps":      {a: TimestampValue(ts), b: TimestampValue(ts.In(time.FixedZone("x", 3600))), equal: true},
+		"different durations":   {a: DurationValue(time.Second), b: DurationValue(time.Minute)},
+		"equal arrays":          {a: ArrValue(IntValue(1), StringValue("two")), b: ArrValue(IntValue(1), StringValue("two")), equal: true},
+		"lazy and eager array":  {a: SliceValue(s), b: ArrValue(IntValue(1), StringValue("two")), equal: true},
+		"different arrays":      {a: ArrValue(IntValue(1)), b: ArrValue(IntValue(1), IntValue(2))},
+		"equal objects":         {a: ValueFromAttribute(pcommon.NewValueMap()), b: ValueFromAttribute(pcommon.NewValueMap()), equal: true},
+		"unflattenable objects": {a: UnflattenableObjectValue(m), b: UnflattenableObjectValue(m), equal: true},
+		"nil values":            {a: nilValue, b: nilValue, equal: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.equal, test.a.Equal(test.b))
+			assert.Equal(t, test.equal, test.b.Equal(test.a))
+		})
+	}
+}
+
+func TestValue_Type(t *testing.T) {
+	m := pcommon.NewMap()
+	require.NoError(t, m.FromRaw(map[string]any{"a": "b"}))
+
+	tests := map[string]struct {
+		value Value
+		want  Kind
+	}{
+		"string":               {value: StringValue("a"), want: KindString},
+		"int":                  {value: IntValue(1), want: KindInt},
+		"uint":                 {value: UIntValue(1), want: KindUInt},
+		"double":               {value: DoubleValue(1.5), want: KindDouble},
+		"bool":                 {value: BoolValue(true), want: KindBool},
+		"array":                {value: ArrValue(IntValue(1)), want: KindArr},
+		"slice":                {value: SliceValue(pcommon.NewSlice()), want: KindArr},
+		"timestamp":            {value: TimestampValue(time.Unix(1, 0)), want: KindTimestamp},
+		"duration":             {value: DurationValue(time.Second), want: KindDuration},
+		"unflattenable object": {value: UnflattenableObjectValue(m), want: KindUnflattenableObject},
+		"object":               {value: ValueFromAttribute(pcommon.NewValueMap()), want: KindObject},
+		"empty attribute":      {value: ValueFromAttribute(pcommon.NewValueEmpty()), want: KindNil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.value.Type())
+		})
+	}
+}
+
 func TestValue_FromAttribute(t *testing.T) {
 	tests := map[string]struct {
 		in   pcommon.Value
@@ -208,6 +704,17 @@ func TestValue_FromAttribute(t *testing.T) {
 			}(),
 			want: ArrValue(IntValue(1)),
 		},
+		"nested array": {
+			in: func() pcommon.Value {
+				v := pcommon.NewValueSlice()
+				inner := v.Slice().AppendEmpty().SetEmptySlice()
+				inner.AppendEmpty().SetInt(1)
+				inner.AppendEmpty().SetStr("a")
+				v.Slice().AppendEmpty().SetEmptySlice()
+				return v
+			}(),
+			want: ArrValue(ArrValue(IntValue(1), StringValue("a")), Value{kind: KindArr}),
+		},
 		"empty map": {
 			in:   pcommon.NewValueMap(),
 			want: Value{kind: KindObject},
@@ -375,6 +882,10 @@ func TestValue_Serialize(t *testing.T) {
 			value: ArrValue(BoolValue(true), IntValue(23)),
 			want:  `[true,23]`,
 		},
+		"nested array": {
+			value: ArrValue(ArrValue(IntValue(1), IntValue(2)), ArrValue(), ArrValue(ArrValue(StringValue("x")))),
+			want:  `[[1,2],[],[["x"]]]`,
+		},
 		"object": {
 			value: func() Value {
 				doc := Document{}
@@ -398,3 +909,769 @@ func TestValue_Serialize(t *testing.T) {
 		})
 	}
 }
+
+func TestDocument_Serialize_NullValues(t *testing.T) {
+	m := pcommon.NewMap()
+	m.PutEmpty("a")
+	m.PutStr("b", "test")
+
+	tests := map[string]struct {
+		opts []DocumentOption
+		want string
+	}{
+		"dropped by default": {
+			want: `{"b":"test"}`,
+		},
+		"with null values": {
+			opts: []DocumentOption{WithNullValues()},
+			want: `{"a":null,"b":"test"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := DocumentFromAttributes(m, test.opts...)
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, false)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_NestedArrays(t *testing.T) {
+	build := func() Document {
+		am := pcommon.NewMap()
+		matrix := am.PutEmptySlice("matrix")
+		for _, row := range [][]int64{{1, 2}, {3, 4}} {
+			inner := matrix.AppendEmpty().SetEmptySlice()
+			for _, v := range row {
+				inner.AppendEmpty().SetInt(v)
+			}
+		}
+
+		objs := am.PutEmptySlice("objs").AppendEmpty().SetEmptySlice()
+		obj := objs.AppendEmpty().SetEmptyMap()
+		obj.PutInt("b", 1)
+		obj.PutInt("a.x", 2)
+		obj.PutEmptyMap("a").PutInt("x", 3)
+		objs.AppendEmpty().SetEmptySlice()
+		return DocumentFromAttributes(am)
+	}
+
+	tests := map[string]struct {
+		dedot bool
+		want  string
+	}{
+		"flat": {
+			want: `{"matrix":[[1,2],[3,4]],"objs":[[{"a":{"x":3},"b":1},[]]]}`,
+		},
+		"dedot": {
+			dedot: true,
+			want:  `{"matrix":[[1,2],[3,4]],"objs":[[{"a":{"x":3},"b":1},[]]]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := build()
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, test.dedot)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_OmitEmptyObjects(t *testing.T) {
+	tests := map[string]struct {
+		dedot bool
+		opts  []SerializeOption
+		want  string
+	}{
+		"null by default": {
+			want: `{"a":"x","arr":[null],"empty":null,"nested":{"inner":null},"obj.empty":null}`,
+		},
+		"null by default dedot": {
+			dedot: true,
+			want:  `{"a":"x","arr":[null],"empty":null,"nested":{"inner":null},"obj":{"empty":null}}`,
+		},
+		"omitted": {
+			opts: []SerializeOption{WithOmitEmptyObjects()},
+			want: `{"a":"x","arr":[null]}`,
+		},
+		"omitted dedot": {
+			dedot: true,
+			opts:  []SerializeOption{WithOmitEmptyObjects()},
+			want:  `{"a":"x","arr":[null]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var doc Document
+			doc.AddString("a", "x")
+			doc.Add("empty", Value{kind: KindUnflattenableObject})
+			doc.Add("nested", Value{kind: KindObject, doc: Document{fields: []field{
+				{"inner", Value{kind: KindUnflattenableObject}},
+			}}})
+			doc.Add("obj.empty", Value{kind: KindUnflattenableObject})
+			doc.Add("arr", ArrValue(Value{kind: KindObject}))
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, test.dedot, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_IndexedObjectArrays(t *testing.T) {
+	object := func(build func(doc *Document)) Value {
+		var doc Document
+		build(&doc)
+		return Value{kind: KindObject, doc: doc}
+	}
+	build := func() Document {
+		var doc Document
+		doc.Add("arr", ArrValue(
+			object(func(doc *Document) {
+				doc.AddInt("a", 1)
+				doc.AddString("b", "x")
+				doc.Add("n", ArrValue(object(func(doc *Document) { doc.AddInt("d", 1) })))
+			}),
+			object(func(doc *Document) { doc.AddInt("a", 2) }),
+		))
+		doc.Add("scalars", ArrValue(IntValue(1), IntValue(2)))
+		doc.Add("mixed", ArrValue(object(func(doc *Document) { doc.AddInt("a", 1) }), IntValue(3)))
+		s := pcommon.NewSlice()
+		s.AppendEmpty().SetEmptyMap().PutBool("c", true)
+		doc.Add("lazy", SliceValue(s))
+		return doc
+	}
+
+	tests := map[string]struct {
+		build func() Document
+		dedot bool
+		opts  []SerializeOption
+		want  string
+	}{
+		"arrays by default": {
+			build: build,
+			want:  `{"arr":[{"a":1,"b":"x","n":[{"d":1}]},{"a":2}],"lazy":[{"c":true}],"mixed":[{"a":1},3],"scalars":[1,2]}`,
+		},
+		"indexed": {
+			build: build,
+			opts:  []SerializeOption{WithIndexedObjectArrays()},
+			want:  `{"arr.0.a":1,"arr.0.b":"x","arr.0.n.0.d":1,"arr.1.a":2,"lazy.0.c":true,"mixed":[{"a":1},3],"scalars":[1,2]}`,
+		},
+		"indexed dedot": {
+			build: build,
+			dedot: true,
+			opts:  []SerializeOption{WithIndexedObjectArrays()},
+			want:  `{"arr":{"0":{"a":1,"b":"x","n":{"0":{"d":1}}},"1":{"a":2}},"lazy":{"0":{"c":true}},"mixed":[{"a":1},3],"scalars":[1,2]}`,
+		},
+		"indexed keys are deduplicated": {
+			build: func() Document {
+				doc := build()
+				doc.AddInt("arr.1.a", 3)
+				return doc
+			},
+			opts: []SerializeOption{WithIndexedObjectArrays()},
+			want: `{"arr.0.a":1,"arr.0.b":"x","arr.0.n.0.d":1,"arr.1.a":3,"lazy.0.c":true,"mixed":[{"a":1},3],"scalars":[1,2]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := test.build()
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, test.dedot, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_ArrayFormat(t *testing.T) {
+	build := func() Document {
+		var doc Document
+		doc.Add("tags", ArrValue(StringValue("a"), StringValue("b")))
+		doc.Add("labels.env", ArrValue(StringValue("prod"), StringValue("eu")))
+		doc.Add("mixed", ArrValue(IntValue(1), StringValue("two"), BoolValue(true)))
+		doc.Add("objects", ArrValue(Value{kind: KindObject, doc: Document{fields: []field{{"a", IntValue(1)}}}}))
+		s := pcommon.NewSlice()
+		s.AppendEmpty().SetStr("x")
+		s.AppendEmpty().SetStr("y")
+		doc.Add("lazy", SliceValue(s))
+		return doc
+	}
+
+	tests := map[string]struct {
+		dedot bool
+		opts  []SerializeOption
+		want  string
+	}{
+		"arrays by default": {
+			want: `{"labels.env":["prod","eu"],"lazy":["x","y"],"mixed":[1,"two",true],"objects":[{"a":1}],"tags":["a","b"]}`,
+		},
+		"join": {
+			opts: []SerializeOption{WithArrayFormat(ArrayFormatJoined, "tags", "labels.*", "mixed", "objects", "lazy")},
+			want: `{"labels.env":"prod,eu","lazy":"x,y","mixed":"1,two,true","objects":[{"a":1}],"tags":"a,b"}`,
+		},
+		"join dedot": {
+			dedot: true,
+			opts:  []SerializeOption{WithArrayFormat(ArrayFormatJoined, "labels.*")},
+			want:  `{"labels":{"env":"prod,eu"},"lazy":["x","y"],"mixed":[1,"two",true],"objects":[{"a":1}],"tags":["a","b"]}`,
+		},
+		"first matching pattern applies": {
+			opts: []SerializeOption{
+				WithArrayFormat(ArrayFormatArray, "tags"),
+				WithArrayFormat(ArrayFormatJoined, "*"),
+			},
+			want: `{"labels.env":"prod,eu","lazy":"x,y","mixed":"1,two,true","objects":[{"a":1}],"tags":["a","b"]}`,
+		},
+		"joined strings are truncated": {
+			opts: []SerializeOption{WithArrayFormat(ArrayFormatJoined, "tags"), WithMaxStringLength(2, "")},
+			want: `{"labels.env":["pr","eu"],"lazy":["x","y"],"mixed":[1,"tw",true],"objects":[{"a":1}],"tags":"a,"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := build()
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, test.dedot, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_UnsupportedKind(t *testing.T) {
+	invalid := Value{kind: KindRawJSON + 1}
+	tests := map[string]func(doc *Document){
+		"field": func(doc *Document) { doc.Add("invalid", invalid) },
+		"array": func(doc *Document) { doc.Add("arr", ArrValue(IntValue(1), invalid)) },
+		"nested field": func(doc *Document) {
+			doc.Add("obj", Value{kind: KindObject, doc: Document{fields: []field{{"invalid", invalid}}}})
+		},
+	}
+
+	for name, add := range tests {
+		t.Run(name, func(t *testing.T) {
+			var doc Document
+			doc.AddString("a", "b")
+			add(&doc)
+
+			for _, dedot := range []bool{false, true} {
+				var buf strings.Builder
+				err := doc.Serialize(&buf, dedot)
+				var kindErr *UnsupportedKindError
+				require.ErrorAs(t, err, &kindErr)
+				assert.Equal(t, KindRawJSON+1, kindErr.Kind)
+			}
+		})
+	}
+}
+
+func TestDocument_Serialize_StringSanitization(t *testing.T) {
+	build := func() Document {
+		var doc Document
+		doc.AddString("nul", "a\x00b")
+		doc.AddString("invalid", "a\xffb\xfe")
+		doc.AddString("whitespace", "a\tb\nc\r")
+		doc.AddString("valid", "äöü")
+		doc.Add("arr", ArrValue(StringValue("x\x1by"), IntValue(1)))
+		return doc
+	}
+
+	tests := map[string]struct {
+		opts []SerializeOption
+		want string
+	}{
+		"off by default": {
+			// the JSON encoder escapes control characters and invalid UTF-8 bytes
+			want: `{"arr":["x\u001by",1],"invalid":"a\ufffdb\ufffd","nul":"a\u0000b","valid":"äöü","whitespace":"a\tb\nc\r"}`,
+		},
+		"replace": {
+			opts: []SerializeOption{WithStringSanitization(StringSanitizationReplace)},
+			want: "{\"arr\":[\"x�y\",1],\"invalid\":\"a�b�\",\"nul\":\"a�b\",\"valid\":\"äöü\",\"whitespace\":\"a\\tb\\nc\\r\"}",
+		},
+		"strip": {
+			opts: []SerializeOption{WithStringSanitization(StringSanitizationStrip)},
+			want: `{"arr":["xy",1],"invalid":"ab","nul":"ab","valid":"äöü","whitespace":"a\tb\nc\r"}`,
+		},
+		"sanitized before truncation": {
+			opts: []SerializeOption{WithStringSanitization(StringSanitizationStrip), WithMaxStringLength(2, "")},
+			want: `{"arr":["xy",1],"invalid":"ab","nul":"ab","valid":"äö","whitespace":"a\t"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := build()
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, false, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_RawJSON(t *testing.T) {
+	tests := map[string]struct {
+		dedot bool
+		want  string
+	}{
+		"flat": {
+			want: `{"a.links":[{"trace_id":"<a&b>","attributes":{}}],"b":"\u003cx\u003e","c":{"x":true}}`,
+		},
+		"dedot": {
+			dedot: true,
+			want:  `{"a":{"links":[{"trace_id":"<a&b>","attributes":{}}]},"b":"\u003cx\u003e","c":{"x":true}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var doc Document
+			doc.AddInt("c", 1)
+			doc.AddString("b", "<x>")
+			doc.AddRawJSON("a.links", json.RawMessage(`[{"trace_id":"<a&b>","attributes":{}}]`))
+			// the last value of a key wins, like for any other field
+			doc.AddRawJSON("c", json.RawMessage(`{"x":true}`))
+			doc.AddRawJSON("empty", nil)
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, test.dedot)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_GeoPoints(t *testing.T) {
+	tests := map[string]struct {
+		attrs map[string]any
+		opts  []DocumentOption
+		want  string
+	}{
+		"kept by default": {
+			attrs: map[string]any{"client.geo": map[string]any{"lat": 52.5, "lon": 13.4}},
+			want:  `{"client.geo.lat":52.5,"client.geo.lon":13.4}`,
+		},
+		"lat/lon map": {
+			attrs: map[string]any{"client.geo": map[string]any{"lat": 52.5, "lon": 13}},
+			opts:  []DocumentOption{WithGeoPointDetection("*.geo")},
+			want:  `{"client.geo":{"lat":52.5,"lon":13.0}}`,
+		},
+		"nested lat/lon map": {
+			attrs: map[string]any{"client": map[string]any{"geo": map[string]any{"lat": 52.5, "lon": 13.4}}},
+			opts:  []DocumentOption{WithGeoPointDetection("*.geo")},
+			want:  `{"client.geo":{"lat":52.5,"lon":13.4}}`,
+		},
+		"string coordinate": {
+			attrs: map[string]any{"client.geo": "52.5, 13.4"},
+			opts:  []DocumentOption{WithGeoPointDetection("*.geo")},
+			want:  `{"client.geo":{"lat":52.5,"lon":13.4}}`,
+		},
+		"non matching key": {
+			attrs: map[string]any{"client.location": "52.5,13.4"},
+			opts:  []DocumentOption{WithGeoPointDetection("*.geo")},
+			want:  `{"client.location":"52.5,13.4"}`,
+		},
+		"invalid coordinates": {
+			attrs: map[string]any{"a.geo": "north", "b.geo": "91,13.4", "c.geo": map[string]any{"lat": "52.5", "lon": 13.4}},
+			opts:  []DocumentOption{WithGeoPointDetection("*.geo")},
+			want:  `{"a.geo":"north","b.geo":"91,13.4","c.geo.lat":"52.5","c.geo.lon":13.4}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := pcommon.NewMap()
+			require.NoError(t, m.FromRaw(test.attrs))
+			doc := DocumentFromAttributes(m, test.opts...)
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, false)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_MaxStringLength(t *testing.T) {
+	tests := map[string]struct {
+		value  string
+		marker string
+		want   string
+	}{
+		"under limit": {
+			value: "short",
+			want:  `{"a":"short"}`,
+		},
+		"at limit": {
+			value: "exactly10!",
+			want:  `{"a":"exactly10!"}`,
+		},
+		"over limit": {
+			value: "this is a long string",
+			want:  `{"a":"this is a "}`,
+		},
+		"over limit with marker": {
+			value:  "this is a long string",
+			marker: "...",
+			want:   `{"a":"this is..."}`,
+		},
+		"over limit multi-byte characters": {
+			value: "ääääääääääää",
+			want:  `{"a":"ääääääääää"}`,
+		},
+		"marker longer than limit": {
+			value:  "this is a long string",
+			marker: "[truncated]",
+			want:   `{"a":"this is a "}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := Document{}
+			doc.AddString("a", test.value)
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, false, WithMaxStringLength(10, test.marker))
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+
+	t.Run("no limit by default", func(t *testing.T) {
+		long := strings.Repeat("x", 100000)
+		doc := Document{}
+		doc.AddString("a", long)
+
+		var buf strings.Builder
+		require.NoError(t, doc.Serialize(&buf, false))
+		assert.Equal(t, `{"a":"`+long+`"}`, buf.String())
+	})
+}
+
+func TestDocument_Serialize_MaxSignificantDigits(t *testing.T) {
+	tests := map[string]struct {
+		opts []SerializeOption
+		want string
+	}{
+		"default format": {
+			want: `{"a":3.14159265,"b":1234.5678,"c":2.0,"d":1.0e+20,"e":["x",3.14159265]}`,
+		},
+		"3 digits": {
+			opts: []SerializeOption{WithMaxSignificantDigits(3), WithMixedArrayMode(MixedArrayAsStrings)},
+			want: `{"a":3.14,"b":1230.0,"c":2.0,"d":1.0e+20,"e":["x","3.14"]}`,
+		},
+		"6 digits": {
+			opts: []SerializeOption{WithMaxSignificantDigits(6)},
+			want: `{"a":3.14159,"b":1234.57,"c":2.0,"d":1.0e+20,"e":["x",3.14159]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var doc Document
+			doc.Add("a", DoubleValue(3.14159265))
+			doc.Add("b", DoubleValue(1234.5678))
+			doc.Add("c", DoubleValue(2))
+			doc.Add("d", DoubleValue(1e20))
+			doc.Add("e", ArrValue(StringValue("x"), DoubleValue(3.14159265)))
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, false, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_InsertionOrder(t *testing.T) {
+	newDoc := func() Document {
+		doc := Document{}
+		doc.AddString("z", "first")
+		doc.AddInt("b", 1)
+		doc.AddString("m.x", "old")
+		doc.AddString("a", "test")
+		doc.AddInt("b", 2)
+		doc.AddString("m", "primitive")
+		doc.AddString("m.x", "new")
+		return doc
+	}
+
+	tests := map[string]struct {
+		opts  []SerializeOption
+		dedot bool
+		want  string
+	}{
+		"sorted by default": {
+			want: `{"a":"test","b":2,"m.value":"primitive","m.x":"new","z":"first"}`,
+		},
+		"insertion order": {
+			opts: []SerializeOption{WithInsertionOrder()},
+			want: `{"z":"first","a":"test","b":2,"m.value":"primitive","m.x":"new"}`,
+		},
+		"insertion order ignored when dedotting": {
+			opts:  []SerializeOption{WithInsertionOrder()},
+			dedot: true,
+			want:  `{"a":"test","b":2,"m":{"value":"primitive","x":"new"},"z":"first"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := newDoc()
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, test.dedot, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestDocument_Serialize_MixedArrays(t *testing.T) {
+	tests := map[string]struct {
+		arr  []any
+		mode MixedArrayMode
+		want string
+	}{
+		"homogeneous array is kept": {
+			arr:  []any{1, 2, 3},
+			mode: MixedArrayAsStrings,
+			want: `{"arr":[1,2,3]}`,
+		},
+		"numbers of different kinds are not mixed": {
+			arr:  []any{1, 2.5},
+			mode: MixedArrayDropNonConforming,
+			want: `{"arr":[1,2.5]}`,
+		},
+		"mixed array is kept by default": {
+			arr:  []any{1, "two", true},
+			mode: MixedArrayKeep,
+			want: `{"arr":[1,"two",true]}`,
+		},
+		"mixed array as strings": {
+			arr:  []any{1, "two", true, 2.5, map[string]any{"a": 1}},
+			mode: MixedArrayAsStrings,
+			want: `{"arr":["1","two","true","2.5",{"a":1}]}`,
+		},
+		"mixed array drops non conforming": {
+			arr:  []any{"one", 2, "three", false},
+			mode: MixedArrayDropNonConforming,
+			want: `{"arr":["one","three"]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := pcommon.NewSlice()
+			require.NoError(t, s.FromRaw(test.arr))
+
+			for _, v := range []Value{ArrValue(arrFromAttributes(s)...), SliceValue(s)} {
+				var doc Document
+				doc.Add("arr", v)
+
+				var buf strings.Builder
+				err := doc.Serialize(&buf, false, WithMixedArrayMode(test.mode))
+				require.NoError(t, err)
+				assert.Equal(t, test.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestDocument_Serialize_Duration(t *testing.T) {
+	d := time.Hour + 2*time.Minute + 3500*time.Millisecond
+
+	tests := map[string]struct {
+		opts []SerializeOption
+		want string
+	}{
+		"default format": {
+			want: `{"duration":3723500}`,
+		},
+		"milliseconds": {
+			opts: []SerializeOption{WithDurationFormat(DurationFormatMillis)},
+			want: `{"duration":3723500}`,
+		},
+		"iso8601": {
+			opts: []SerializeOption{WithDurationFormat(DurationFormatISO8601)},
+			want: `{"duration":"PT1H2M3.5S"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var doc Document
+			doc.AddDuration("duration", d)
+
+			var buf strings.Builder
+			err := doc.Serialize(&buf, false, test.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestFormatISO8601Duration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                                     "PT0S",
+		250 * time.Millisecond:                "PT0.25S",
+		90 * time.Second:                      "PT1M30S",
+		2 * time.Hour:                         "PT2H",
+		-(time.Minute + 500*time.Millisecond): "-PT1M0.5S",
+	}
+
+	for d, want := range tests {
+		assert.Equal(t, want, formatISO8601Duration(d), d.String())
+	}
+}
+
+func TestValue_Serialize_SliceValue(t *testing.T) {
+	tests := map[string][]any{
+		"empty":         {},
+		"scalars":       {true, 23, "test", 3.14},
+		"nested arrays": {[]any{1, 2}, []any{"a"}},
+		"objects": {
+			map[string]any{"b": 1, "a": "test"},
+			map[string]any{"x.y": 1, "x": map[string]any{"y": 2}},
+		},
+	}
+
+	for name, elems := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := pcommon.NewSlice()
+			require.NoError(t, s.FromRaw(elems))
+
+			materialized := ArrValue(arrFromAttributes(s)...)
+			materialized.Dedup()
+			var want strings.Builder
+			require.NoError(t, materialized.iterJSON(newJSONVisitor(&want), false))
+
+			streamed := SliceValue(s)
+			assert.Equal(t, materialized.IsEmpty(), streamed.IsEmpty())
+			var got strings.Builder
+			require.NoError(t, streamed.iterJSON(newJSONVisitor(&got), false))
+			assert.Equal(t, want.String(), got.String())
+		})
+	}
+}
+
+func TestDocumentFromAttributes_CopiesSlices(t *testing.T) {
+	m := pcommon.NewMap()
+	require.NoError(t, m.PutEmptySlice("scalars").FromRaw([]any{1, "two"}))
+	m.PutEmptySlice("objects").AppendEmpty().SetEmptyMap().PutInt("a", 1)
+
+	doc := DocumentFromAttributes(m)
+	// the document doesn't alias the attributes it was built from
+	scalars, _ := m.Get("scalars")
+	scalars.Slice().AppendEmpty().SetStr("three")
+	objects, _ := m.Get("objects")
+	objects.Slice().At(0).Map().PutInt("a", 2)
+
+	var buf strings.Builder
+	require.NoError(t, doc.Serialize(&buf, false))
+	assert.Equal(t, `{"objects":[{"a":1}],"scalars":[1,"two"]}`, buf.String())
+}
+
+func TestSerializer(t *testing.T) {
+	docs := []map[string]any{
+		{"a": "test", "b": 1},
+		{"a.str": "test", "a.i": 1},
+		{"a": map[string]any{"str": "test"}, "b": []any{1, "x"}},
+	}
+
+	for _, dedot := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dedot=%v", dedot), func(t *testing.T) {
+			var want, got strings.Builder
+			s := NewSerializer(&got)
+			for i, attrs := range docs {
+				m := pcommon.NewMap()
+				require.NoError(t, m.FromRaw(attrs))
+
+				doc := DocumentFromAttributes(m)
+				require.NoError(t, doc.Serialize(&want, dedot))
+				doc = DocumentFromAttributes(m)
+				require.NoError(t, s.Serialize(&doc, dedot), "document %d", i)
+			}
+			assert.Equal(t, want.String(), got.String())
+		})
+	}
+
+	t.Run("reset", func(t *testing.T) {
+		var first, second strings.Builder
+		s := NewSerializer(&first, WithDurationFormat(DurationFormatISO8601))
+
+		var doc Document
+		doc.AddDuration("duration", time.Second)
+		require.NoError(t, s.Serialize(&doc, false))
+		s.Reset(&second)
+		require.NoError(t, s.Serialize(&doc, false))
+
+		assert.Equal(t, `{"duration":"PT1S"}`, first.String())
+		assert.Equal(t, `{"duration":"PT1S"}`, second.String())
+	})
+}
+
+func BenchmarkDocument_Serialize(b *testing.B) {
+	m := pcommon.NewMap()
+	require.NoError(b, m.FromRaw(map[string]any{
+		"a.str": "test",
+		"a.i":   1,
+		"b":     map[string]any{"c": 1.5, "d": true},
+	}))
+	doc := DocumentFromAttributes(m)
+
+	b.Run("new visitor", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = doc.Serialize(io.Discard, true)
+		}
+	})
+	b.Run("serializer", func(b *testing.B) {
+		b.ReportAllocs()
+		s := NewSerializer(io.Discard)
+		for b.Loop() {
+			_ = s.Serialize(&doc, true)
+		}
+	})
+}
+
+func BenchmarkValue_Serialize_Array(b *testing.B) {
+	s := pcommon.NewSlice()
+	s.EnsureCapacity(10000)
+	for i := range 10000 {
+		s.AppendEmpty().SetInt(int64(i))
+	}
+
+	b.Run("materialized", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var doc Document
+			doc.Add("arr", ArrValue(arrFromAttributes(s)...))
+			_ = doc.Serialize(io.Discard, false)
+		}
+	})
+	b.Run("streamed", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var doc Document
+			doc.Add("arr", SliceValue(s))
+			_ = doc.Serialize(io.Discard, false)
+		}
+	})
+}
@@ -166,13 +166,18 @@ func (e legacyModeEncoder) encodeLog(ec encodingContext, record plog.LogRecord,
 		docTimeStamp = record.ObservedTimestamp()
 	}
 	// We use @timestamp in order to ensure that we can index if the default data stream logs template is used.
-	document.AddTimestamp("@timestamp", docTimeStamp)
+	document.AddTimestamp(objmodel.DefaultTimestampKey, docTimeStamp)
 	document.AddTraceID("TraceId", record.TraceID())
 	document.AddSpanID("SpanId", record.SpanID())
 	document.AddInt("TraceFlags", int64(record.Flags()))
 	document.AddString("SeverityText", record.SeverityText())
 	document.AddInt("SeverityNumber", int64(record.SeverityNumber()))
-	document.AddAttribute("Body", record.Body())
+	if body := record.Body(); body.Type() == pcommon.ValueTypeSlice {
+		// The record outlives the serialization below, so the body can be streamed instead of copied.
+		document.Add("Body", objmodel.SliceValue(body.Slice()))
+	} else {
+		document.AddAttribute("Body", body)
+	}
 	document.AddAttributes("Resource", ec.resource.Attributes())
 	document.AddAttributes("Scope", scopeToAttributes(ec.scope))
 	encodeAttributes(e.attributesPrefix, &document, record.Attributes(), idx)
@@ -255,7 +260,7 @@ func (ecsModeEncoder) encodeSpan(
 	encodeHostOsTypeECSMode(&document, ec.resource)
 	addDataStreamAttributes(&document, "", idx)
 
-	document.AddTimestamp("@timestamp", span.StartTimestamp())
+	document.AddTimestamp(objmodel.DefaultTimestampKey, span.StartTimestamp())
 	document.AddTraceID("trace.id", span.TraceID())
 	document.AddSpanID("span.id", span.SpanID())
 	document.AddString("span.name", span.Name())
@@ -394,7 +399,7 @@ func (e nonOTelSpanEncoder) encodeSpan(
 	buf *bytes.Buffer,
 ) error {
 	var document objmodel.Document
-	document.AddTimestamp("@timestamp", span.StartTimestamp()) // We use @timestamp in order to ensure that we can index if the default data stream logs template is used.
+	document.AddTimestamp(objmodel.DefaultTimestampKey, span.StartTimestamp()) // We use @timestamp in order to ensure that we can index if the default data stream logs template is used.
 	document.AddTimestamp("EndTimestamp", span.EndTimestamp())
 	document.AddTraceID("TraceId", span.TraceID())
 	document.AddSpanID("SpanId", span.SpanID())
@@ -424,7 +429,7 @@ func (ecsDataPointsEncoder) encodeMetrics(
 	dp0 := dataPoints[0]
 	var document objmodel.Document
 	encodeAttributesECSMode(&document, ec.resource.Attributes(), resourceAttrsConversionMap, resourceAttrsToPreserve)
-	document.AddTimestamp("@timestamp", dp0.Timestamp())
+	document.AddTimestamp(objmodel.DefaultTimestampKey, dp0.Timestamp())
 	document.AddAttributes("", dp0.Attributes())
 	addDataStreamAttributes(&document, "", idx)
 
@@ -606,9 +611,9 @@ func encodeHostOsTypeECSMode(document *objmodel.Document, resource pcommon.Resou
 
 func encodeLogTimestampECSMode(document *objmodel.Document, record plog.LogRecord) {
 	if record.Timestamp() != 0 {
-		document.AddTimestamp("@timestamp", record.Timestamp())
+		document.AddTimestamp(objmodel.DefaultTimestampKey, record.Timestamp())
 		return
 	}
 
-	document.AddTimestamp("@timestamp", record.ObservedTimestamp())
+	document.AddTimestamp(objmodel.DefaultTimestampKey, record.ObservedTimestamp())
 }
@@ -129,6 +129,26 @@ func TestEncodeLog(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedLogBodyWithEmptyTimestamp, buf.String())
 	})
+
+	t.Run("slice body", func(t *testing.T) {
+		encoder, _ := newEncoder(MappingNone)
+		td := mockResourceLogs()
+		record := td.ScopeLogs().At(0).LogRecords().At(0)
+		require.NoError(t, record.Body().SetEmptySlice().FromRaw([]any{"a", int64(1), map[string]any{"b": "c"}}))
+		var buf bytes.Buffer
+		err := encoder.encodeLog(
+			encodingContext{
+				resource:          td.Resource(),
+				resourceSchemaURL: td.SchemaUrl(),
+				scope:             td.ScopeLogs().At(0).Scope(),
+				scopeSchemaURL:    td.ScopeLogs().At(0).SchemaUrl(),
+			},
+			record,
+			elasticsearch.Index{}, &buf,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"@timestamp":"1970-01-01T00:00:00.000000000Z","Attributes.log-attr1":"value1","Body":["a",1,{"b":"c"}],"Resource.key1":"value1","Scope.name":"","Scope.version":"","SeverityNumber":0,"TraceFlags":0}`, buf.String())
+	})
 }
 
 func TestEncodeMetric(t *testing.T) {
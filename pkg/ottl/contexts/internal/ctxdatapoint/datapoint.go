@@ -5,12 +5,17 @@ package ctxdatapoint // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/ctxcommon"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/ctxerror"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/ctxutil"
 )
@@ -25,6 +30,11 @@ func PathGetSetter[K Context](path ottl.Path[K]) (ottl.GetSetter[K], error) {
 			return accessAttributes[K](), nil
 		}
 		return accessAttributesKey(path.Keys()), nil
+	case "has_attribute":
+		if path.Keys() == nil {
+			return nil, ctxerror.New(path.Name(), path.String(), Name, DocRef)
+		}
+		return accessHasAttribute(path.Keys()), nil
 	case "start_time_unix_nano":
 		return accessStartTimeUnixNano[K](), nil
 	case "time_unix_nano":
@@ -33,26 +43,60 @@ func PathGetSetter[K Context](path ottl.Path[K]) (ottl.GetSetter[K], error) {
 		return accessStartTime[K](), nil
 	case "time":
 		return accessTime[K](), nil
+	case "start_time_after_time":
+		return accessStartTimeAfterTime[K](), nil
 	case "value_double":
 		return accessDoubleValue[K](), nil
 	case "value_int":
 		return accessIntValue[K](), nil
 	case "exemplars":
-		return accessExemplars[K](), nil
+		if path.Keys() == nil {
+			return accessExemplars[K](), nil
+		}
+		nextPath := path.Next()
+		if nextPath == nil {
+			return nil, ctxerror.New(path.Name(), path.String(), Name, DocRef)
+		}
+		switch nextPath.Name() {
+		case "trace_id":
+			return accessExemplarTraceID(path.Keys()), nil
+		case "span_id":
+			return accessExemplarSpanID(path.Keys()), nil
+		case "time_unix_nano":
+			return accessExemplarTimeUnixNano(path.Keys()), nil
+		case "filtered_attributes":
+			if nextPath.Keys() == nil {
+				return accessExemplarFilteredAttributes(path.Keys()), nil
+			}
+			return accessExemplarFilteredAttributesKey(path.Keys(), nextPath.Keys()), nil
+		default:
+			return nil, ctxerror.New(nextPath.Name(), path.String(), Name, DocRef)
+		}
 	case "flags":
 		return accessFlags[K](), nil
 	case "count":
 		return accessCount[K](), nil
+	case "sample_count":
+		return accessSampleCount[K](), nil
 	case "sum":
 		return accessSum[K](), nil
 	case "bucket_counts":
 		return accessBucketCounts[K](), nil
+	case "bucket_counts_at":
+		if path.Keys() == nil {
+			return nil, ctxerror.New(path.Name(), path.String(), Name, DocRef)
+		}
+		return accessBucketCountsAt(path.Keys()), nil
+	case "histogram_fields":
+		return accessHistogramFields[K](), nil
 	case "explicit_bounds":
 		return accessExplicitBounds[K](), nil
 	case "scale":
 		return accessScale[K](), nil
 	case "zero_count":
 		return accessZeroCount[K](), nil
+	case "zero_threshold":
+		return accessZeroThreshold[K](), nil
 	case "positive":
 		nextPath := path.Next()
 		if nextPath != nil {
@@ -86,30 +130,118 @@ func PathGetSetter[K Context](path ottl.Path[K]) (ottl.GetSetter[K], error) {
 	}
 }
 
-func accessAttributes[K Context]() ottl.StandardGetSetter[K] {
+// ValidatePath checks whether the path is a valid datapoint path without building its
+// accessor. It returns the same errors as PathGetSetter.
+func ValidatePath[K Context](path ottl.Path[K]) error {
+	if path == nil {
+		return ctxerror.New("nil", "nil", Name, DocRef)
+	}
+	switch path.Name() {
+	case "attributes",
+		"start_time_unix_nano",
+		"time_unix_nano",
+		"start_time",
+		"time",
+		"start_time_after_time",
+		"value_double",
+		"value_int",
+		"flags",
+		"count",
+		"sample_count",
+		"sum",
+		"bucket_counts",
+		"histogram_fields",
+		"explicit_bounds",
+		"scale",
+		"zero_count",
+		"zero_threshold",
+		"quantile_values":
+		return nil
+	case "has_attribute", "bucket_counts_at":
+		if path.Keys() == nil {
+			return ctxerror.New(path.Name(), path.String(), Name, DocRef)
+		}
+		return nil
+	case "exemplars":
+		if path.Keys() == nil {
+			return nil
+		}
+		nextPath := path.Next()
+		if nextPath == nil {
+			return ctxerror.New(path.Name(), path.String(), Name, DocRef)
+		}
+		switch nextPath.Name() {
+		case "trace_id", "span_id", "time_unix_nano", "filtered_attributes":
+			return nil
+		default:
+			return ctxerror.New(nextPath.Name(), path.String(), Name, DocRef)
+		}
+	case "positive", "negative":
+		nextPath := path.Next()
+		if nextPath == nil {
+			return nil
+		}
+		switch nextPath.Name() {
+		case "offset", "bucket_counts":
+			return nil
+		default:
+			return ctxerror.New(nextPath.Name(), path.String(), Name, DocRef)
+		}
+	default:
+		return ctxerror.New(path.Name(), path.String(), Name, DocRef)
+	}
+}
+
+// DataPointGetSetter returns an accessor for the data point being processed as a whole.
+// Setting it copies the given data point into the current one, which must be of the same type.
+func DataPointGetSetter[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetDataPoint(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
 			switch dp := tCtx.GetDataPoint().(type) {
 			case pmetric.NumberDataPoint:
-				return dp.Attributes(), nil
+				if newDataPoint, ok := val.(pmetric.NumberDataPoint); ok {
+					newDataPoint.CopyTo(dp)
+					return nil
+				}
 			case pmetric.HistogramDataPoint:
-				return dp.Attributes(), nil
+				if newDataPoint, ok := val.(pmetric.HistogramDataPoint); ok {
+					newDataPoint.CopyTo(dp)
+					return nil
+				}
 			case pmetric.ExponentialHistogramDataPoint:
-				return dp.Attributes(), nil
+				if newDataPoint, ok := val.(pmetric.ExponentialHistogramDataPoint); ok {
+					newDataPoint.CopyTo(dp)
+					return nil
+				}
 			case pmetric.SummaryDataPoint:
+				if newDataPoint, ok := val.(pmetric.SummaryDataPoint); ok {
+					newDataPoint.CopyTo(dp)
+					return nil
+				}
+			}
+			return fmt.Errorf("cannot set a %T data point from a value of type %T", tCtx.GetDataPoint(), val)
+		},
+	}
+}
+
+// attributesDataPoint is implemented by all data point types holding attributes.
+type attributesDataPoint interface {
+	Attributes() pcommon.Map
+}
+
+func accessAttributes[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			if dp, ok := tCtx.GetDataPoint().(attributesDataPoint); ok {
 				return dp.Attributes(), nil
 			}
 			return nil, nil
 		},
 		Setter: func(_ context.Context, tCtx K, val any) error {
-			switch dp := tCtx.GetDataPoint().(type) {
-			case pmetric.NumberDataPoint:
-				return ctxutil.SetMap(dp.Attributes(), val)
-			case pmetric.HistogramDataPoint:
-				return ctxutil.SetMap(dp.Attributes(), val)
-			case pmetric.ExponentialHistogramDataPoint:
-				return ctxutil.SetMap(dp.Attributes(), val)
-			case pmetric.SummaryDataPoint:
+			if dp, ok := tCtx.GetDataPoint().(attributesDataPoint); ok {
 				return ctxutil.SetMap(dp.Attributes(), val)
 			}
 			return nil
@@ -120,27 +252,13 @@ func accessAttributes[K Context]() ottl.StandardGetSetter[K] {
 func accessAttributesKey[K Context](key []ottl.Key[K]) ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(ctx context.Context, tCtx K) (any, error) {
-			switch dp := tCtx.GetDataPoint().(type) {
-			case pmetric.NumberDataPoint:
-				return ctxutil.GetMapValue(ctx, tCtx, dp.Attributes(), key)
-			case pmetric.HistogramDataPoint:
-				return ctxutil.GetMapValue(ctx, tCtx, dp.Attributes(), key)
-			case pmetric.ExponentialHistogramDataPoint:
-				return ctxutil.GetMapValue(ctx, tCtx, dp.Attributes(), key)
-			case pmetric.SummaryDataPoint:
+			if dp, ok := tCtx.GetDataPoint().(attributesDataPoint); ok {
 				return ctxutil.GetMapValue(ctx, tCtx, dp.Attributes(), key)
 			}
 			return nil, nil
 		},
 		Setter: func(ctx context.Context, tCtx K, val any) error {
-			switch dp := tCtx.GetDataPoint().(type) {
-			case pmetric.NumberDataPoint:
-				return ctxutil.SetMapValue(ctx, tCtx, dp.Attributes(), key, val)
-			case pmetric.HistogramDataPoint:
-				return ctxutil.SetMapValue(ctx, tCtx, dp.Attributes(), key, val)
-			case pmetric.ExponentialHistogramDataPoint:
-				return ctxutil.SetMapValue(ctx, tCtx, dp.Attributes(), key, val)
-			case pmetric.SummaryDataPoint:
+			if dp, ok := tCtx.GetDataPoint().(attributesDataPoint); ok {
 				return ctxutil.SetMapValue(ctx, tCtx, dp.Attributes(), key, val)
 			}
 			return nil
@@ -148,6 +266,31 @@ func accessAttributesKey[K Context](key []ottl.Key[K]) ottl.StandardGetSetter[K]
 	}
 }
 
+// accessHasAttribute returns whether the data point has an attribute with the key given by keys,
+// including attributes with an empty value, which can't be told apart from absent ones by value.
+func accessHasAttribute[K Context](keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			if len(keys) != 1 {
+				return nil, errors.New("cannot index has_attribute with more than one key")
+			}
+			name, err := ctxutil.GetMapKeyName(ctx, tCtx, keys[0])
+			if err != nil {
+				return nil, err
+			}
+			dp, ok := tCtx.GetDataPoint().(attributesDataPoint)
+			if !ok {
+				return false, nil
+			}
+			_, ok = dp.Attributes().Get(*name)
+			return ok, nil
+		},
+		Setter: func(context.Context, K, any) error {
+			return errors.New("has_attribute is read-only, set attributes instead")
+		},
+	}
+}
+
 func accessStartTimeUnixNano[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -214,6 +357,27 @@ func accessStartTime[K Context]() ottl.StandardGetSetter[K] {
 	}
 }
 
+func accessStartTimeAfterTime[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			switch dp := tCtx.GetDataPoint().(type) {
+			case pmetric.NumberDataPoint:
+				return dp.StartTimestamp() > dp.Timestamp(), nil
+			case pmetric.HistogramDataPoint:
+				return dp.StartTimestamp() > dp.Timestamp(), nil
+			case pmetric.ExponentialHistogramDataPoint:
+				return dp.StartTimestamp() > dp.Timestamp(), nil
+			case pmetric.SummaryDataPoint:
+				return dp.StartTimestamp() > dp.Timestamp(), nil
+			}
+			return nil, nil
+		},
+		Setter: func(context.Context, K, any) error {
+			return errors.New("start_time_after_time is read-only, set start_time or time instead")
+		},
+	}
+}
+
 func accessTimeUnixNano[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -347,6 +511,160 @@ func accessExemplars[K Context]() ottl.StandardGetSetter[K] {
 	}
 }
 
+// getExemplar returns the exemplar of the current data point at the index given by keys.
+// It returns false if the data point type doesn't hold exemplars.
+func getExemplar[K Context](ctx context.Context, tCtx K, keys []ottl.Key[K]) (pmetric.Exemplar, bool, error) {
+	var exemplars pmetric.ExemplarSlice
+	switch dp := tCtx.GetDataPoint().(type) {
+	case pmetric.NumberDataPoint:
+		exemplars = dp.Exemplars()
+	case pmetric.HistogramDataPoint:
+		exemplars = dp.Exemplars()
+	case pmetric.ExponentialHistogramDataPoint:
+		exemplars = dp.Exemplars()
+	default:
+		return pmetric.Exemplar{}, false, nil
+	}
+
+	if len(keys) != 1 {
+		return pmetric.Exemplar{}, false, errors.New("cannot index exemplars with more than one key")
+	}
+	idx, err := keys[0].Int(ctx, tCtx)
+	if err != nil {
+		return pmetric.Exemplar{}, false, err
+	}
+	if idx == nil {
+		idx, err = ctxutil.FetchValueFromExpression[K, int64](ctx, tCtx, keys[0])
+		if err != nil {
+			return pmetric.Exemplar{}, false, fmt.Errorf("unable to resolve an integer index in exemplars: %w", err)
+		}
+	}
+	if *idx < 0 || *idx >= int64(exemplars.Len()) {
+		return pmetric.Exemplar{}, false, fmt.Errorf("index %d out of bounds", *idx)
+	}
+	return exemplars.At(int(*idx)), true, nil
+}
+
+func accessExemplarTraceID[K Context](keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return nil, err
+			}
+			id := exemplar.TraceID()
+			return hex.EncodeToString(id[:]), nil
+		},
+		Setter: func(ctx context.Context, tCtx K, val any) error {
+			str, ok := val.(string)
+			if !ok {
+				return nil
+			}
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return err
+			}
+			id, err := ctxcommon.ParseTraceID(str)
+			if err != nil {
+				return err
+			}
+			exemplar.SetTraceID(id)
+			return nil
+		},
+	}
+}
+
+func accessExemplarSpanID[K Context](keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return nil, err
+			}
+			id := exemplar.SpanID()
+			return hex.EncodeToString(id[:]), nil
+		},
+		Setter: func(ctx context.Context, tCtx K, val any) error {
+			str, ok := val.(string)
+			if !ok {
+				return nil
+			}
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return err
+			}
+			id, err := ctxcommon.ParseSpanID(str)
+			if err != nil {
+				return err
+			}
+			exemplar.SetSpanID(id)
+			return nil
+		},
+	}
+}
+
+func accessExemplarTimeUnixNano[K Context](keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return exemplar.Timestamp().AsTime().UnixNano(), nil
+		},
+		Setter: func(ctx context.Context, tCtx K, val any) error {
+			newTime, ok := val.(int64)
+			if !ok {
+				return nil
+			}
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return err
+			}
+			exemplar.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(0, newTime)))
+			return nil
+		},
+	}
+}
+
+func accessExemplarFilteredAttributes[K Context](keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return exemplar.FilteredAttributes(), nil
+		},
+		Setter: func(ctx context.Context, tCtx K, val any) error {
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return err
+			}
+			return ctxutil.SetMap(exemplar.FilteredAttributes(), val)
+		},
+	}
+}
+
+func accessExemplarFilteredAttributesKey[K Context](keys, attributeKeys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return ctxutil.GetMapValue(ctx, tCtx, exemplar.FilteredAttributes(), attributeKeys)
+		},
+		Setter: func(ctx context.Context, tCtx K, val any) error {
+			exemplar, ok, err := getExemplar(ctx, tCtx, keys)
+			if err != nil || !ok {
+				return err
+			}
+			return ctxutil.SetMapValue(ctx, tCtx, exemplar.FilteredAttributes(), attributeKeys, val)
+		},
+	}
+}
+
 func accessFlags[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -409,6 +727,27 @@ func accessCount[K Context]() ottl.StandardGetSetter[K] {
 	}
 }
 
+func accessSampleCount[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			switch dp := tCtx.GetDataPoint().(type) {
+			case pmetric.NumberDataPoint:
+				return int64(1), nil
+			case pmetric.HistogramDataPoint:
+				return int64(dp.Count()), nil
+			case pmetric.ExponentialHistogramDataPoint:
+				return int64(dp.Count()), nil
+			case pmetric.SummaryDataPoint:
+				return int64(dp.Count()), nil
+			}
+			return nil, nil
+		},
+		Setter: func(context.Context, K, any) error {
+			return errors.New("sample_count is read-only, set count instead")
+		},
+	}
+}
+
 func accessSum[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -476,6 +815,230 @@ func accessBucketCounts[K Context]() ottl.StandardGetSetter[K] {
 	}
 }
 
+// accessBucketCountsAt returns the count of the bucket whose explicit upper bound equals
+// the value given by keys, or nil if the data point has no such bound.
+func accessBucketCountsAt[K Context](keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			histogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.HistogramDataPoint)
+			if !ok {
+				return nil, nil
+			}
+			bound, err := getBound(ctx, tCtx, keys)
+			if err != nil {
+				return nil, err
+			}
+			bounds := histogramDataPoint.ExplicitBounds()
+			for i := 0; i < bounds.Len(); i++ {
+				if bounds.At(i) == bound {
+					if i >= histogramDataPoint.BucketCounts().Len() {
+						return nil, nil
+					}
+					return int64(histogramDataPoint.BucketCounts().At(i)), nil
+				}
+			}
+			return nil, nil
+		},
+		Setter: func(context.Context, K, any) error {
+			return errors.New("bucket_counts_at is read-only, set bucket_counts instead")
+		},
+	}
+}
+
+// getBound resolves the explicit bound given by keys to a float64.
+func getBound[K Context](ctx context.Context, tCtx K, keys []ottl.Key[K]) (float64, error) {
+	if len(keys) != 1 {
+		return 0, errors.New("cannot index bucket_counts_at with more than one key")
+	}
+	i, err := keys[0].Int(ctx, tCtx)
+	if err != nil {
+		return 0, err
+	}
+	if i != nil {
+		return float64(*i), nil
+	}
+	getter, err := keys[0].ExpressionGetter(ctx, tCtx)
+	if err != nil {
+		return 0, err
+	}
+	if getter == nil {
+		return 0, errors.New("bucket_counts_at must be indexed by a numeric bound")
+	}
+	val, err := getter.Get(ctx, tCtx)
+	if err != nil {
+		return 0, err
+	}
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("bucket_counts_at must be indexed by a numeric bound, got %T", val)
+	}
+}
+
+// accessHistogramFields gets and sets the count, sum, min and max of a histogram data point
+// together. The fields given to the setter are validated against each other and against the
+// fields which aren't given, and only applied if they are consistent. A field with an empty
+// value removes the optional sum, min or max.
+func accessHistogramFields[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			histogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.HistogramDataPoint)
+			if !ok {
+				return nil, nil
+			}
+			fields := pcommon.NewMap()
+			fields.PutInt("count", int64(histogramDataPoint.Count()))
+			if histogramDataPoint.HasSum() {
+				fields.PutDouble("sum", histogramDataPoint.Sum())
+			}
+			if histogramDataPoint.HasMin() {
+				fields.PutDouble("min", histogramDataPoint.Min())
+			}
+			if histogramDataPoint.HasMax() {
+				fields.PutDouble("max", histogramDataPoint.Max())
+			}
+			return fields, nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			histogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.HistogramDataPoint)
+			if !ok {
+				return nil
+			}
+			newFields, err := ctxutil.GetMap(val)
+			if err != nil {
+				return err
+			}
+			fields := newHistogramFields(histogramDataPoint)
+			if err = fields.update(newFields); err != nil {
+				return err
+			}
+			if err = fields.validate(histogramDataPoint); err != nil {
+				return err
+			}
+			fields.apply(histogramDataPoint)
+			return nil
+		},
+	}
+}
+
+// histogramFields holds the count, sum, min and max of a histogram data point. A nil sum,
+// min or max is unset.
+type histogramFields struct {
+	count uint64
+	sum   *float64
+	min   *float64
+	max   *float64
+}
+
+func newHistogramFields(dp pmetric.HistogramDataPoint) histogramFields {
+	fields := histogramFields{count: dp.Count()}
+	if dp.HasSum() {
+		sumValue := dp.Sum()
+		fields.sum = &sumValue
+	}
+	if dp.HasMin() {
+		minValue := dp.Min()
+		fields.min = &minValue
+	}
+	if dp.HasMax() {
+		maxValue := dp.Max()
+		fields.max = &maxValue
+	}
+	return fields
+}
+
+func (f *histogramFields) update(newFields pcommon.Map) error {
+	for k, v := range newFields.All() {
+		switch k {
+		case "count":
+			if v.Type() != pcommon.ValueTypeInt || v.Int() < 0 {
+				return fmt.Errorf("histogram count must be a non-negative int, got %q", v.AsString())
+			}
+			f.count = uint64(v.Int())
+		case "sum", "min", "max":
+			var field *float64
+			switch v.Type() {
+			case pcommon.ValueTypeEmpty:
+			case pcommon.ValueTypeDouble:
+				d := v.Double()
+				field = &d
+			case pcommon.ValueTypeInt:
+				d := float64(v.Int())
+				field = &d
+			default:
+				return fmt.Errorf("histogram %s must be a number, got %q", k, v.AsString())
+			}
+			switch k {
+			case "sum":
+				f.sum = field
+			case "min":
+				f.min = field
+			default:
+				f.max = field
+			}
+		default:
+			return fmt.Errorf("unknown histogram field %q, expected one of count, sum, min or max", k)
+		}
+	}
+	return nil
+}
+
+// validate checks that the fields are consistent with each other and with the bucket counts of dp.
+func (f *histogramFields) validate(dp pmetric.HistogramDataPoint) error {
+	if dp.BucketCounts().Len() > 0 {
+		var total uint64
+		for _, count := range dp.BucketCounts().All() {
+			total += count
+		}
+		if total != f.count {
+			return fmt.Errorf("histogram count %d doesn't match the total of its bucket counts %d", f.count, total)
+		}
+	}
+	if f.min != nil && f.max != nil && *f.min > *f.max {
+		return fmt.Errorf("histogram min %v is greater than its max %v", *f.min, *f.max)
+	}
+	if f.sum != nil && f.count > 0 {
+		if f.min != nil && *f.sum < *f.min*float64(f.count) {
+			return fmt.Errorf("histogram sum %v is less than count %d times its min %v", *f.sum, f.count, *f.min)
+		}
+		if f.max != nil && *f.sum > *f.max*float64(f.count) {
+			return fmt.Errorf("histogram sum %v is greater than count %d times its max %v", *f.sum, f.count, *f.max)
+		}
+	}
+	return nil
+}
+
+func (f *histogramFields) apply(dp pmetric.HistogramDataPoint) {
+	dp.SetCount(f.count)
+	if f.sum != nil {
+		dp.SetSum(*f.sum)
+	} else {
+		dp.RemoveSum()
+	}
+	if f.min != nil {
+		dp.SetMin(*f.min)
+	} else {
+		dp.RemoveMin()
+	}
+	if f.max != nil {
+		dp.SetMax(*f.max)
+	} else {
+		dp.RemoveMax()
+	}
+}
+
+// The valid range of exponential histogram scales, see
+// https://opentelemetry.io/docs/specs/otel/metrics/data-model/#exponential-scale.
+// Scales outside of the range are clamped to it, as they would produce buckets that
+// consumers can't handle.
+const (
+	minScale = -10
+	maxScale = 20
+)
+
 func accessScale[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -487,7 +1050,7 @@ func accessScale[K Context]() ottl.StandardGetSetter[K] {
 		Setter: func(_ context.Context, tCtx K, val any) error {
 			if newScale, ok := val.(int64); ok {
 				if expoHistogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.ExponentialHistogramDataPoint); ok {
-					expoHistogramDataPoint.SetScale(int32(newScale))
+					expoHistogramDataPoint.SetScale(int32(min(max(newScale, minScale), maxScale)))
 				}
 			}
 			return nil
@@ -514,6 +1077,25 @@ func accessZeroCount[K Context]() ottl.StandardGetSetter[K] {
 	}
 }
 
+func accessZeroThreshold[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			if expoHistogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.ExponentialHistogramDataPoint); ok {
+				return expoHistogramDataPoint.ZeroThreshold(), nil
+			}
+			return nil, nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if newZeroThreshold, ok := val.(float64); ok {
+				if expoHistogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.ExponentialHistogramDataPoint); ok {
+					expoHistogramDataPoint.SetZeroThreshold(newZeroThreshold)
+				}
+			}
+			return nil
+		},
+	}
+}
+
 func accessPositive[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -533,6 +1115,17 @@ func accessPositive[K Context]() ottl.StandardGetSetter[K] {
 	}
 }
 
+// toBucketOffset converts the value to an exponential histogram bucket offset, which is an int32.
+// Offsets outside of the int32 range are rejected with an error rather than clamped, as clamping
+// would silently shift the buckets. Contexts have no logger, so the error is what surfaces the
+// rejected statement; with error_mode ignore it is logged and the data point is left unchanged.
+func toBucketOffset(val int64) (int32, error) {
+	if val < math.MinInt32 || val > math.MaxInt32 {
+		return 0, fmt.Errorf("bucket offset %d is out of the int32 range", val)
+	}
+	return int32(val), nil
+}
+
 func accessPositiveOffset[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -544,7 +1137,11 @@ func accessPositiveOffset[K Context]() ottl.StandardGetSetter[K] {
 		Setter: func(_ context.Context, tCtx K, val any) error {
 			if newPositiveOffset, ok := val.(int64); ok {
 				if expoHistogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.ExponentialHistogramDataPoint); ok {
-					expoHistogramDataPoint.Positive().SetOffset(int32(newPositiveOffset))
+					offset, err := toBucketOffset(newPositiveOffset)
+					if err != nil {
+						return err
+					}
+					expoHistogramDataPoint.Positive().SetOffset(offset)
 				}
 			}
 			return nil
@@ -601,7 +1198,11 @@ func accessNegativeOffset[K Context]() ottl.StandardGetSetter[K] {
 		Setter: func(_ context.Context, tCtx K, val any) error {
 			if newNegativeOffset, ok := val.(int64); ok {
 				if expoHistogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.ExponentialHistogramDataPoint); ok {
-					expoHistogramDataPoint.Negative().SetOffset(int32(newNegativeOffset))
+					offset, err := toBucketOffset(newNegativeOffset)
+					if err != nil {
+						return err
+					}
+					expoHistogramDataPoint.Negative().SetOffset(offset)
 				}
 			}
 			return nil
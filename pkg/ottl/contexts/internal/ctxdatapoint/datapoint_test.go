@@ -4,11 +4,15 @@
 package ctxdatapoint_test
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"slices"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
@@ -1079,6 +1083,17 @@ func TestPathGetSetter_ExpoHistogramDataPoint(t *testing.T) {
 				datapoint.SetZeroCount(2)
 			},
 		},
+		{
+			name: "zero_threshold",
+			path: &pathtest.Path[*testContext]{
+				N: "zero_threshold",
+			},
+			orig:   0.5,
+			newVal: 1.5,
+			modified: func(datapoint pmetric.ExponentialHistogramDataPoint) {
+				datapoint.SetZeroThreshold(1.5)
+			},
+		},
 		{
 			name: "positive",
 			path: &pathtest.Path[*testContext]{
@@ -1503,6 +1518,7 @@ func createExpoHistogramDataPointTelemetry() pmetric.ExponentialHistogramDataPoi
 	expoHistogramDataPoint.SetSum(10.1)
 	expoHistogramDataPoint.SetScale(1)
 	expoHistogramDataPoint.SetZeroCount(1)
+	expoHistogramDataPoint.SetZeroThreshold(0.5)
 
 	expoHistogramDataPoint.Positive().BucketCounts().FromRaw([]uint64{1, 1})
 	expoHistogramDataPoint.Positive().SetOffset(1)
@@ -1970,6 +1986,698 @@ func createSummaryDataPointTelemetry() pmetric.SummaryDataPoint {
 	return summaryDataPoint
 }
 
+func TestPathGetSetter_StartTimeAfterTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		startTime int64
+		time      int64
+		expected  bool
+	}{
+		{name: "start before time", startTime: 100, time: 200, expected: false},
+		{name: "start equals time", startTime: 200, time: 200, expected: false},
+		{name: "start after time", startTime: 300, time: 200, expected: true},
+	}
+	for _, tt := range tests {
+		numberDataPoint := pmetric.NewNumberDataPoint()
+		histogramDataPoint := pmetric.NewHistogramDataPoint()
+		expoHistogramDataPoint := pmetric.NewExponentialHistogramDataPoint()
+		summaryDataPoint := pmetric.NewSummaryDataPoint()
+		startTimestamp := pcommon.Timestamp(tt.startTime)
+		timestamp := pcommon.Timestamp(tt.time)
+		numberDataPoint.SetStartTimestamp(startTimestamp)
+		numberDataPoint.SetTimestamp(timestamp)
+		histogramDataPoint.SetStartTimestamp(startTimestamp)
+		histogramDataPoint.SetTimestamp(timestamp)
+		expoHistogramDataPoint.SetStartTimestamp(startTimestamp)
+		expoHistogramDataPoint.SetTimestamp(timestamp)
+		summaryDataPoint.SetStartTimestamp(startTimestamp)
+		summaryDataPoint.SetTimestamp(timestamp)
+
+		for _, dp := range []any{numberDataPoint, histogramDataPoint, expoHistogramDataPoint, summaryDataPoint} {
+			t.Run(fmt.Sprintf("%s %T", tt.name, dp), func(t *testing.T) {
+				accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{N: "start_time_after_time"})
+				assert.NoError(t, err)
+
+				ctx := newTestContext(dp)
+				got, err := accessor.Get(t.Context(), ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, got)
+
+				assert.Error(t, accessor.Set(t.Context(), ctx, false))
+			})
+		}
+	}
+}
+
+func TestPathGetSetter_SampleCount(t *testing.T) {
+	numberDataPoint := pmetric.NewNumberDataPoint()
+	numberDataPoint.SetDoubleValue(42)
+	histogramDataPoint := pmetric.NewHistogramDataPoint()
+	histogramDataPoint.SetCount(7)
+	expoHistogramDataPoint := pmetric.NewExponentialHistogramDataPoint()
+	expoHistogramDataPoint.SetCount(11)
+	summaryDataPoint := pmetric.NewSummaryDataPoint()
+	summaryDataPoint.SetCount(3)
+
+	tests := []struct {
+		dataPoint any
+		expected  int64
+	}{
+		{dataPoint: numberDataPoint, expected: 1},
+		{dataPoint: histogramDataPoint, expected: 7},
+		{dataPoint: expoHistogramDataPoint, expected: 11},
+		{dataPoint: summaryDataPoint, expected: 3},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T", tt.dataPoint), func(t *testing.T) {
+			accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{N: "sample_count"})
+			require.NoError(t, err)
+
+			ctx := newTestContext(tt.dataPoint)
+			got, err := accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+
+			assert.EqualError(t, accessor.Set(t.Context(), ctx, int64(5)), "sample_count is read-only, set count instead")
+			got, err = accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestDataPointGetSetter(t *testing.T) {
+	newNumberDataPoint := pmetric.NewNumberDataPoint()
+	newNumberDataPoint.SetIntValue(42)
+	newHistogramDataPoint := pmetric.NewHistogramDataPoint()
+	newHistogramDataPoint.SetCount(42)
+	newExpoHistogramDataPoint := pmetric.NewExponentialHistogramDataPoint()
+	newExpoHistogramDataPoint.SetScale(42)
+	newSummaryDataPoint := pmetric.NewSummaryDataPoint()
+	newSummaryDataPoint.SetSum(42)
+
+	tests := []struct {
+		current  any
+		newValue any
+	}{
+		{current: pmetric.NewNumberDataPoint(), newValue: newNumberDataPoint},
+		{current: pmetric.NewHistogramDataPoint(), newValue: newHistogramDataPoint},
+		{current: pmetric.NewExponentialHistogramDataPoint(), newValue: newExpoHistogramDataPoint},
+		{current: pmetric.NewSummaryDataPoint(), newValue: newSummaryDataPoint},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T", tt.current), func(t *testing.T) {
+			accessor := ctxdatapoint.DataPointGetSetter[*testContext]()
+			ctx := newTestContext(tt.current)
+
+			got, err := accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.current, got)
+
+			require.NoError(t, accessor.Set(t.Context(), ctx, tt.newValue))
+			assert.Equal(t, tt.newValue, tt.current)
+
+			// a data point of another type can't be set
+			for _, other := range tests {
+				if other.current == tt.current {
+					continue
+				}
+				assert.Error(t, accessor.Set(t.Context(), ctx, other.newValue))
+			}
+			assert.Error(t, accessor.Set(t.Context(), ctx, "invalid"))
+		})
+	}
+}
+
+func exemplarPath(index int64, field string) *pathtest.Path[*testContext] {
+	return &pathtest.Path[*testContext]{
+		N: "exemplars",
+		KeySlice: []ottl.Key[*testContext]{
+			&pathtest.Key[*testContext]{I: ottltest.Intp(index)},
+		},
+		NextPath: &pathtest.Path[*testContext]{N: field},
+	}
+}
+
+func TestPathGetSetter_ExemplarIDs(t *testing.T) {
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	spanID := pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+	numberDataPoint := pmetric.NewNumberDataPoint()
+	histogramDataPoint := pmetric.NewHistogramDataPoint()
+	expoHistogramDataPoint := pmetric.NewExponentialHistogramDataPoint()
+	numberDataPoint.Exemplars().AppendEmpty()
+	histogramDataPoint.Exemplars().AppendEmpty()
+	expoHistogramDataPoint.Exemplars().AppendEmpty()
+
+	for _, dp := range []any{numberDataPoint, histogramDataPoint, expoHistogramDataPoint} {
+		t.Run(fmt.Sprintf("%T", dp), func(t *testing.T) {
+			ctx := newTestContext(dp)
+
+			traceIDAccessor, err := ctxdatapoint.PathGetSetter(exemplarPath(0, "trace_id"))
+			require.NoError(t, err)
+			require.NoError(t, traceIDAccessor.Set(t.Context(), ctx, "0102030405060708090a0b0c0d0e0f10"))
+			got, err := traceIDAccessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", got)
+			assert.Error(t, traceIDAccessor.Set(t.Context(), ctx, "invalid"))
+
+			spanIDAccessor, err := ctxdatapoint.PathGetSetter(exemplarPath(0, "span_id"))
+			require.NoError(t, err)
+			require.NoError(t, spanIDAccessor.Set(t.Context(), ctx, "0102030405060708"))
+			got, err = spanIDAccessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "0102030405060708", got)
+
+			var exemplar pmetric.Exemplar
+			switch dp := dp.(type) {
+			case pmetric.NumberDataPoint:
+				exemplar = dp.Exemplars().At(0)
+			case pmetric.HistogramDataPoint:
+				exemplar = dp.Exemplars().At(0)
+			case pmetric.ExponentialHistogramDataPoint:
+				exemplar = dp.Exemplars().At(0)
+			}
+			assert.Equal(t, traceID, exemplar.TraceID())
+			assert.Equal(t, spanID, exemplar.SpanID())
+
+			outOfBounds, err := ctxdatapoint.PathGetSetter(exemplarPath(1, "trace_id"))
+			require.NoError(t, err)
+			_, err = outOfBounds.Get(t.Context(), ctx)
+			assert.ErrorContains(t, err, "index 1 out of bounds")
+			assert.ErrorContains(t, outOfBounds.Set(t.Context(), ctx, "0102030405060708090a0b0c0d0e0f10"), "index 1 out of bounds")
+		})
+	}
+
+	t.Run("summary data point", func(t *testing.T) {
+		accessor, err := ctxdatapoint.PathGetSetter(exemplarPath(0, "trace_id"))
+		require.NoError(t, err)
+		got, err := accessor.Get(t.Context(), newTestContext(pmetric.NewSummaryDataPoint()))
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestPathGetSetter_ExemplarTimeAndFilteredAttributes(t *testing.T) {
+	numberDataPoint := pmetric.NewNumberDataPoint()
+	histogramDataPoint := pmetric.NewHistogramDataPoint()
+	expoHistogramDataPoint := pmetric.NewExponentialHistogramDataPoint()
+	numberDataPoint.Exemplars().AppendEmpty()
+	histogramDataPoint.Exemplars().AppendEmpty()
+	expoHistogramDataPoint.Exemplars().AppendEmpty()
+
+	filteredAttributeKeyPath := exemplarPath(0, "filtered_attributes")
+	filteredAttributeKeyPath.NextPath.KeySlice = []ottl.Key[*testContext]{
+		&pathtest.Key[*testContext]{S: ottltest.Strp("key")},
+	}
+
+	for _, dp := range []any{numberDataPoint, histogramDataPoint, expoHistogramDataPoint} {
+		t.Run(fmt.Sprintf("%T", dp), func(t *testing.T) {
+			ctx := newTestContext(dp)
+
+			timeAccessor, err := ctxdatapoint.PathGetSetter(exemplarPath(0, "time_unix_nano"))
+			require.NoError(t, err)
+			require.NoError(t, timeAccessor.Set(t.Context(), ctx, int64(1_000_000_500)))
+			got, err := timeAccessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1_000_000_500), got)
+
+			keyAccessor, err := ctxdatapoint.PathGetSetter(filteredAttributeKeyPath)
+			require.NoError(t, err)
+			require.NoError(t, keyAccessor.Set(t.Context(), ctx, "value"))
+			got, err = keyAccessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "value", got)
+
+			mapAccessor, err := ctxdatapoint.PathGetSetter(exemplarPath(0, "filtered_attributes"))
+			require.NoError(t, err)
+			got, err = mapAccessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"key": "value"}, got.(pcommon.Map).AsRaw())
+
+			var exemplar pmetric.Exemplar
+			switch dp := dp.(type) {
+			case pmetric.NumberDataPoint:
+				exemplar = dp.Exemplars().At(0)
+			case pmetric.HistogramDataPoint:
+				exemplar = dp.Exemplars().At(0)
+			case pmetric.ExponentialHistogramDataPoint:
+				exemplar = dp.Exemplars().At(0)
+			}
+			assert.Equal(t, pcommon.Timestamp(1_000_000_500), exemplar.Timestamp())
+			assert.Equal(t, map[string]any{"key": "value"}, exemplar.FilteredAttributes().AsRaw())
+
+			outOfBounds, err := ctxdatapoint.PathGetSetter(exemplarPath(1, "time_unix_nano"))
+			require.NoError(t, err)
+			_, err = outOfBounds.Get(t.Context(), ctx)
+			assert.ErrorContains(t, err, "index 1 out of bounds")
+		})
+	}
+}
+
+// customDataPoint is a data point type unknown to the context, exposing only attributes.
+type customDataPoint struct {
+	attributes pcommon.Map
+}
+
+func (dp customDataPoint) Attributes() pcommon.Map {
+	return dp.attributes
+}
+
+func TestPathGetSetter_CustomDataPointAttributes(t *testing.T) {
+	dp := customDataPoint{attributes: pcommon.NewMap()}
+	dp.attributes.PutStr("str", "val")
+	ctx := newTestContext(dp)
+
+	accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{N: "attributes"})
+	assert.NoError(t, err)
+	got, err := accessor.Get(t.Context(), ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, dp.attributes, got)
+
+	err = accessor.Set(t.Context(), ctx, createAttributeMap())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"hello": "world"}, dp.attributes.AsRaw())
+
+	keyAccessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{
+		N: "attributes",
+		KeySlice: []ottl.Key[*testContext]{
+			&pathtest.Key[*testContext]{S: ottltest.Strp("hello")},
+		},
+	})
+	assert.NoError(t, err)
+	got, err = keyAccessor.Get(t.Context(), ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", got)
+
+	err = keyAccessor.Set(t.Context(), ctx, "there")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"hello": "there"}, dp.attributes.AsRaw())
+}
+
+func TestPathGetSetter_ScaleRange(t *testing.T) {
+	accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{N: "scale"})
+	require.NoError(t, err)
+
+	expoHistogramDataPoint := pmetric.NewExponentialHistogramDataPoint()
+	ctx := newTestContext(expoHistogramDataPoint)
+
+	for _, scale := range []int64{-10, 0, 20} {
+		require.NoError(t, accessor.Set(t.Context(), ctx, scale))
+		assert.Equal(t, int32(scale), expoHistogramDataPoint.Scale())
+	}
+
+	for scale, want := range map[int64]int32{21: 20, math.MaxInt32 + 1: 20, -11: -10, math.MinInt64: -10} {
+		require.NoError(t, accessor.Set(t.Context(), ctx, scale))
+		assert.Equal(t, want, expoHistogramDataPoint.Scale())
+	}
+}
+
+func TestPathGetSetter_ZeroThresholdOtherDataPoints(t *testing.T) {
+	accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{N: "zero_threshold"})
+	require.NoError(t, err)
+
+	for _, dataPoint := range []any{
+		createNumberDataPoint(pmetric.NumberDataPointValueTypeDouble),
+		createHistogramDataPointTelemetry(),
+		createSummaryDataPointTelemetry(),
+	} {
+		ctx := newTestContext(dataPoint)
+		got, err := accessor.Get(t.Context(), ctx)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		require.NoError(t, accessor.Set(t.Context(), ctx, 1.5))
+		got, err = accessor.Get(t.Context(), ctx)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	}
+}
+
+func TestPathGetSetter_BucketOffsetRange(t *testing.T) {
+	for _, bucket := range []string{"positive", "negative"} {
+		t.Run(bucket, func(t *testing.T) {
+			path := &pathtest.Path[*testContext]{N: bucket, NextPath: &pathtest.Path[*testContext]{N: "offset"}}
+			accessor, err := ctxdatapoint.PathGetSetter(path)
+			require.NoError(t, err)
+
+			expoHistogramDataPoint := pmetric.NewExponentialHistogramDataPoint()
+			ctx := newTestContext(expoHistogramDataPoint)
+
+			require.NoError(t, accessor.Set(t.Context(), ctx, int64(math.MinInt32)))
+			got, err := accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(math.MinInt32), got)
+
+			require.NoError(t, accessor.Set(t.Context(), ctx, int64(42)))
+			got, err = accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), got)
+
+			for _, outOfRange := range []int64{math.MaxInt32 + 1, math.MinInt32 - 1} {
+				err = accessor.Set(t.Context(), ctx, outOfRange)
+				assert.ErrorContains(t, err, "out of the int32 range")
+				got, err = accessor.Get(t.Context(), ctx)
+				require.NoError(t, err)
+				assert.Equal(t, int64(42), got)
+			}
+		})
+	}
+}
+
+func bucketCountsAtPath(bound float64) *pathtest.Path[*testContext] {
+	return &pathtest.Path[*testContext]{
+		N: "bucket_counts_at",
+		KeySlice: []ottl.Key[*testContext]{
+			&pathtest.Key[*testContext]{
+				G: ottl.StandardGetSetter[*testContext]{
+					Getter: func(context.Context, *testContext) (any, error) {
+						return bound, nil
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPathGetSetter_BucketCountsAt(t *testing.T) {
+	histogramDataPoint := pmetric.NewHistogramDataPoint()
+	histogramDataPoint.ExplicitBounds().FromRaw([]float64{0.5, 1, 2.5})
+	histogramDataPoint.BucketCounts().FromRaw([]uint64{3, 5, 7, 11})
+	ctx := newTestContext(histogramDataPoint)
+
+	tests := []struct {
+		name     string
+		path     ottl.Path[*testContext]
+		expected any
+	}{
+		{
+			name:     "float bound",
+			path:     bucketCountsAtPath(2.5),
+			expected: int64(7),
+		},
+		{
+			name: "int bound",
+			path: &pathtest.Path[*testContext]{
+				N:        "bucket_counts_at",
+				KeySlice: []ottl.Key[*testContext]{&pathtest.Key[*testContext]{I: ottltest.Intp(1)}},
+			},
+			expected: int64(5),
+		},
+		{
+			name:     "absent bound",
+			path:     bucketCountsAtPath(5),
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := ctxdatapoint.PathGetSetter(tt.path)
+			require.NoError(t, err)
+			got, err := accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("non histogram data point", func(t *testing.T) {
+		accessor, err := ctxdatapoint.PathGetSetter(bucketCountsAtPath(1))
+		require.NoError(t, err)
+		got, err := accessor.Get(t.Context(), newTestContext(pmetric.NewNumberDataPoint()))
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		accessor, err := ctxdatapoint.PathGetSetter(bucketCountsAtPath(1))
+		require.NoError(t, err)
+		assert.Error(t, accessor.Set(t.Context(), ctx, int64(1)))
+	})
+}
+
+func TestPathGetSetter_HistogramFields(t *testing.T) {
+	createDataPoint := func() pmetric.HistogramDataPoint {
+		histogramDataPoint := pmetric.NewHistogramDataPoint()
+		histogramDataPoint.SetCount(4)
+		histogramDataPoint.SetSum(10)
+		histogramDataPoint.SetMin(1)
+		histogramDataPoint.SetMax(4)
+		histogramDataPoint.ExplicitBounds().FromRaw([]float64{2})
+		histogramDataPoint.BucketCounts().FromRaw([]uint64{1, 3})
+		return histogramDataPoint
+	}
+	path := &pathtest.Path[*testContext]{N: "histogram_fields"}
+
+	t.Run("get", func(t *testing.T) {
+		accessor, err := ctxdatapoint.PathGetSetter(path)
+		require.NoError(t, err)
+		got, err := accessor.Get(t.Context(), newTestContext(createDataPoint()))
+		require.NoError(t, err)
+		require.IsType(t, pcommon.Map{}, got)
+		assert.Equal(t, map[string]any{"count": int64(4), "sum": float64(10), "min": float64(1), "max": float64(4)}, got.(pcommon.Map).AsRaw())
+
+		got, err = accessor.Get(t.Context(), newTestContext(pmetric.NewHistogramDataPoint()))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"count": int64(0)}, got.(pcommon.Map).AsRaw())
+	})
+
+	tests := []struct {
+		name     string
+		fields   map[string]any
+		expected func(pmetric.HistogramDataPoint)
+		wantErr  string
+	}{
+		{
+			name:   "consistent batch",
+			fields: map[string]any{"sum": 12.5, "min": 2, "max": 5.5},
+			expected: func(dp pmetric.HistogramDataPoint) {
+				dp.SetSum(12.5)
+				dp.SetMin(2)
+				dp.SetMax(5.5)
+			},
+		},
+		{
+			name:   "remove min and max",
+			fields: map[string]any{"min": nil, "max": nil, "sum": 100.0},
+			expected: func(dp pmetric.HistogramDataPoint) {
+				dp.RemoveMin()
+				dp.RemoveMax()
+				dp.SetSum(100)
+			},
+		},
+		{
+			name:    "count doesn't match bucket counts",
+			fields:  map[string]any{"count": 5, "sum": 11.0},
+			wantErr: "histogram count 5 doesn't match the total of its bucket counts 4",
+		},
+		{
+			name:    "min greater than max",
+			fields:  map[string]any{"min": 3.0, "max": 2.0},
+			wantErr: "histogram min 3 is greater than its max 2",
+		},
+		{
+			name:    "sum greater than count times max",
+			fields:  map[string]any{"sum": 17.0},
+			wantErr: "histogram sum 17 is greater than count 4 times its max 4",
+		},
+		{
+			name:    "sum less than count times min",
+			fields:  map[string]any{"sum": 3.0},
+			wantErr: "histogram sum 3 is less than count 4 times its min 1",
+		},
+		{
+			name:    "negative count",
+			fields:  map[string]any{"count": -1},
+			wantErr: "histogram count must be a non-negative int",
+		},
+		{
+			name:    "non-numeric sum",
+			fields:  map[string]any{"sum": "ten"},
+			wantErr: "histogram sum must be a number",
+		},
+		{
+			name:    "unknown field",
+			fields:  map[string]any{"zero_count": 1},
+			wantErr: `unknown histogram field "zero_count"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := ctxdatapoint.PathGetSetter(path)
+			require.NoError(t, err)
+
+			histogramDataPoint := createDataPoint()
+			err = accessor.Set(t.Context(), newTestContext(histogramDataPoint), tt.fields)
+
+			expected := createDataPoint()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				tt.expected(expected)
+			}
+			// an inconsistent batch leaves the data point unchanged
+			assert.Equal(t, expected, histogramDataPoint)
+		})
+	}
+
+	t.Run("non histogram data point", func(t *testing.T) {
+		accessor, err := ctxdatapoint.PathGetSetter(path)
+		require.NoError(t, err)
+		numberDataPoint := pmetric.NewNumberDataPoint()
+		got, err := accessor.Get(t.Context(), newTestContext(numberDataPoint))
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		require.NoError(t, accessor.Set(t.Context(), newTestContext(numberDataPoint), map[string]any{"count": 1}))
+		assert.Equal(t, pmetric.NewNumberDataPoint(), numberDataPoint)
+	})
+}
+
+func hasAttributePath(key string) *pathtest.Path[*testContext] {
+	return &pathtest.Path[*testContext]{
+		N:        "has_attribute",
+		KeySlice: []ottl.Key[*testContext]{&pathtest.Key[*testContext]{S: ottltest.Strp(key)}},
+	}
+}
+
+func TestPathGetSetter_HasAttribute(t *testing.T) {
+	numberDataPoint := pmetric.NewNumberDataPoint()
+	numberDataPoint.Attributes().PutEmpty("null")
+	numberDataPoint.Attributes().PutStr("str", "val")
+	ctx := newTestContext(numberDataPoint)
+
+	tests := []struct {
+		name     string
+		key      string
+		expected bool
+	}{
+		{
+			name:     "absent key",
+			key:      "absent",
+			expected: false,
+		},
+		{
+			name:     "present key with null value",
+			key:      "null",
+			expected: true,
+		},
+		{
+			name:     "present key with value",
+			key:      "str",
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := ctxdatapoint.PathGetSetter(hasAttributePath(tt.key))
+			require.NoError(t, err)
+			got, err := accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("null value is indistinguishable from absent key through attributes", func(t *testing.T) {
+		for _, key := range []string{"absent", "null"} {
+			accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{
+				N:        "attributes",
+				KeySlice: []ottl.Key[*testContext]{&pathtest.Key[*testContext]{S: ottltest.Strp(key)}},
+			})
+			require.NoError(t, err)
+			got, err := accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Nil(t, got)
+		}
+	})
+
+	t.Run("expression key", func(t *testing.T) {
+		accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{
+			N: "has_attribute",
+			KeySlice: []ottl.Key[*testContext]{
+				&pathtest.Key[*testContext]{
+					G: ottl.StandardGetSetter[*testContext]{
+						Getter: func(context.Context, *testContext) (any, error) {
+							return "str", nil
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		got, err := accessor.Get(t.Context(), ctx)
+		require.NoError(t, err)
+		assert.True(t, got.(bool))
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		accessor, err := ctxdatapoint.PathGetSetter(hasAttributePath("str"))
+		require.NoError(t, err)
+		assert.Error(t, accessor.Set(t.Context(), ctx, true))
+	})
+}
+
+func TestValidatePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    ottl.Path[*testContext]
+		wantErr bool
+	}{
+		{name: "nil", path: nil, wantErr: true},
+		{name: "attributes", path: &pathtest.Path[*testContext]{N: "attributes"}},
+		{name: "attributes key", path: &pathtest.Path[*testContext]{N: "attributes", KeySlice: []ottl.Key[*testContext]{&pathtest.Key[*testContext]{S: ottltest.Strp("foo")}}}},
+		{name: "has_attribute", path: hasAttributePath("foo")},
+		{name: "has_attribute without key", path: &pathtest.Path[*testContext]{N: "has_attribute"}, wantErr: true},
+		{name: "start_time_unix_nano", path: &pathtest.Path[*testContext]{N: "start_time_unix_nano"}},
+		{name: "time_unix_nano", path: &pathtest.Path[*testContext]{N: "time_unix_nano"}},
+		{name: "start_time", path: &pathtest.Path[*testContext]{N: "start_time"}},
+		{name: "time", path: &pathtest.Path[*testContext]{N: "time"}},
+		{name: "start_time_after_time", path: &pathtest.Path[*testContext]{N: "start_time_after_time"}},
+		{name: "value_double", path: &pathtest.Path[*testContext]{N: "value_double"}},
+		{name: "value_int", path: &pathtest.Path[*testContext]{N: "value_int"}},
+		{name: "exemplars", path: &pathtest.Path[*testContext]{N: "exemplars"}},
+		{name: "exemplars trace_id", path: exemplarPath(0, "trace_id")},
+		{name: "exemplars span_id", path: exemplarPath(0, "span_id")},
+		{name: "exemplars time_unix_nano", path: exemplarPath(0, "time_unix_nano")},
+		{name: "exemplars filtered_attributes", path: exemplarPath(0, "filtered_attributes")},
+		{name: "exemplars invalid", path: exemplarPath(0, "invalid"), wantErr: true},
+		{name: "exemplars index without field", path: &pathtest.Path[*testContext]{N: "exemplars", KeySlice: []ottl.Key[*testContext]{&pathtest.Key[*testContext]{I: ottltest.Intp(0)}}}, wantErr: true},
+		{name: "flags", path: &pathtest.Path[*testContext]{N: "flags"}},
+		{name: "count", path: &pathtest.Path[*testContext]{N: "count"}},
+		{name: "sample_count", path: &pathtest.Path[*testContext]{N: "sample_count"}},
+		{name: "sum", path: &pathtest.Path[*testContext]{N: "sum"}},
+		{name: "bucket_counts", path: &pathtest.Path[*testContext]{N: "bucket_counts"}},
+		{name: "bucket_counts_at", path: bucketCountsAtPath(1)},
+		{name: "bucket_counts_at without bound", path: &pathtest.Path[*testContext]{N: "bucket_counts_at"}, wantErr: true},
+		{name: "histogram_fields", path: &pathtest.Path[*testContext]{N: "histogram_fields"}},
+		{name: "explicit_bounds", path: &pathtest.Path[*testContext]{N: "explicit_bounds"}},
+		{name: "scale", path: &pathtest.Path[*testContext]{N: "scale"}},
+		{name: "zero_count", path: &pathtest.Path[*testContext]{N: "zero_count"}},
+		{name: "zero_threshold", path: &pathtest.Path[*testContext]{N: "zero_threshold"}},
+		{name: "positive", path: &pathtest.Path[*testContext]{N: "positive"}},
+		{name: "positive offset", path: &pathtest.Path[*testContext]{N: "positive", NextPath: &pathtest.Path[*testContext]{N: "offset"}}},
+		{name: "positive bucket_counts", path: &pathtest.Path[*testContext]{N: "positive", NextPath: &pathtest.Path[*testContext]{N: "bucket_counts"}}},
+		{name: "positive invalid", path: &pathtest.Path[*testContext]{N: "positive", NextPath: &pathtest.Path[*testContext]{N: "invalid"}}, wantErr: true},
+		{name: "negative", path: &pathtest.Path[*testContext]{N: "negative"}},
+		{name: "negative offset", path: &pathtest.Path[*testContext]{N: "negative", NextPath: &pathtest.Path[*testContext]{N: "offset"}}},
+		{name: "negative bucket_counts", path: &pathtest.Path[*testContext]{N: "negative", NextPath: &pathtest.Path[*testContext]{N: "bucket_counts"}}},
+		{name: "negative invalid", path: &pathtest.Path[*testContext]{N: "negative", NextPath: &pathtest.Path[*testContext]{N: "invalid"}}, wantErr: true},
+		{name: "quantile_values", path: &pathtest.Path[*testContext]{N: "quantile_values"}},
+		{name: "invalid", path: &pathtest.Path[*testContext]{N: "invalid"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ctxdatapoint.ValidatePath(tt.path)
+			_, wantErr := ctxdatapoint.PathGetSetter(tt.path)
+			assert.Equal(t, wantErr, err)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func createAttributeTelemetry(attributes pcommon.Map) {
 	attributes.PutStr("str", "val")
 	attributes.PutBool("bool", true)
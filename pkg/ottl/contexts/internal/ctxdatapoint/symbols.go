@@ -6,15 +6,32 @@ package ctxdatapoint // import "github.com/open-telemetry/opentelemetry-collecto
 import (
 	"maps"
 
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/ctxmetric"
 )
 
+const (
+	flagNoneSymbol            ottl.EnumSymbol = "FLAG_NONE"
+	flagNoRecordedValueSymbol ottl.EnumSymbol = "FLAG_NO_RECORDED_VALUE"
+)
+
 var SymbolTable = func() map[ottl.EnumSymbol]ottl.Enum {
 	st := map[ottl.EnumSymbol]ottl.Enum{
-		"FLAG_NONE":              0,
-		"FLAG_NO_RECORDED_VALUE": 1,
+		// The values match the int64 returned by the flags path.
+		flagNoneSymbol:            ottl.Enum(pmetric.DefaultDataPointFlags),
+		flagNoRecordedValueSymbol: ottl.Enum(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true)),
 	}
 	maps.Copy(st, ctxmetric.SymbolTable)
 	return st
 }()
+
+// FlagSymbols returns the symbols of the flags set in the given data point flags, as returned
+// by the flags path, or FLAG_NONE if no flag is set. Unknown flags are ignored.
+func FlagSymbols(flags int64) []ottl.EnumSymbol {
+	if pmetric.DataPointFlags(flags).NoRecordedValue() {
+		return []ottl.EnumSymbol{flagNoRecordedValueSymbol}
+	}
+	return []ottl.EnumSymbol{flagNoneSymbol}
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ctxdatapoint_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/ctxdatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/pathtest"
+)
+
+func TestFlagsMatchSymbols(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       pmetric.DataPointFlags
+		wantSymbol  ottl.EnumSymbol
+		wantSymbols []ottl.EnumSymbol
+	}{
+		{
+			name:        "none",
+			flags:       pmetric.DefaultDataPointFlags,
+			wantSymbol:  "FLAG_NONE",
+			wantSymbols: []ottl.EnumSymbol{"FLAG_NONE"},
+		},
+		{
+			name:        "no recorded value",
+			flags:       pmetric.DefaultDataPointFlags.WithNoRecordedValue(true),
+			wantSymbol:  "FLAG_NO_RECORDED_VALUE",
+			wantSymbols: []ottl.EnumSymbol{"FLAG_NO_RECORDED_VALUE"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := pmetric.NewNumberDataPoint()
+			dp.SetFlags(tt.flags)
+
+			accessor, err := ctxdatapoint.PathGetSetter(&pathtest.Path[*testContext]{N: "flags"})
+			require.NoError(t, err)
+			got, err := accessor.Get(context.Background(), newTestContext(dp))
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(ctxdatapoint.SymbolTable[tt.wantSymbol]), got)
+			assert.Equal(t, tt.wantSymbols, ctxdatapoint.FlagSymbols(got.(int64)))
+		})
+	}
+}
+
+func TestFlagSymbols_UnknownFlags(t *testing.T) {
+	assert.Equal(t, []ottl.EnumSymbol{"FLAG_NONE"}, ctxdatapoint.FlagSymbols(2))
+	assert.Equal(t, []ottl.EnumSymbol{"FLAG_NO_RECORDED_VALUE"}, ctxdatapoint.FlagSymbols(3))
+}
@@ -5,6 +5,7 @@ package ctxmetric // import "github.com/open-telemetry/opentelemetry-collector-c
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
@@ -32,6 +33,8 @@ func PathGetSetter[K Context](path ottl.Path[K]) (ottl.GetSetter[K], error) {
 		return accessIsMonotonic[K](), nil
 	case "data_points":
 		return accessDataPoints[K](), nil
+	case "data_point_count":
+		return accessDataPointCount[K](), nil
 	case "metadata":
 		if path.Keys() == nil {
 			return accessMetadata[K](), nil
@@ -109,7 +112,8 @@ func accessAggTemporality[K Context]() ottl.StandardGetSetter[K] {
 			case pmetric.MetricTypeExponentialHistogram:
 				return int64(metric.ExponentialHistogram().AggregationTemporality()), nil
 			}
-			return nil, nil
+			// gauges and summaries have no aggregation temporality
+			return int64(pmetric.AggregationTemporalityUnspecified), nil
 		},
 		Setter: func(_ context.Context, tCtx K, val any) error {
 			if newAggTemporality, ok := val.(int64); ok {
@@ -196,6 +200,30 @@ func accessDataPoints[K Context]() ottl.StandardGetSetter[K] {
 	}
 }
 
+func accessDataPointCount[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			metric := tCtx.GetMetric()
+			switch metric.Type() {
+			case pmetric.MetricTypeSum:
+				return int64(metric.Sum().DataPoints().Len()), nil
+			case pmetric.MetricTypeGauge:
+				return int64(metric.Gauge().DataPoints().Len()), nil
+			case pmetric.MetricTypeHistogram:
+				return int64(metric.Histogram().DataPoints().Len()), nil
+			case pmetric.MetricTypeExponentialHistogram:
+				return int64(metric.ExponentialHistogram().DataPoints().Len()), nil
+			case pmetric.MetricTypeSummary:
+				return int64(metric.Summary().DataPoints().Len()), nil
+			}
+			return int64(0), nil
+		},
+		Setter: func(context.Context, K, any) error {
+			return errors.New("data_point_count is read-only, set data_points instead")
+		},
+	}
+}
+
 func accessMetadata[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
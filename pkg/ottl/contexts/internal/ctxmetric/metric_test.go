@@ -145,6 +145,127 @@ func TestPathGetSetter(t *testing.T) {
 	}
 }
 
+func TestPathGetSetter_AggTemporalityUnspecified(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric func(pmetric.Metric)
+	}{
+		{
+			name: "gauge",
+			metric: func(metric pmetric.Metric) {
+				metric.SetEmptyGauge()
+			},
+		},
+		{
+			name: "summary",
+			metric: func(metric pmetric.Metric) {
+				metric.SetEmptySummary()
+			},
+		},
+		{
+			name:   "empty",
+			metric: func(pmetric.Metric) {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := ctxmetric.PathGetSetter(&pathtest.Path[*testContext]{
+				N: "aggregation_temporality",
+			})
+			assert.NoError(t, err)
+
+			metric := pmetric.NewMetric()
+			tt.metric(metric)
+
+			got, err := accessor.Get(t.Context(), newTestContext(metric))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(pmetric.AggregationTemporalityUnspecified), got)
+
+			// setting the temporality of a metric without one is a no-op
+			err = accessor.Set(t.Context(), newTestContext(metric), int64(pmetric.AggregationTemporalityDelta))
+			assert.NoError(t, err)
+			expectedMetric := pmetric.NewMetric()
+			tt.metric(expectedMetric)
+			assert.Equal(t, expectedMetric, metric)
+		})
+	}
+}
+
+func TestPathGetSetter_DataPointCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric func(metric pmetric.Metric, count int)
+	}{
+		{
+			name: "sum",
+			metric: func(metric pmetric.Metric, count int) {
+				metric.SetEmptySum()
+				for range count {
+					metric.Sum().DataPoints().AppendEmpty()
+				}
+			},
+		},
+		{
+			name: "gauge",
+			metric: func(metric pmetric.Metric, count int) {
+				metric.SetEmptyGauge()
+				for range count {
+					metric.Gauge().DataPoints().AppendEmpty()
+				}
+			},
+		},
+		{
+			name: "histogram",
+			metric: func(metric pmetric.Metric, count int) {
+				metric.SetEmptyHistogram()
+				for range count {
+					metric.Histogram().DataPoints().AppendEmpty()
+				}
+			},
+		},
+		{
+			name: "exponential histogram",
+			metric: func(metric pmetric.Metric, count int) {
+				metric.SetEmptyExponentialHistogram()
+				for range count {
+					metric.ExponentialHistogram().DataPoints().AppendEmpty()
+				}
+			},
+		},
+		{
+			name: "summary",
+			metric: func(metric pmetric.Metric, count int) {
+				metric.SetEmptySummary()
+				for range count {
+					metric.Summary().DataPoints().AppendEmpty()
+				}
+			},
+		},
+	}
+	accessor, err := ctxmetric.PathGetSetter(&pathtest.Path[*testContext]{
+		N: "data_point_count",
+	})
+	assert.NoError(t, err)
+
+	for _, tt := range tests {
+		for _, count := range []int{0, 1, 3} {
+			metric := pmetric.NewMetric()
+			tt.metric(metric, count)
+
+			got, err := accessor.Get(t.Context(), newTestContext(metric))
+			assert.NoError(t, err, tt.name)
+			assert.Equal(t, int64(count), got, tt.name)
+
+			err = accessor.Set(t.Context(), newTestContext(metric), int64(5))
+			assert.EqualError(t, err, "data_point_count is read-only, set data_points instead", tt.name)
+		}
+	}
+
+	got, err := accessor.Get(t.Context(), newTestContext(pmetric.NewMetric()))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), got)
+}
+
 func createTelemetry() pmetric.Metric {
 	metric := pmetric.NewMetric()
 	metric.SetName("name")
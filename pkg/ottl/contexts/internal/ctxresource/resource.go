@@ -5,6 +5,10 @@ package ctxresource // import "github.com/open-telemetry/opentelemetry-collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/ctxerror"
@@ -21,6 +25,8 @@ func PathGetSetter[K Context](path ottl.Path[K]) (ottl.GetSetter[K], error) {
 			return accessResourceAttributes[K](), nil
 		}
 		return accessResourceAttributesKey[K](path.Keys()), nil
+	case "attributes_count":
+		return accessResourceAttributesCount[K](), nil
 	case "dropped_attributes_count":
 		return accessResourceDroppedAttributesCount[K](), nil
 	case "schema_url":
@@ -30,6 +36,35 @@ func PathGetSetter[K Context](path ottl.Path[K]) (ottl.GetSetter[K], error) {
 	}
 }
 
+// ResourceGetSetter returns an accessor for the resource as a whole. Setting it copies the
+// given resource into the current one.
+func ResourceGetSetter[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetResource(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			newResource, ok := val.(pcommon.Resource)
+			if !ok {
+				return fmt.Errorf("cannot set resource from a value of type %T", val)
+			}
+			newResource.CopyTo(tCtx.GetResource())
+			return nil
+		},
+	}
+}
+
+// WithResourcePath extends parser with the bare resource path, which accesses the resource
+// as a whole.
+func WithResourcePath[K Context](parser ottl.PathExpressionParser[K]) ottl.PathExpressionParser[K] {
+	return func(path ottl.Path[K]) (ottl.GetSetter[K], error) {
+		if path != nil && path.Context() == "" && path.Name() == Name && path.Keys() == nil && path.Next() == nil {
+			return ResourceGetSetter[K](), nil
+		}
+		return parser(path)
+	}
+}
+
 func accessResourceAttributes[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -52,6 +87,17 @@ func accessResourceAttributesKey[K Context](keys []ottl.Key[K]) ottl.StandardGet
 	}
 }
 
+func accessResourceAttributesCount[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return int64(tCtx.GetResource().Attributes().Len()), nil
+		},
+		Setter: func(context.Context, K, any) error {
+			return errors.New("attributes_count is read-only, set attributes instead")
+		},
+	}
+}
+
 func accessResourceDroppedAttributesCount[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
@@ -4,6 +4,7 @@
 package ctxresource_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -344,6 +345,66 @@ func TestPathGetSetter(t *testing.T) {
 	}
 }
 
+func TestPathGetSetter_AttributesCount(t *testing.T) {
+	accessor, err := ctxresource.PathGetSetter[*testContext](&pathtest.Path[*testContext]{N: "attributes_count"})
+	assert.NoError(t, err)
+
+	for _, count := range []int{0, 1, 3} {
+		resource := pcommon.NewResource()
+		for i := range count {
+			resource.Attributes().PutInt(fmt.Sprintf("attr%d", i), int64(i))
+		}
+
+		got, err := accessor.Get(t.Context(), newTestContext(resource))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(count), got)
+	}
+
+	resource := createResource()
+	got, err := accessor.Get(t.Context(), newTestContext(resource))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(resource.Attributes().Len()), got)
+
+	err = accessor.Set(t.Context(), newTestContext(resource), int64(1))
+	assert.EqualError(t, err, "attributes_count is read-only, set attributes instead")
+	assert.Equal(t, createResource(), resource)
+}
+
+func TestResourceGetSetter(t *testing.T) {
+	resource := createResource()
+	accessor := ctxresource.ResourceGetSetter[*testContext]()
+
+	got, err := accessor.Get(t.Context(), newTestContext(resource))
+	assert.NoError(t, err)
+	assert.Equal(t, resource, got)
+
+	newResource := pcommon.NewResource()
+	newResource.Attributes().PutStr("service.name", "other")
+	newResource.SetDroppedAttributesCount(5)
+	assert.NoError(t, accessor.Set(t.Context(), newTestContext(resource), newResource))
+	assert.Equal(t, newResource, resource)
+
+	err = accessor.Set(t.Context(), newTestContext(resource), "invalid")
+	assert.EqualError(t, err, "cannot set resource from a value of type string")
+	assert.Equal(t, newResource, resource)
+}
+
+func TestWithResourcePath(t *testing.T) {
+	parser := ctxresource.WithResourcePath(ctxresource.PathGetSetter[*testContext])
+
+	resource := createResource()
+	accessor, err := parser(&pathtest.Path[*testContext]{N: "resource"})
+	assert.NoError(t, err)
+	got, err := accessor.Get(t.Context(), newTestContext(resource))
+	assert.NoError(t, err)
+	assert.Equal(t, resource, got)
+
+	_, err = parser(&pathtest.Path[*testContext]{N: "resource", KeySlice: []ottl.Key[*testContext]{&pathtest.Key[*testContext]{S: ottltest.Strp("foo")}}})
+	assert.Error(t, err)
+	_, err = parser(&pathtest.Path[*testContext]{N: "schema_url"})
+	assert.NoError(t, err)
+}
+
 func createResource() pcommon.Resource {
 	resource := pcommon.NewResource()
 	resource.Attributes().PutStr("str", "val")
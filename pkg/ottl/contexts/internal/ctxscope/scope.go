@@ -5,6 +5,7 @@ package ctxscope // import "github.com/open-telemetry/opentelemetry-collector-co
 
 import (
 	"context"
+	"errors"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/ctxerror"
@@ -26,6 +27,8 @@ func PathGetSetter[K Context](path ottl.Path[K]) (ottl.GetSetter[K], error) {
 			return accessInstrumentationScopeAttributes[K](), nil
 		}
 		return accessInstrumentationScopeAttributesKey[K](mapKeys), nil
+	case "attributes_count":
+		return accessInstrumentationScopeAttributesCount[K](), nil
 	case "dropped_attributes_count":
 		return accessInstrumentationScopeDroppedAttributesCount[K](), nil
 	case "schema_url":
@@ -57,6 +60,17 @@ func accessInstrumentationScopeAttributesKey[K Context](keys []ottl.Key[K]) ottl
 	}
 }
 
+func accessInstrumentationScopeAttributesCount[K Context]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return int64(tCtx.GetInstrumentationScope().Attributes().Len()), nil
+		},
+		Setter: func(context.Context, K, any) error {
+			return errors.New("attributes_count is read-only, set attributes instead")
+		},
+	}
+}
+
 func accessInstrumentationScopeName[K Context]() ottl.StandardGetSetter[K] {
 	return ottl.StandardGetSetter[K]{
 		Getter: func(_ context.Context, tCtx K) (any, error) {
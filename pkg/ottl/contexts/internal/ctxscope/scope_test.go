@@ -4,6 +4,7 @@
 package ctxscope_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -382,6 +383,31 @@ func TestPathGetSetter(t *testing.T) {
 	}
 }
 
+func TestPathGetSetter_AttributesCount(t *testing.T) {
+	accessor, err := ctxscope.PathGetSetter[*testContext](&pathtest.Path[*testContext]{N: "attributes_count"})
+	assert.NoError(t, err)
+
+	for _, count := range []int{0, 1, 3} {
+		is := pcommon.NewInstrumentationScope()
+		for i := range count {
+			is.Attributes().PutInt(fmt.Sprintf("attr%d", i), int64(i))
+		}
+
+		got, err := accessor.Get(t.Context(), newTestContext(is))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(count), got)
+	}
+
+	is := createInstrumentationScope()
+	got, err := accessor.Get(t.Context(), newTestContext(is))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(is.Attributes().Len()), got)
+
+	err = accessor.Set(t.Context(), newTestContext(is), int64(1))
+	assert.EqualError(t, err, "attributes_count is read-only, set attributes instead")
+	assert.Equal(t, createInstrumentationScope(), is)
+}
+
 func createInstrumentationScope() pcommon.InstrumentationScope {
 	is := pcommon.NewInstrumentationScope()
 	is.SetName("library")
@@ -189,6 +189,30 @@ func NewParser(
 	)
 }
 
+// ValidatePath checks whether the path can be parsed by the datapoint context parser,
+// returning the same errors the parser would, without building the datapoint accessor.
+// It is meant to be used by tooling that needs to validate statements without executing them.
+//
+// Experimental: *NOTE* this function is subject to change or removal in the future.
+func ValidatePath(path ottl.Path[TransformContext]) error {
+	_, err := pathValidator(path)
+	return err
+}
+
+var pathValidator = withDataPointPath(ctxresource.WithResourcePath(ctxcommon.PathExpressionParser(
+	ctxdatapoint.Name,
+	ctxdatapoint.DocRef,
+	getCache,
+	map[string]ottl.PathExpressionParser[TransformContext]{
+		ctxresource.Name:    ctxresource.PathGetSetter[TransformContext],
+		ctxscope.Name:       ctxscope.PathGetSetter[TransformContext],
+		ctxscope.LegacyName: ctxscope.PathGetSetter[TransformContext],
+		ctxmetric.Name:      ctxmetric.PathGetSetter[TransformContext],
+		ctxdatapoint.Name: func(path ottl.Path[TransformContext]) (ottl.GetSetter[TransformContext], error) {
+			return nil, ctxdatapoint.ValidatePath(path)
+		},
+	})))
+
 func parseEnum(val *ottl.EnumSymbol) (*ottl.Enum, error) {
 	if val != nil {
 		if enum, ok := ctxdatapoint.SymbolTable[*val]; ok {
@@ -199,12 +223,19 @@ func parseEnum(val *ottl.EnumSymbol) (*ottl.Enum, error) {
 	return nil, errors.New("enum symbol not provided")
 }
 
+// FlagSymbols returns the names of the enum symbols matching the flags set in the given data
+// point flags, as returned by the flags path, or FLAG_NONE if no flag is set. Unknown flags are
+// ignored. It can be used to render the flags of a data point to their symbolic names.
+func FlagSymbols(flags int64) []ottl.EnumSymbol {
+	return ctxdatapoint.FlagSymbols(flags)
+}
+
 func getCache(tCtx TransformContext) pcommon.Map {
 	return tCtx.cache
 }
 
 func pathExpressionParser(cacheGetter ctxcache.Getter[TransformContext]) ottl.PathExpressionParser[TransformContext] {
-	return ctxcommon.PathExpressionParser(
+	return withDataPointPath(ctxresource.WithResourcePath(ctxcommon.PathExpressionParser(
 		ctxdatapoint.Name,
 		ctxdatapoint.DocRef,
 		cacheGetter,
@@ -214,5 +245,16 @@ func pathExpressionParser(cacheGetter ctxcache.Getter[TransformContext]) ottl.Pa
 			ctxscope.LegacyName: ctxscope.PathGetSetter[TransformContext],
 			ctxmetric.Name:      ctxmetric.PathGetSetter[TransformContext],
 			ctxdatapoint.Name:   ctxdatapoint.PathGetSetter[TransformContext],
-		})
+		})))
+}
+
+// withDataPointPath extends parser with the bare datapoint path, which accesses the
+// data point being processed as a whole.
+func withDataPointPath(parser ottl.PathExpressionParser[TransformContext]) ottl.PathExpressionParser[TransformContext] {
+	return func(path ottl.Path[TransformContext]) (ottl.GetSetter[TransformContext], error) {
+		if path != nil && path.Context() == "" && path.Name() == ctxdatapoint.Name && path.Keys() == nil && path.Next() == nil {
+			return ctxdatapoint.DataPointGetSetter[TransformContext](), nil
+		}
+		return parser(path)
+	}
 }
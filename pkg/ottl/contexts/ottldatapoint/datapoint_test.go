@@ -2221,6 +2221,19 @@ func Test_ParseEnum_False(t *testing.T) {
 	}
 }
 
+func Test_FlagSymbols(t *testing.T) {
+	for _, flags := range []pmetric.DataPointFlags{
+		pmetric.DefaultDataPointFlags,
+		pmetric.DefaultDataPointFlags.WithNoRecordedValue(true),
+	} {
+		symbols := FlagSymbols(int64(flags))
+		require.Len(t, symbols, 1)
+		enum, err := parseEnum(&symbols[0])
+		require.NoError(t, err)
+		assert.Equal(t, ottl.Enum(flags), *enum)
+	}
+}
+
 func Test_newPathGetSetter_higherContextPath(t *testing.T) {
 	resource := pcommon.NewResource()
 	resource.Attributes().PutStr("foo", "bar")
@@ -2309,3 +2322,100 @@ func Test_newPathGetSetter_higherContextPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_newPathGetSetter_SchemaURL(t *testing.T) {
+	scopeMetrics := pmetric.NewScopeMetrics()
+	scopeMetrics.SetSchemaUrl("https://opentelemetry.io/schemas/1.25.0")
+	resourceMetrics := pmetric.NewResourceMetrics()
+	resourceMetrics.SetSchemaUrl("https://opentelemetry.io/schemas/1.26.0")
+
+	ctx := NewTransformContext(
+		pmetric.NewNumberDataPoint(),
+		pmetric.NewMetric(),
+		pmetric.NewMetricSlice(),
+		pcommon.NewInstrumentationScope(),
+		pcommon.NewResource(),
+		scopeMetrics,
+		resourceMetrics)
+
+	tests := []struct {
+		name     string
+		path     ottl.Path[TransformContext]
+		orig     string
+		newVal   string
+		modified func() string
+	}{
+		{
+			name:     "resource schema_url",
+			path:     &pathtest.Path[TransformContext]{N: "resource", NextPath: &pathtest.Path[TransformContext]{N: "schema_url"}},
+			orig:     "https://opentelemetry.io/schemas/1.26.0",
+			newVal:   "https://opentelemetry.io/schemas/1.27.0",
+			modified: resourceMetrics.SchemaUrl,
+		},
+		{
+			name:     "instrumentation_scope schema_url",
+			path:     &pathtest.Path[TransformContext]{C: "instrumentation_scope", N: "schema_url"},
+			orig:     "https://opentelemetry.io/schemas/1.25.0",
+			newVal:   "https://opentelemetry.io/schemas/1.27.0",
+			modified: scopeMetrics.SchemaUrl,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := pathExpressionParser(getCache)(tt.path)
+			require.NoError(t, err)
+
+			got, err := accessor.Get(t.Context(), ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.orig, got)
+
+			require.NoError(t, accessor.Set(t.Context(), ctx, tt.newVal))
+			assert.Equal(t, tt.newVal, tt.modified())
+		})
+	}
+}
+
+func Test_ValidatePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    ottl.Path[TransformContext]
+		wantErr bool
+	}{
+		{name: "nil", path: nil, wantErr: true},
+		{name: "datapoint", path: &pathtest.Path[TransformContext]{N: "value_int"}},
+		{name: "datapoint with context", path: &pathtest.Path[TransformContext]{C: "datapoint", N: "positive", NextPath: &pathtest.Path[TransformContext]{N: "offset"}}},
+		{name: "datapoint invalid", path: &pathtest.Path[TransformContext]{C: "datapoint", N: "invalid"}, wantErr: true},
+		{name: "datapoint item", path: &pathtest.Path[TransformContext]{N: "datapoint"}},
+		{name: "datapoint item with keys", path: &pathtest.Path[TransformContext]{N: "datapoint", KeySlice: []ottl.Key[TransformContext]{&pathtest.Key[TransformContext]{I: ottltest.Intp(0)}}}, wantErr: true},
+		{name: "cache", path: &pathtest.Path[TransformContext]{N: "cache"}},
+		{name: "cache with context", path: &pathtest.Path[TransformContext]{C: "datapoint", N: "cache"}},
+		{name: "cache on higher context", path: &pathtest.Path[TransformContext]{C: "metric", N: "cache"}, wantErr: true},
+		{name: "metric", path: &pathtest.Path[TransformContext]{N: "metric", NextPath: &pathtest.Path[TransformContext]{N: "name"}}},
+		{name: "metric with context", path: &pathtest.Path[TransformContext]{C: "metric", N: "name"}},
+		{name: "metric invalid", path: &pathtest.Path[TransformContext]{C: "metric", N: "invalid"}, wantErr: true},
+		{name: "metric without field", path: &pathtest.Path[TransformContext]{N: "metric"}, wantErr: true},
+		{name: "resource", path: &pathtest.Path[TransformContext]{N: "resource", NextPath: &pathtest.Path[TransformContext]{N: "attributes"}}},
+		{name: "resource with context", path: &pathtest.Path[TransformContext]{C: "resource", N: "attributes"}},
+		{name: "resource invalid", path: &pathtest.Path[TransformContext]{C: "resource", N: "invalid"}, wantErr: true},
+		{name: "resource item", path: &pathtest.Path[TransformContext]{N: "resource"}},
+		{name: "resource item with keys", path: &pathtest.Path[TransformContext]{N: "resource", KeySlice: []ottl.Key[TransformContext]{&pathtest.Key[TransformContext]{I: ottltest.Intp(0)}}}, wantErr: true},
+		{name: "scope", path: &pathtest.Path[TransformContext]{N: "scope", NextPath: &pathtest.Path[TransformContext]{N: "name"}}},
+		{name: "scope with context", path: &pathtest.Path[TransformContext]{C: "scope", N: "version"}},
+		{name: "instrumentation_scope", path: &pathtest.Path[TransformContext]{N: "instrumentation_scope", NextPath: &pathtest.Path[TransformContext]{N: "name"}}},
+		{name: "instrumentation_scope with context", path: &pathtest.Path[TransformContext]{C: "instrumentation_scope", N: "name"}},
+		{name: "unknown context", path: &pathtest.Path[TransformContext]{C: "log", N: "body"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePath(tt.path)
+			_, wantErr := pathExpressionParser(getCache)(tt.path)
+			assert.Equal(t, wantErr, err)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -186,7 +186,7 @@ func getCache(tCtx TransformContext) pcommon.Map {
 }
 
 func pathExpressionParser(cacheGetter ctxcache.Getter[TransformContext]) ottl.PathExpressionParser[TransformContext] {
-	return ctxcommon.PathExpressionParser(
+	return ctxresource.WithResourcePath(ctxcommon.PathExpressionParser(
 		ctxmetric.Name,
 		ctxmetric.DocRef,
 		cacheGetter,
@@ -195,5 +195,5 @@ func pathExpressionParser(cacheGetter ctxcache.Getter[TransformContext]) ottl.Pa
 			ctxscope.Name:       ctxscope.PathGetSetter[TransformContext],
 			ctxscope.LegacyName: ctxscope.PathGetSetter[TransformContext],
 			ctxmetric.Name:      ctxmetric.PathGetSetter[TransformContext],
-		})
+		}))
 }
@@ -240,6 +240,11 @@ func Test_newPathGetSetter_higherContextPath(t *testing.T) {
 			}},
 			expected: "bar",
 		},
+		{
+			name:     "resource item",
+			path:     &pathtest.Path[TransformContext]{N: "resource"},
+			expected: resource,
+		},
 		{
 			name: "resource with context",
 			path: &pathtest.Path[TransformContext]{C: "resource", N: "attributes", KeySlice: []ottl.Key[TransformContext]{
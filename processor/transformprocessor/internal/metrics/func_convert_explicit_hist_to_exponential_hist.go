@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metrics"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
+)
+
+const (
+	minExponentialScale = -10
+	maxExponentialScale = 20
+	// maxExponentialBuckets is the default maximum number of buckets used by the OpenTelemetry SDKs.
+	// The scale is reduced until the buckets of each sign fit within this size.
+	maxExponentialBuckets = 160
+)
+
+type convertExplicitHistToExponentialHistArguments struct {
+	Scale int64
+}
+
+func newConvertExplicitHistToExponentialHistFactory() ottl.Factory[ottlmetric.TransformContext] {
+	return ottl.NewFactory("convert_histogram_to_exponential_histogram",
+		&convertExplicitHistToExponentialHistArguments{}, createConvertExplicitHistToExponentialHistFunction)
+}
+
+func createConvertExplicitHistToExponentialHistFunction(_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[ottlmetric.TransformContext], error) {
+	args, ok := oArgs.(*convertExplicitHistToExponentialHistArguments)
+
+	if !ok {
+		return nil, errors.New("convertExplicitHistToExponentialHistFactory args must be of type *convertExplicitHistToExponentialHistArguments")
+	}
+
+	return convertExplicitHistToExponentialHist(args.Scale)
+}
+
+// convertExplicitHistToExponentialHist converts a bucketed histogram to an exponential histogram
+func convertExplicitHistToExponentialHist(scale int64) (ottl.ExprFunc[ottlmetric.TransformContext], error) {
+	if scale < minExponentialScale || scale > maxExponentialScale {
+		return nil, fmt.Errorf("invalid scale: %d, must be between %d and %d", scale, minExponentialScale, maxExponentialScale)
+	}
+
+	return func(_ context.Context, tCtx ottlmetric.TransformContext) (any, error) {
+		metric := tCtx.GetMetric()
+
+		// only execute on explicit histograms
+		if metric.Type() != pmetric.MetricTypeHistogram {
+			return nil, nil
+		}
+
+		// create new metric and override metric
+		newMetric := pmetric.NewMetric()
+		newMetric.SetName(metric.Name())
+		newMetric.SetDescription(metric.Description())
+		newMetric.SetUnit(metric.Unit())
+		expHist := newMetric.SetEmptyExponentialHistogram()
+
+		dps := metric.Histogram().DataPoints()
+		expHist.SetAggregationTemporality(metric.Histogram().AggregationTemporality())
+
+		for i := 0; i < dps.Len(); i++ {
+			explicitDataPoint := dps.At(i)
+			expDataPoint := expHist.DataPoints().AppendEmpty()
+			expDataPoint.SetStartTimestamp(explicitDataPoint.StartTimestamp())
+			expDataPoint.SetTimestamp(explicitDataPoint.Timestamp())
+			expDataPoint.SetFlags(explicitDataPoint.Flags())
+			expDataPoint.SetCount(explicitDataPoint.Count())
+			if explicitDataPoint.HasSum() {
+				expDataPoint.SetSum(explicitDataPoint.Sum())
+			}
+			if explicitDataPoint.HasMin() {
+				expDataPoint.SetMin(explicitDataPoint.Min())
+			}
+			if explicitDataPoint.HasMax() {
+				expDataPoint.SetMax(explicitDataPoint.Max())
+			}
+			calculateExponentialBuckets(explicitDataPoint, expDataPoint, int32(scale))
+			explicitDataPoint.Exemplars().MoveAndAppendTo(expDataPoint.Exemplars())
+			explicitDataPoint.Attributes().MoveTo(expDataPoint.Attributes())
+		}
+
+		newMetric.MoveTo(metric)
+
+		return nil, nil
+	}, nil
+}
+
+// calculateExponentialBuckets allocates the count of each explicit bucket to the exponential bucket containing a
+// single representative value of the explicit bucket, see bucketRepresentative. Exponential buckets are wider or
+// narrower than the explicit ones depending on the scale, so the resulting distribution is only an approximation,
+// but the total count is always preserved.
+func calculateExponentialBuckets(src pmetric.HistogramDataPoint, dst pmetric.ExponentialHistogramDataPoint, scale int32) {
+	positive := map[int32]uint64{}
+	negative := map[int32]uint64{}
+	zeroCount := uint64(0)
+
+	bucketCounts := src.BucketCounts()
+	for i := 0; i < bucketCounts.Len(); i++ {
+		count := bucketCounts.At(i)
+		if count == 0 {
+			continue
+		}
+		value := bucketRepresentative(src, i)
+		switch {
+		case value > 0:
+			positive[exponentialBucketIndex(value, scale)] += count
+		case value < 0:
+			negative[exponentialBucketIndex(-value, scale)] += count
+		default:
+			zeroCount += count
+		}
+	}
+
+	// reduce the scale until the buckets of both signs fit; halving the scale merges pairs of adjacent buckets
+	for scale > minExponentialScale && (exponentialBucketSpan(positive) > maxExponentialBuckets || exponentialBucketSpan(negative) > maxExponentialBuckets) {
+		scale--
+		positive = downscaleExponentialBuckets(positive)
+		negative = downscaleExponentialBuckets(negative)
+	}
+
+	dst.SetScale(scale)
+	dst.SetZeroCount(zeroCount)
+	setExponentialBuckets(positive, dst.Positive())
+	setExponentialBuckets(negative, dst.Negative())
+}
+
+// bucketRepresentative returns the value used to place the count of the explicit bucket at index i. It is the
+// midpoint of the bucket bounds. The unbounded first and last buckets are bounded by the data point's min and max
+// when they are set, otherwise their only finite bound is used.
+func bucketRepresentative(dp pmetric.HistogramDataPoint, i int) float64 {
+	bounds := dp.ExplicitBounds()
+	lower, upper := math.Inf(-1), math.Inf(1)
+
+	switch {
+	case i > 0 && i <= bounds.Len():
+		lower = bounds.At(i - 1)
+	case i > bounds.Len() && bounds.Len() > 0:
+		lower = bounds.At(bounds.Len() - 1)
+	case dp.HasMin():
+		lower = dp.Min()
+	}
+
+	switch {
+	case i < bounds.Len():
+		upper = bounds.At(i)
+	case dp.HasMax():
+		upper = dp.Max()
+	}
+
+	switch {
+	case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+		return 0
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	}
+	return (lower + upper) / 2
+}
+
+// exponentialBucketIndex returns the index of the exponential bucket (base^index, base^(index+1)] holding the
+// positive value, where base = 2^(2^-scale).
+func exponentialBucketIndex(value float64, scale int32) int32 {
+	return int32(math.Ceil(math.Log(value)*math.Ldexp(math.Log2E, int(scale)))) - 1
+}
+
+func exponentialBucketSpan(buckets map[int32]uint64) int {
+	if len(buckets) == 0 {
+		return 0
+	}
+	low, high := exponentialBucketRange(buckets)
+	return int(high-low) + 1
+}
+
+func exponentialBucketRange(buckets map[int32]uint64) (int32, int32) {
+	low, high := int32(math.MaxInt32), int32(math.MinInt32)
+	for index := range buckets {
+		low = min(low, index)
+		high = max(high, index)
+	}
+	return low, high
+}
+
+func downscaleExponentialBuckets(buckets map[int32]uint64) map[int32]uint64 {
+	downscaled := make(map[int32]uint64, len(buckets))
+	for index, count := range buckets {
+		downscaled[index>>1] += count
+	}
+	return downscaled
+}
+
+func setExponentialBuckets(buckets map[int32]uint64, dst pmetric.ExponentialHistogramDataPointBuckets) {
+	if len(buckets) == 0 {
+		return
+	}
+	low, high := exponentialBucketRange(buckets)
+	counts := make([]uint64, high-low+1)
+	for index, count := range buckets {
+		counts[index-low] = count
+	}
+	dst.SetOffset(low)
+	dst.BucketCounts().FromRaw(counts)
+}
@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
+)
+
+func Test_convertExplicitHistToExponentialHist(t *testing.T) {
+	ts := pcommon.NewTimestampFromTime(time.Now())
+	defaultTestMetric := func() pmetric.Metric {
+		m := pmetric.NewMetric()
+		m.SetName("response_time")
+		m.SetUnit("ms")
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := m.Histogram().DataPoints().AppendEmpty()
+		dp.SetCount(10)
+		dp.SetSum(500)
+		dp.SetTimestamp(ts)
+		dp.Attributes().PutStr("metric_type", "timing")
+		dp.ExplicitBounds().FromRaw([]float64{0, 10, 100})
+		dp.BucketCounts().FromRaw([]uint64{1, 2, 3, 4})
+		return m
+	}
+
+	tests := []struct {
+		name  string
+		input func() pmetric.Metric
+		scale int64
+		want  func(pmetric.Metric)
+	}{
+		{
+			// (-inf, 0] is placed in the zero bucket, (0, 10] at its midpoint 5 in (4, 8],
+			// (10, 100] at its midpoint 55 in (32, 64] and (100, +inf) at its lower bound 100 in (64, 128]
+			name:  "convert histogram to exponential histogram",
+			input: defaultTestMetric,
+			scale: 0,
+			want: func(metric pmetric.Metric) {
+				metric.SetName("response_time")
+				metric.SetUnit("ms")
+				metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := metric.ExponentialHistogram().DataPoints().AppendEmpty()
+				dp.SetCount(10)
+				dp.SetSum(500)
+				dp.SetTimestamp(ts)
+				dp.Attributes().PutStr("metric_type", "timing")
+				dp.SetScale(0)
+				dp.SetZeroCount(1)
+				dp.Positive().SetOffset(2)
+				dp.Positive().BucketCounts().FromRaw([]uint64{2, 0, 0, 3, 4})
+			},
+		},
+		{
+			// the first and last buckets are bounded by min and max
+			name: "convert histogram with negative bounds to exponential histogram",
+			input: func() pmetric.Metric {
+				m := pmetric.NewMetric()
+				m.SetName("temperature")
+				dp := m.SetEmptyHistogram().DataPoints().AppendEmpty()
+				dp.SetCount(4)
+				dp.SetMin(-20)
+				dp.SetMax(20)
+				dp.ExplicitBounds().FromRaw([]float64{-10, 0, 10})
+				dp.BucketCounts().FromRaw([]uint64{1, 1, 1, 1})
+				return m
+			},
+			scale: 0,
+			want: func(metric pmetric.Metric) {
+				metric.SetName("temperature")
+				dp := metric.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+				dp.SetCount(4)
+				dp.SetMin(-20)
+				dp.SetMax(20)
+				dp.SetScale(0)
+				dp.Negative().SetOffset(2)
+				dp.Negative().BucketCounts().FromRaw([]uint64{1, 1})
+				dp.Positive().SetOffset(2)
+				dp.Positive().BucketCounts().FromRaw([]uint64{1, 1})
+			},
+		},
+		{
+			name: "empty histogram",
+			input: func() pmetric.Metric {
+				m := pmetric.NewMetric()
+				m.SetName("empty")
+				m.SetEmptyHistogram()
+				return m
+			},
+			scale: 0,
+			want: func(metric pmetric.Metric) {
+				metric.SetName("empty")
+				metric.SetEmptyExponentialHistogram()
+			},
+		},
+		{
+			name:  "non-histogram",
+			input: nonExponentialHist,
+			scale: 0,
+			want: func(metric pmetric.Metric) {
+				nonExponentialHist().CopyTo(metric)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := pmetric.NewMetric()
+			tt.input().CopyTo(metric)
+
+			ctx := ottlmetric.NewTransformContext(metric, pmetric.NewMetricSlice(), pcommon.NewInstrumentationScope(), pcommon.NewResource(), pmetric.NewScopeMetrics(), pmetric.NewResourceMetrics())
+
+			exprFunc, err := convertExplicitHistToExponentialHist(tt.scale)
+			require.NoError(t, err)
+			_, err = exprFunc(nil, ctx)
+			require.NoError(t, err)
+
+			expected := pmetric.NewMetric()
+			tt.want(expected)
+
+			assert.Equal(t, expected, metric)
+		})
+	}
+}
+
+func Test_convertExplicitHistToExponentialHist_preservesCount(t *testing.T) {
+	for scale := int64(minExponentialScale); scale <= maxExponentialScale; scale++ {
+		metric := pmetric.NewMetric()
+		dp := metric.SetEmptyHistogram().DataPoints().AppendEmpty()
+		dp.SetCount(55)
+		dp.ExplicitBounds().FromRaw([]float64{-1000, -5, -0.5, 0, 0.001, 1, 2.5, 10, 1e6})
+		dp.BucketCounts().FromRaw([]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+		ctx := ottlmetric.NewTransformContext(metric, pmetric.NewMetricSlice(), pcommon.NewInstrumentationScope(), pcommon.NewResource(), pmetric.NewScopeMetrics(), pmetric.NewResourceMetrics())
+
+		exprFunc, err := convertExplicitHistToExponentialHist(scale)
+		require.NoError(t, err)
+		_, err = exprFunc(nil, ctx)
+		require.NoError(t, err)
+
+		require.Equal(t, pmetric.MetricTypeExponentialHistogram, metric.Type())
+		expDp := metric.ExponentialHistogram().DataPoints().At(0)
+		assert.LessOrEqual(t, expDp.Scale(), int32(scale))
+		assert.LessOrEqual(t, expDp.Positive().BucketCounts().Len(), maxExponentialBuckets)
+		assert.LessOrEqual(t, expDp.Negative().BucketCounts().Len(), maxExponentialBuckets)
+
+		total := expDp.ZeroCount()
+		for _, count := range expDp.Positive().BucketCounts().AsRaw() {
+			total += count
+		}
+		for _, count := range expDp.Negative().BucketCounts().AsRaw() {
+			total += count
+		}
+		assert.Equal(t, expDp.Count(), total, "scale %d", scale)
+	}
+}
+
+func Test_convertExplicitHistToExponentialHist_downscale(t *testing.T) {
+	metric := pmetric.NewMetric()
+	dp := metric.SetEmptyHistogram().DataPoints().AppendEmpty()
+	dp.SetCount(2)
+	dp.ExplicitBounds().FromRaw([]float64{1, 10, 100})
+	dp.BucketCounts().FromRaw([]uint64{0, 1, 1, 0})
+
+	ctx := ottlmetric.NewTransformContext(metric, pmetric.NewMetricSlice(), pcommon.NewInstrumentationScope(), pcommon.NewResource(), pmetric.NewScopeMetrics(), pmetric.NewResourceMetrics())
+
+	exprFunc, err := convertExplicitHistToExponentialHist(maxExponentialScale)
+	require.NoError(t, err)
+	_, err = exprFunc(nil, ctx)
+	require.NoError(t, err)
+
+	// the midpoints 5.5 and 55 are a decade apart, which needs more than 160 buckets above scale 5
+	expDp := metric.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, int32(5), expDp.Scale())
+	counts := expDp.Positive().BucketCounts()
+	require.LessOrEqual(t, counts.Len(), maxExponentialBuckets)
+	assert.Equal(t, uint64(1), counts.At(0))
+	assert.Equal(t, uint64(1), counts.At(counts.Len()-1))
+}
+
+func Test_convertExplicitHistToExponentialHist_validate(t *testing.T) {
+	for _, scale := range []int64{minExponentialScale - 1, maxExponentialScale + 1} {
+		_, err := convertExplicitHistToExponentialHist(scale)
+		assert.ErrorContains(t, err, "invalid scale")
+	}
+}
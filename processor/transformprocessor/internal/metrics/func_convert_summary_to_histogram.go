@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metrics"
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
+)
+
+func newConvertSummaryToHistogramFactory() ottl.Factory[ottlmetric.TransformContext] {
+	return ottl.NewFactory("convert_summary_to_histogram", nil, createConvertSummaryToHistogramFunction)
+}
+
+func createConvertSummaryToHistogramFunction(_ ottl.FunctionContext, _ ottl.Arguments) (ottl.ExprFunc[ottlmetric.TransformContext], error) {
+	return convertSummaryToHistogram()
+}
+
+// convertSummaryToHistogram converts a summary to a bucketed histogram
+func convertSummaryToHistogram() (ottl.ExprFunc[ottlmetric.TransformContext], error) {
+	return func(_ context.Context, tCtx ottlmetric.TransformContext) (any, error) {
+		metric := tCtx.GetMetric()
+
+		// only execute on summaries
+		if metric.Type() != pmetric.MetricTypeSummary {
+			return nil, nil
+		}
+
+		// create new metric and override metric
+		newMetric := pmetric.NewMetric()
+		newMetric.SetName(metric.Name())
+		newMetric.SetDescription(metric.Description())
+		newMetric.SetUnit(metric.Unit())
+		histogram := newMetric.SetEmptyHistogram()
+		// summaries are always cumulative
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			summaryDataPoint := dps.At(i)
+			histogramDataPoint := histogram.DataPoints().AppendEmpty()
+			histogramDataPoint.SetStartTimestamp(summaryDataPoint.StartTimestamp())
+			histogramDataPoint.SetTimestamp(summaryDataPoint.Timestamp())
+			histogramDataPoint.SetFlags(summaryDataPoint.Flags())
+			histogramDataPoint.SetCount(summaryDataPoint.Count())
+			histogramDataPoint.SetSum(summaryDataPoint.Sum())
+			calculateQuantileBuckets(summaryDataPoint, histogramDataPoint)
+			summaryDataPoint.Attributes().MoveTo(histogramDataPoint.Attributes())
+		}
+
+		newMetric.MoveTo(metric)
+
+		return nil, nil
+	}, nil
+}
+
+// calculateQuantileBuckets derives the explicit bounds of the histogram from the values of the summary's quantiles.
+// A summary only records that roughly quantile * count observations are less than or equal to the quantile's value,
+// so that number is used as the cumulative count of the bucket bounded by the value. The resulting bucket counts are
+// an approximation, but the total count is always preserved. The values of the 0 and 1 quantiles are used as the
+// min and max of the histogram.
+func calculateQuantileBuckets(src pmetric.SummaryDataPoint, dst pmetric.HistogramDataPoint) {
+	quantiles := make([]pmetric.SummaryDataPointValueAtQuantile, 0, src.QuantileValues().Len())
+	for i := 0; i < src.QuantileValues().Len(); i++ {
+		q := src.QuantileValues().At(i)
+		if q.Quantile() < 0 || q.Quantile() > 1 || math.IsNaN(q.Value()) || math.IsInf(q.Value(), 0) {
+			continue
+		}
+		quantiles = append(quantiles, q)
+	}
+	sort.SliceStable(quantiles, func(i, j int) bool {
+		return quantiles[i].Quantile() < quantiles[j].Quantile()
+	})
+
+	count := src.Count()
+	bounds := make([]float64, 0, len(quantiles))
+	cumulativeCounts := make([]uint64, 0, len(quantiles))
+	for _, q := range quantiles {
+		switch q.Quantile() {
+		case 0:
+			dst.SetMin(q.Value())
+		case 1:
+			dst.SetMax(q.Value())
+		}
+
+		cumulativeCount := min(uint64(math.Round(q.Quantile()*float64(count))), count)
+		// bounds must be strictly increasing, a quantile whose value doesn't exceed the previous bound
+		// is merged into the previous bucket
+		if n := len(bounds); n > 0 && q.Value() <= bounds[n-1] {
+			cumulativeCounts[n-1] = max(cumulativeCounts[n-1], cumulativeCount)
+			continue
+		}
+		if n := len(cumulativeCounts); n > 0 {
+			cumulativeCount = max(cumulativeCount, cumulativeCounts[n-1])
+		}
+		bounds = append(bounds, q.Value())
+		cumulativeCounts = append(cumulativeCounts, cumulativeCount)
+	}
+
+	bucketCounts := make([]uint64, len(bounds)+1)
+	previous := uint64(0)
+	for i, cumulativeCount := range cumulativeCounts {
+		bucketCounts[i] = cumulativeCount - previous
+		previous = cumulativeCount
+	}
+	// the remaining observations are greater than the highest quantile value
+	bucketCounts[len(bounds)] = count - previous
+
+	dst.ExplicitBounds().FromRaw(bounds)
+	dst.BucketCounts().FromRaw(bucketCounts)
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
+)
+
+func Test_convertSummaryToHistogram(t *testing.T) {
+	ts := pcommon.NewTimestampFromTime(time.Now())
+
+	tests := []struct {
+		name  string
+		input func() pmetric.Metric
+		want  func(pmetric.Metric)
+	}{
+		{
+			name: "convert summary to histogram",
+			input: func() pmetric.Metric {
+				m := pmetric.NewMetric()
+				m.SetName("response_time")
+				m.SetUnit("ms")
+				m.SetDescription("response time")
+				dp := m.SetEmptySummary().DataPoints().AppendEmpty()
+				dp.SetCount(10)
+				dp.SetSum(55)
+				dp.SetTimestamp(ts)
+				dp.Attributes().PutStr("metric_type", "timing")
+				for _, q := range [][2]float64{{0.9, 9}, {0, 1}, {0.5, 5}, {1, 10}} {
+					qv := dp.QuantileValues().AppendEmpty()
+					qv.SetQuantile(q[0])
+					qv.SetValue(q[1])
+				}
+				return m
+			},
+			want: func(metric pmetric.Metric) {
+				metric.SetName("response_time")
+				metric.SetUnit("ms")
+				metric.SetDescription("response time")
+				metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := metric.Histogram().DataPoints().AppendEmpty()
+				dp.SetCount(10)
+				dp.SetSum(55)
+				dp.SetMin(1)
+				dp.SetMax(10)
+				dp.SetTimestamp(ts)
+				dp.Attributes().PutStr("metric_type", "timing")
+				dp.ExplicitBounds().FromRaw([]float64{1, 5, 9, 10})
+				dp.BucketCounts().FromRaw([]uint64{0, 5, 4, 1, 0})
+			},
+		},
+		{
+			// the values of the higher quantiles don't exceed the value of the 0.5 quantile,
+			// so they are merged into its bucket
+			name:  "convert summary with non increasing quantile values to histogram",
+			input: getTestSummaryMetric,
+			want: func(metric pmetric.Metric) {
+				metric.SetName("summary_metric")
+				metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := metric.Histogram().DataPoints().AppendEmpty()
+				dp.SetCount(100)
+				dp.SetSum(12.34)
+				getTestAttributes().CopyTo(dp.Attributes())
+				dp.ExplicitBounds().FromRaw([]float64{3})
+				dp.BucketCounts().FromRaw([]uint64{99, 1})
+			},
+		},
+		{
+			name: "convert summary without quantiles to histogram",
+			input: func() pmetric.Metric {
+				m := pmetric.NewMetric()
+				m.SetName("no_quantiles")
+				dp := m.SetEmptySummary().DataPoints().AppendEmpty()
+				dp.SetCount(3)
+				dp.SetSum(6)
+				qv := dp.QuantileValues().AppendEmpty()
+				qv.SetQuantile(0.5)
+				qv.SetValue(math.NaN())
+				return m
+			},
+			want: func(metric pmetric.Metric) {
+				metric.SetName("no_quantiles")
+				metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := metric.Histogram().DataPoints().AppendEmpty()
+				dp.SetCount(3)
+				dp.SetSum(6)
+				dp.BucketCounts().FromRaw([]uint64{3})
+			},
+		},
+		{
+			name:  "non-summary",
+			input: getTestGaugeMetric,
+			want: func(metric pmetric.Metric) {
+				getTestGaugeMetric().CopyTo(metric)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := pmetric.NewMetric()
+			tt.input().CopyTo(metric)
+
+			ctx := ottlmetric.NewTransformContext(metric, pmetric.NewMetricSlice(), pcommon.NewInstrumentationScope(), pcommon.NewResource(), pmetric.NewScopeMetrics(), pmetric.NewResourceMetrics())
+
+			exprFunc, err := convertSummaryToHistogram()
+			require.NoError(t, err)
+			_, err = exprFunc(nil, ctx)
+			require.NoError(t, err)
+
+			expected := pmetric.NewMetric()
+			tt.want(expected)
+
+			assert.Equal(t, expected, metric)
+		})
+	}
+}
+
+func Test_convertSummaryToHistogram_preservesCountAndSum(t *testing.T) {
+	metric := pmetric.NewMetric()
+	dps := metric.SetEmptySummary().DataPoints()
+	for _, count := range []uint64{0, 1, 7, 1000} {
+		dp := dps.AppendEmpty()
+		dp.SetCount(count)
+		dp.SetSum(float64(count) * 1.5)
+		for _, q := range [][2]float64{{0.25, 0.5}, {0.5, 1}, {0.75, 1.5}, {0.99, 3}} {
+			qv := dp.QuantileValues().AppendEmpty()
+			qv.SetQuantile(q[0])
+			qv.SetValue(q[1])
+		}
+	}
+
+	ctx := ottlmetric.NewTransformContext(metric, pmetric.NewMetricSlice(), pcommon.NewInstrumentationScope(), pcommon.NewResource(), pmetric.NewScopeMetrics(), pmetric.NewResourceMetrics())
+
+	exprFunc, err := convertSummaryToHistogram()
+	require.NoError(t, err)
+	_, err = exprFunc(nil, ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, pmetric.MetricTypeHistogram, metric.Type())
+	histDps := metric.Histogram().DataPoints()
+	require.Equal(t, 4, histDps.Len())
+	for i := 0; i < histDps.Len(); i++ {
+		dp := histDps.At(i)
+		assert.Equal(t, float64(dp.Count())*1.5, dp.Sum())
+		assert.Equal(t, []float64{0.5, 1, 1.5, 3}, dp.ExplicitBounds().AsRaw())
+
+		total := uint64(0)
+		for _, count := range dp.BucketCounts().AsRaw() {
+			total += count
+		}
+		assert.Equal(t, dp.Count(), total)
+	}
+}
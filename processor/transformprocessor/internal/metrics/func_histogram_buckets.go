@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metrics"
+
+import (
+	"context"
+	"math"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+)
+
+const (
+	histogramBucketUpperBoundKey = "upper_bound"
+	histogramBucketCountKey      = "count"
+)
+
+func newHistogramBucketsFactory() ottl.Factory[ottldatapoint.TransformContext] {
+	return ottl.NewFactory("HistogramBuckets", nil, createHistogramBucketsFunction)
+}
+
+func createHistogramBucketsFunction(_ ottl.FunctionContext, _ ottl.Arguments) (ottl.ExprFunc[ottldatapoint.TransformContext], error) {
+	return histogramBuckets()
+}
+
+func histogramBuckets() (ottl.ExprFunc[ottldatapoint.TransformContext], error) {
+	return func(_ context.Context, tCtx ottldatapoint.TransformContext) (any, error) {
+		histogramDataPoint, ok := tCtx.GetDataPoint().(pmetric.HistogramDataPoint)
+		if !ok {
+			return nil, nil
+		}
+		return histogramBucketsFromDataPoint(histogramDataPoint), nil
+	}, nil
+}
+
+// histogramBucketsFromDataPoint returns a slice with a map holding the upper bound and the count
+// of each bucket of the data point. The upper bound of the last bucket is +Inf. No buckets are returned if the
+// number of bounds and counts don't match.
+func histogramBucketsFromDataPoint(dp pmetric.HistogramDataPoint) pcommon.Slice {
+	buckets := pcommon.NewSlice()
+	explicitBounds := dp.ExplicitBounds()
+	bucketCounts := dp.BucketCounts()
+	if bucketCounts.Len() == 0 || explicitBounds.Len()+1 != bucketCounts.Len() {
+		return buckets
+	}
+
+	buckets.EnsureCapacity(bucketCounts.Len())
+	for i, count := range bucketCounts.All() {
+		upperBound := math.Inf(1)
+		if i < explicitBounds.Len() {
+			upperBound = explicitBounds.At(i)
+		}
+		bucket := buckets.AppendEmpty().SetEmptyMap()
+		bucket.PutDouble(histogramBucketUpperBoundKey, upperBound)
+		bucket.PutInt(histogramBucketCountKey, int64(count))
+	}
+	return buckets
+}
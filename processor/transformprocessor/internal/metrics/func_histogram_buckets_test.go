@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	tests := []struct {
+		name      string
+		dataPoint any
+		want      any
+	}{
+		{
+			name: "histogram with +Inf bucket",
+			dataPoint: func() any {
+				dp := pmetric.NewHistogramDataPoint()
+				dp.ExplicitBounds().FromRaw([]float64{0.1, 0.5, 1})
+				dp.BucketCounts().FromRaw([]uint64{5, 8, 3, 1})
+				return dp
+			}(),
+			want: []any{
+				map[string]any{"upper_bound": 0.1, "count": int64(5)},
+				map[string]any{"upper_bound": 0.5, "count": int64(8)},
+				map[string]any{"upper_bound": 1.0, "count": int64(3)},
+				map[string]any{"upper_bound": math.Inf(1), "count": int64(1)},
+			},
+		},
+		{
+			name: "single +Inf bucket",
+			dataPoint: func() any {
+				dp := pmetric.NewHistogramDataPoint()
+				dp.BucketCounts().FromRaw([]uint64{7})
+				return dp
+			}(),
+			want: []any{
+				map[string]any{"upper_bound": math.Inf(1), "count": int64(7)},
+			},
+		},
+		{
+			name:      "empty histogram",
+			dataPoint: pmetric.NewHistogramDataPoint(),
+			want:      []any{},
+		},
+		{
+			name: "mismatched bounds and counts",
+			dataPoint: func() any {
+				dp := pmetric.NewHistogramDataPoint()
+				dp.ExplicitBounds().FromRaw([]float64{0.1, 0.5})
+				dp.BucketCounts().FromRaw([]uint64{5, 8})
+				return dp
+			}(),
+			want: []any{},
+		},
+		{
+			name:      "not a histogram",
+			dataPoint: pmetric.NewNumberDataPoint(),
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := histogramBuckets()
+			require.NoError(t, err)
+
+			result, err := exprFunc(t.Context(), ottldatapoint.NewTransformContext(tt.dataPoint, pmetric.NewMetric(), pmetric.NewMetricSlice(), pcommon.NewInstrumentationScope(), pcommon.NewResource(), pmetric.NewScopeMetrics(), pmetric.NewResourceMetrics()))
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Nil(t, result)
+				return
+			}
+			require.IsType(t, pcommon.Slice{}, result)
+			assert.Equal(t, tt.want, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
@@ -28,6 +28,7 @@ func DataPointFunctions() map[string]ottl.Factory[ottldatapoint.TransformContext
 		newConvertSummarySumValToSumFactory(),
 		newConvertSummaryCountValToSumFactory(),
 		newMergeHistogramBucketsFactory(),
+		newHistogramBucketsFactory(),
 	)
 
 	maps.Copy(functions, datapointFunctions)
@@ -47,8 +48,10 @@ func MetricFunctions() map[string]ottl.Factory[ottlmetric.TransformContext] {
 		newScaleMetricFactory(),
 		newAggregateOnAttributesFactory(),
 		newconvertExponentialHistToExplicitHistFactory(),
+		newConvertExplicitHistToExponentialHistFactory(),
 		newAggregateOnAttributeValueFactory(),
 		newConvertSummaryQuantileValToGaugeFactory(),
+		newConvertSummaryToHistogramFactory(),
 	)
 
 	maps.Copy(functions, metricFunctions)
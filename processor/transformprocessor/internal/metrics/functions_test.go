@@ -37,6 +37,7 @@ func Test_DataPointFunctions(t *testing.T) {
 			expected["convert_summary_sum_val_to_sum"] = newConvertSummarySumValToSumFactory()
 			expected["convert_summary_count_val_to_sum"] = newConvertSummaryCountValToSumFactory()
 			expected["merge_histogram_buckets"] = newMergeHistogramBucketsFactory()
+			expected["HistogramBuckets"] = newHistogramBucketsFactory()
 
 			actual := DataPointFunctions()
 
@@ -60,7 +61,9 @@ func Test_MetricFunctions(t *testing.T) {
 	expected["copy_metric"] = newCopyMetricFactory()
 	expected["scale_metric"] = newScaleMetricFactory()
 	expected["convert_exponential_histogram_to_histogram"] = newconvertExponentialHistToExplicitHistFactory()
+	expected["convert_histogram_to_exponential_histogram"] = newConvertExplicitHistToExponentialHistFactory()
 	expected["convert_summary_quantile_val_to_gauge"] = newConvertSummaryQuantileValToGaugeFactory()
+	expected["convert_summary_to_histogram"] = newConvertSummaryToHistogramFactory()
 
 	actual := MetricFunctions()
 	require.Len(t, actual, len(expected))
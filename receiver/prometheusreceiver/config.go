@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"regexp"
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	commonconfig "github.com/prometheus/common/config"
@@ -22,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/config/configoptional"
 	"go.opentelemetry.io/collector/confmap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/targetallocator"
 )
 
@@ -41,6 +44,88 @@ type Config struct {
 	// ReportExtraScrapeMetrics - enables reporting of additional metrics for Prometheus client like scrape_body_size_bytes
 	ReportExtraScrapeMetrics bool `mapstructure:"report_extra_scrape_metrics"`
 
+	// AlignTimestampsToScrapeStart sets the timestamp of all data points of a scrape to the
+	// scrape start time instead of the per-sample timestamps. Start timestamps are preserved.
+	AlignTimestampsToScrapeStart bool `mapstructure:"align_timestamps_to_scrape_start"`
+
+	// ExcludedLabels lists label names which are dropped from all scraped samples, so that
+	// they are neither converted to data point attributes nor used to identify a series.
+	// The metric name, job, instance, le and quantile labels can't be excluded.
+	ExcludedLabels []string `mapstructure:"excluded_labels"`
+
+	// CumulativeToDelta converts monotonic cumulative sums (Prometheus counters) into delta
+	// sums by differencing the values of consecutive scrapes of each series.
+	CumulativeToDelta bool `mapstructure:"cumulative_to_delta"`
+
+	// PromoteTargetLabels adds the job, instance, scheme and metrics_path labels of each
+	// scrape target as resource attributes, in addition to service.name and service.instance.id.
+	PromoteTargetLabels bool `mapstructure:"promote_target_labels"`
+
+	// DisableMetricFamilyNormalization treats each metric name as its own metric family, instead
+	// of grouping metrics whose names only differ by a well-known suffix (e.g. _total) into one.
+	DisableMetricFamilyNormalization bool `mapstructure:"disable_metric_family_normalization"`
+
+	// NonFiniteValues selects how NaN and infinite counter and gauge values, other than the
+	// staleness marker, are handled: keep (default), drop or clamp.
+	NonFiniteValues string `mapstructure:"non_finite_values"`
+
+	// TrimMetricTypeSuffixes trims the type suffix (_total of counters) from metric names, independently
+	// of the unit suffix. TrimMetricSuffixes trims both.
+	TrimMetricTypeSuffixes bool `mapstructure:"trim_metric_type_suffixes"`
+
+	// TrimMetricUnitSuffixes trims the unit suffix (e.g. _seconds) from metric names, independently
+	// of the type suffix. TrimMetricSuffixes trims both.
+	TrimMetricUnitSuffixes bool `mapstructure:"trim_metric_unit_suffixes"`
+
+	// DetectMetricTypeChanges drops the samples of a metric family whose type differs from its type
+	// in previous scrapes of the same target.
+	DetectMetricTypeChanges bool `mapstructure:"detect_metric_type_changes"`
+
+	// MaxHistogramBuckets limits the number of buckets of each histogram data point, including the
+	// +Inf bucket. Buckets exceeding the limit are merged into the +Inf bucket. Zero means unlimited.
+	MaxHistogramBuckets int `mapstructure:"max_histogram_buckets"`
+
+	// TargetLabelAttributes adds the job, instance, scheme and metrics_path labels of each sample
+	// as data point attributes prefixed with "target.".
+	TargetLabelAttributes bool `mapstructure:"target_label_attributes"`
+
+	// MetricNameValidation selects the naming rules metric names are validated against: none
+	// (default), legacy or utf8. Samples with invalid metric names are dropped.
+	MetricNameValidation string `mapstructure:"metric_name_validation"`
+
+	// HistogramRepresentations selects which representation is kept when a histogram is exposed
+	// with both classic and native buckets: both (default), native or classic.
+	HistogramRepresentations string `mapstructure:"histogram_representations"`
+
+	// MetricNameRules rename or drop scraped samples by their metric name. The first matching
+	// rule applies.
+	MetricNameRules []MetricNameRule `mapstructure:"metric_name_rules"`
+
+	// FutureTimestamps selects how samples whose timestamp exceeds the current time by more than
+	// FutureTimestampTolerance are handled: keep (default), drop or clamp.
+	FutureTimestamps string `mapstructure:"future_timestamps"`
+
+	// FutureTimestampTolerance is how far the timestamp of a sample may exceed the current time
+	// before it is handled according to FutureTimestamps.
+	FutureTimestampTolerance time.Duration `mapstructure:"future_timestamp_tolerance"`
+
+	// SummaryAsGauges replaces each summary by a gauge holding a data point per quantile, with a
+	// quantile attribute, and the _count and _sum gauges of the summary.
+	SummaryAsGauges bool `mapstructure:"summary_as_gauges"`
+
+	// MaxExemplarsPerDataPoint limits the number of exemplars of each data point. Exemplars exceeding
+	// the limit are dropped. Zero means unlimited.
+	MaxExemplarsPerDataPoint int `mapstructure:"max_exemplars_per_data_point"`
+
+	// MaxExemplarLabels limits the number of labels of each exemplar, not counting the trace and
+	// span id. Labels exceeding the limit are dropped. Zero means unlimited.
+	MaxExemplarLabels int `mapstructure:"max_exemplar_labels"`
+
+	// MaxLabelValueLength limits the number of characters of label values. Longer values are truncated
+	// and the names of the truncated labels are listed in the otel.truncated_labels attribute. Zero
+	// means unlimited.
+	MaxLabelValueLength int `mapstructure:"max_label_value_length"`
+
 	TargetAllocator configoptional.Optional[targetallocator.Config] `mapstructure:"target_allocator"`
 
 	//  APIServer has the settings to enable the receiver to host the Prometheus API
@@ -49,6 +134,51 @@ type Config struct {
 	APIServer APIServer `mapstructure:"api_server"`
 }
 
+// Actions of a MetricNameRule.
+const (
+	metricNameRuleActionRename = "rename"
+	metricNameRuleActionDrop   = "drop"
+)
+
+// MetricNameRule renames or drops the scraped samples whose metric name matches a regular expression.
+type MetricNameRule struct {
+	// Regex is matched against the full metric name of each sample, including suffixes like
+	// _total or _bucket. It is anchored at both ends.
+	Regex string `mapstructure:"regex"`
+	// TargetName replaces the metric name of matching samples. It can reference the capture
+	// groups of Regex, e.g. ${1}. Required by the rename action.
+	TargetName string `mapstructure:"target_name"`
+	// Action is rename (default) or drop.
+	Action string `mapstructure:"action"`
+}
+
+// compileMetricNameRules validates the metric name rules and converts them into their internal representation.
+func compileMetricNameRules(rules []MetricNameRule) ([]internal.MetricNameRule, error) {
+	compiled := make([]internal.MetricNameRule, 0, len(rules))
+	for i, rule := range rules {
+		regex, err := regexp.Compile("^(?:" + rule.Regex + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("metric_name_rules[%d]: invalid regex: %w", i, err)
+		}
+		switch rule.Action {
+		case "", metricNameRuleActionRename:
+			if rule.TargetName == "" {
+				return nil, fmt.Errorf("metric_name_rules[%d]: target_name must be set for the %q action", i, metricNameRuleActionRename)
+			}
+		case metricNameRuleActionDrop:
+		default:
+			return nil, fmt.Errorf("metric_name_rules[%d]: action must be one of %q or %q, got %q",
+				i, metricNameRuleActionRename, metricNameRuleActionDrop, rule.Action)
+		}
+		compiled = append(compiled, internal.MetricNameRule{
+			Regex:      regex,
+			TargetName: rule.TargetName,
+			Drop:       rule.Action == metricNameRuleActionDrop,
+		})
+	}
+	return compiled, nil
+}
+
 // Validate checks the receiver configuration is valid.
 func (cfg *Config) Validate() error {
 	if !cfg.PrometheusConfig.ContainsScrapeConfigs() && !cfg.TargetAllocator.HasValue() {
@@ -59,6 +189,58 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("invalid API server configuration settings: %w", err)
 	}
 
+	switch cfg.NonFiniteValues {
+	case "", internal.NonFiniteValuesKeep, internal.NonFiniteValuesDrop, internal.NonFiniteValuesClamp:
+	default:
+		return fmt.Errorf("non_finite_values must be one of %q, %q or %q, got %q",
+			internal.NonFiniteValuesKeep, internal.NonFiniteValuesDrop, internal.NonFiniteValuesClamp, cfg.NonFiniteValues)
+	}
+
+	switch cfg.MetricNameValidation {
+	case "", internal.MetricNameValidationNone, internal.MetricNameValidationLegacy, internal.MetricNameValidationUTF8:
+	default:
+		return fmt.Errorf("metric_name_validation must be one of %q, %q or %q, got %q",
+			internal.MetricNameValidationNone, internal.MetricNameValidationLegacy, internal.MetricNameValidationUTF8, cfg.MetricNameValidation)
+	}
+
+	switch cfg.HistogramRepresentations {
+	case "", internal.HistogramRepresentationsBoth, internal.HistogramRepresentationsNative, internal.HistogramRepresentationsClassic:
+	default:
+		return fmt.Errorf("histogram_representations must be one of %q, %q or %q, got %q",
+			internal.HistogramRepresentationsBoth, internal.HistogramRepresentationsNative, internal.HistogramRepresentationsClassic, cfg.HistogramRepresentations)
+	}
+
+	switch cfg.FutureTimestamps {
+	case "", internal.FutureTimestampsKeep, internal.FutureTimestampsDrop, internal.FutureTimestampsClamp:
+	default:
+		return fmt.Errorf("future_timestamps must be one of %q, %q or %q, got %q",
+			internal.FutureTimestampsKeep, internal.FutureTimestampsDrop, internal.FutureTimestampsClamp, cfg.FutureTimestamps)
+	}
+
+	if cfg.FutureTimestampTolerance < 0 {
+		return fmt.Errorf("future_timestamp_tolerance must not be negative, got %s", cfg.FutureTimestampTolerance)
+	}
+
+	if _, err := compileMetricNameRules(cfg.MetricNameRules); err != nil {
+		return err
+	}
+
+	if cfg.MaxHistogramBuckets < 0 {
+		return fmt.Errorf("max_histogram_buckets must not be negative, got %d", cfg.MaxHistogramBuckets)
+	}
+
+	if cfg.MaxExemplarsPerDataPoint < 0 {
+		return fmt.Errorf("max_exemplars_per_data_point must not be negative, got %d", cfg.MaxExemplarsPerDataPoint)
+	}
+
+	if cfg.MaxExemplarLabels < 0 {
+		return fmt.Errorf("max_exemplar_labels must not be negative, got %d", cfg.MaxExemplarLabels)
+	}
+
+	if cfg.MaxLabelValueLength < 0 {
+		return fmt.Errorf("max_label_value_length must not be negative, got %d", cfg.MaxLabelValueLength)
+	}
+
 	return nil
 }
 
@@ -132,6 +132,215 @@ func TestValidateConfigWithScrapeConfigFiles(t *testing.T) {
 	require.NoError(t, xconfmap.Validate(cfg))
 }
 
+func TestValidateConfigNonFiniteValues(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	for _, mode := range []string{"", "keep", "drop", "clamp"} {
+		cfg := factory.CreateDefaultConfig()
+		sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+		require.NoError(t, err)
+		require.NoError(t, sub.Unmarshal(cfg))
+		cfg.(*Config).NonFiniteValues = mode
+		require.NoError(t, xconfmap.Validate(cfg), mode)
+	}
+
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	cfg.(*Config).NonFiniteValues = "ignore"
+	require.ErrorContains(t, xconfmap.Validate(cfg), `non_finite_values must be one of "keep", "drop" or "clamp", got "ignore"`)
+}
+
+func TestValidateConfigMaxHistogramBuckets(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	for _, maxBuckets := range []int{0, 1, 100} {
+		cfg := factory.CreateDefaultConfig()
+		sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+		require.NoError(t, err)
+		require.NoError(t, sub.Unmarshal(cfg))
+		cfg.(*Config).MaxHistogramBuckets = maxBuckets
+		require.NoError(t, xconfmap.Validate(cfg), maxBuckets)
+	}
+
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	cfg.(*Config).MaxHistogramBuckets = -1
+	require.ErrorContains(t, xconfmap.Validate(cfg), "max_histogram_buckets must not be negative, got -1")
+}
+
+func TestValidateConfigMetricNameValidation(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	for _, mode := range []string{"", "none", "legacy", "utf8"} {
+		cfg := factory.CreateDefaultConfig()
+		sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+		require.NoError(t, err)
+		require.NoError(t, sub.Unmarshal(cfg))
+		cfg.(*Config).MetricNameValidation = mode
+		require.NoError(t, xconfmap.Validate(cfg), mode)
+	}
+
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	cfg.(*Config).MetricNameValidation = "strict"
+	require.ErrorContains(t, xconfmap.Validate(cfg), `metric_name_validation must be one of "none", "legacy" or "utf8", got "strict"`)
+}
+
+func TestValidateConfigHistogramRepresentations(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	for _, mode := range []string{"", "both", "native", "classic"} {
+		cfg := factory.CreateDefaultConfig()
+		sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+		require.NoError(t, err)
+		require.NoError(t, sub.Unmarshal(cfg))
+		cfg.(*Config).HistogramRepresentations = mode
+		require.NoError(t, xconfmap.Validate(cfg), mode)
+	}
+
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	cfg.(*Config).HistogramRepresentations = "exponential"
+	require.ErrorContains(t, xconfmap.Validate(cfg), `histogram_representations must be one of "both", "native" or "classic", got "exponential"`)
+}
+
+func TestValidateConfigMetricNameRules(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	tests := []struct {
+		rule    MetricNameRule
+		wantErr string
+	}{
+		{rule: MetricNameRule{Regex: "http_(.*)", TargetName: "web_${1}"}},
+		{rule: MetricNameRule{Regex: "http_.*", TargetName: "web", Action: "rename"}},
+		{rule: MetricNameRule{Regex: "go_.*", Action: "drop"}},
+		{rule: MetricNameRule{Regex: "http_(", TargetName: "web"}, wantErr: "metric_name_rules[0]: invalid regex"},
+		{rule: MetricNameRule{Regex: "http_.*"}, wantErr: `metric_name_rules[0]: target_name must be set for the "rename" action`},
+		{rule: MetricNameRule{Regex: "http_.*", Action: "keep"}, wantErr: `metric_name_rules[0]: action must be one of "rename" or "drop", got "keep"`},
+	}
+	for _, tt := range tests {
+		cfg := factory.CreateDefaultConfig()
+		sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+		require.NoError(t, err)
+		require.NoError(t, sub.Unmarshal(cfg))
+		cfg.(*Config).MetricNameRules = []MetricNameRule{tt.rule}
+		if tt.wantErr == "" {
+			require.NoError(t, xconfmap.Validate(cfg), tt.rule.Regex)
+		} else {
+			require.ErrorContains(t, xconfmap.Validate(cfg), tt.wantErr)
+		}
+	}
+}
+
+func TestValidateConfigFutureTimestamps(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	for _, mode := range []string{"", "keep", "drop", "clamp"} {
+		cfg := factory.CreateDefaultConfig()
+		sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+		require.NoError(t, err)
+		require.NoError(t, sub.Unmarshal(cfg))
+		cfg.(*Config).FutureTimestamps = mode
+		cfg.(*Config).FutureTimestampTolerance = time.Minute
+		require.NoError(t, xconfmap.Validate(cfg), mode)
+	}
+
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	cfg.(*Config).FutureTimestamps = "reject"
+	require.ErrorContains(t, xconfmap.Validate(cfg), `future_timestamps must be one of "keep", "drop" or "clamp", got "reject"`)
+
+	cfg.(*Config).FutureTimestamps = "drop"
+	cfg.(*Config).FutureTimestampTolerance = -time.Second
+	require.ErrorContains(t, xconfmap.Validate(cfg), "future_timestamp_tolerance must not be negative, got -1s")
+}
+
+func TestValidateConfigExemplarLimits(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{name: "unlimited", modify: func(*Config) {}},
+		{name: "limited", modify: func(cfg *Config) {
+			cfg.MaxExemplarsPerDataPoint = 1
+			cfg.MaxExemplarLabels = 10
+		}},
+		{
+			name:    "negative exemplars",
+			modify:  func(cfg *Config) { cfg.MaxExemplarsPerDataPoint = -1 },
+			wantErr: "max_exemplars_per_data_point must not be negative, got -1",
+		},
+		{
+			name:    "negative labels",
+			modify:  func(cfg *Config) { cfg.MaxExemplarLabels = -1 },
+			wantErr: "max_exemplar_labels must not be negative, got -1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := factory.CreateDefaultConfig()
+			sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+			tt.modify(cfg.(*Config))
+			if tt.wantErr == "" {
+				require.NoError(t, xconfmap.Validate(cfg))
+			} else {
+				require.ErrorContains(t, xconfmap.Validate(cfg), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfigMaxLabelValueLength(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config_scrape_config_files.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+
+	for _, maxLength := range []int{0, 1, 1024} {
+		cfg := factory.CreateDefaultConfig()
+		sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+		require.NoError(t, err)
+		require.NoError(t, sub.Unmarshal(cfg))
+		cfg.(*Config).MaxLabelValueLength = maxLength
+		require.NoError(t, xconfmap.Validate(cfg), maxLength)
+	}
+
+	cfg := factory.CreateDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	cfg.(*Config).MaxLabelValueLength = -1
+	require.ErrorContains(t, xconfmap.Validate(cfg), "max_label_value_length must not be negative, got -1")
+}
+
 func TestLoadConfigFailsOnUnknownSection(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "invalid-config-section.yaml"))
 	require.NoError(t, err)
@@ -13,20 +13,26 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadata"
 )
 
 // appendable translates Prometheus scraping diffs into OpenTelemetry format.
 type appendable struct {
 	sink                   consumer.Metrics
 	metricAdjuster         MetricsAdjuster
+	deltaAdjuster          MetricsAdjuster
+	typeTracker            *metricTypeTracker
 	useStartTimeMetric     bool
 	enableNativeHistograms bool
 	trimSuffixes           bool
 	startTimeMetricRegex   *regexp.Regexp
 	externalLabels         labels.Labels
+	opts                   TransactionOptions
 
-	settings receiver.Settings
-	obsrecv  *receiverhelper.ObsReport
+	settings         receiver.Settings
+	obsrecv          *receiverhelper.ObsReport
+	telemetryBuilder *metadata.TelemetryBuilder
 }
 
 // NewAppendable returns a storage.Appendable instance that emits metrics to the sink.
@@ -40,12 +46,28 @@ func NewAppendable(
 	enableNativeHistograms bool,
 	externalLabels labels.Labels,
 	trimSuffixes bool,
+	opts TransactionOptions,
 ) (storage.Appendable, error) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+
 	var metricAdjuster MetricsAdjuster
 	if !useStartTimeMetric {
-		metricAdjuster = NewInitialPointAdjuster(set.Logger, gcInterval, useCreatedMetric)
+		metricAdjuster = NewInitialPointAdjuster(set.Logger, gcInterval, useCreatedMetric, telemetryBuilder)
 	} else {
-		metricAdjuster = NewStartTimeMetricAdjuster(set.Logger, startTimeMetricRegex, gcInterval)
+		metricAdjuster = NewStartTimeMetricAdjuster(set.Logger, startTimeMetricRegex, gcInterval, telemetryBuilder)
+	}
+
+	var deltaAdjuster MetricsAdjuster
+	if opts.CumulativeToDelta {
+		deltaAdjuster = NewCumulativeToDeltaAdjuster(gcInterval)
+	}
+
+	var typeTracker *metricTypeTracker
+	if opts.DetectMetricTypeChanges {
+		typeTracker = newMetricTypeTracker(gcInterval)
 	}
 
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverID: set.ID, Transport: transport, ReceiverCreateSettings: set})
@@ -57,15 +79,24 @@ func NewAppendable(
 		sink:                   sink,
 		settings:               set,
 		metricAdjuster:         metricAdjuster,
+		deltaAdjuster:          deltaAdjuster,
+		typeTracker:            typeTracker,
 		useStartTimeMetric:     useStartTimeMetric,
 		enableNativeHistograms: enableNativeHistograms,
 		startTimeMetricRegex:   startTimeMetricRegex,
 		externalLabels:         externalLabels,
 		obsrecv:                obsrecv,
+		telemetryBuilder:       telemetryBuilder,
 		trimSuffixes:           trimSuffixes,
+		opts:                   opts,
 	}, nil
 }
 
 func (o *appendable) Appender(ctx context.Context) storage.Appender {
-	return newTransaction(ctx, o.metricAdjuster, o.sink, o.externalLabels, o.settings, o.obsrecv, o.trimSuffixes, o.enableNativeHistograms)
+	tr := newTransaction(ctx, o.metricAdjuster, o.sink, o.externalLabels, o.settings, o.obsrecv, o.trimSuffixes, o.enableNativeHistograms)
+	tr.opts = o.opts
+	tr.deltaAdjuster = o.deltaAdjuster
+	tr.typeTracker = o.typeTracker
+	tr.telemetryBuilder = o.telemetryBuilder
+	return tr
 }
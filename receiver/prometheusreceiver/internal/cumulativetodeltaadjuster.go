@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+)
+
+// cumulativeToDeltaAdjuster converts monotonic cumulative sums into delta sums by
+// differencing the values of consecutive scrapes of the same timeseries.
+type cumulativeToDeltaAdjuster struct {
+	jobsMap *JobsMap
+}
+
+// NewCumulativeToDeltaAdjuster returns a new MetricsAdjuster that converts monotonic
+// cumulative sums into delta sums. The first point of each timeseries is dropped, as
+// there is no previous value to compute the delta from.
+func NewCumulativeToDeltaAdjuster(gcInterval time.Duration) MetricsAdjuster {
+	return &cumulativeToDeltaAdjuster{
+		jobsMap: NewJobsMap(gcInterval),
+	}
+}
+
+// AdjustMetrics converts all monotonic cumulative sums in metrics to delta sums.
+func (a *cumulativeToDeltaAdjuster) AdjustMetrics(metrics pmetric.Metrics) error {
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		rm := metrics.ResourceMetrics().At(i)
+		job, _ := rm.Resource().Attributes().Get(string(semconv.ServiceNameKey))
+		instance, _ := rm.Resource().Attributes().Get(string(semconv.ServiceInstanceIDKey))
+		tsm := a.jobsMap.get(job.Str(), instance.Str())
+
+		tsm.Lock()
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			ilm := rm.ScopeMetrics().At(j)
+			ilm.Metrics().RemoveIf(func(metric pmetric.Metric) bool {
+				if metric.Type() != pmetric.MetricTypeSum {
+					return false
+				}
+				sum := metric.Sum()
+				if !sum.IsMonotonic() || sum.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+					return false
+				}
+				a.adjustMetricSum(tsm, metric)
+				return sum.DataPoints().Len() == 0
+			})
+		}
+		tsm.Unlock()
+	}
+	return nil
+}
+
+func (*cumulativeToDeltaAdjuster) adjustMetricSum(tsm *timeseriesMap, current pmetric.Metric) {
+	sum := current.Sum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	sum.DataPoints().RemoveIf(func(point pmetric.NumberDataPoint) bool {
+		if point.Flags().NoRecordedValue() {
+			// Stale points are kept as they are to mark the end of the timeseries.
+			return false
+		}
+
+		// The start time of a delta point is the time of the previous point, which
+		// is tracked in the start time of the timeseries info.
+		tsi, found := tsm.get(current, point.Attributes())
+		previousTime, previousValue := tsi.number.startTime, tsi.number.previousValue
+		tsi.number.startTime = point.Timestamp()
+		tsi.number.previousValue = point.DoubleValue()
+		if !found {
+			// There is no previous value to compute the delta from.
+			return true
+		}
+
+		delta := point.DoubleValue() - previousValue
+		if delta < 0 {
+			// The counter was reset, the new value is the delta since the reset.
+			delta = point.DoubleValue()
+		}
+		point.SetStartTimestamp(previousTime)
+		point.SetDoubleValue(delta)
+		return false
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+)
+
+func newDeltaTestMetrics(name string, monotonic bool, ts pcommon.Timestamp, v float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(string(semconv.ServiceNameKey), "job")
+	rm.Resource().Attributes().PutStr(string(semconv.ServiceInstanceIDKey), "instance")
+	metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName(name)
+	sum := metric.SetEmptySum()
+	sum.SetIsMonotonic(monotonic)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	point := sum.DataPoints().AppendEmpty()
+	point.SetStartTimestamp(pcommon.Timestamp(1 * time.Second))
+	point.SetTimestamp(ts)
+	point.SetDoubleValue(v)
+	point.Attributes().PutStr("a", "A")
+	return md
+}
+
+func TestCumulativeToDeltaAdjuster(t *testing.T) {
+	adjuster := NewCumulativeToDeltaAdjuster(time.Minute)
+	t1 := pcommon.Timestamp(10 * time.Second)
+	t2 := pcommon.Timestamp(20 * time.Second)
+	t3 := pcommon.Timestamp(30 * time.Second)
+
+	// The first scrape of a series has no previous value and is dropped.
+	md := newDeltaTestMetrics("counter", true, t1, 10)
+	require.NoError(t, adjuster.AdjustMetrics(md))
+	assert.Equal(t, 0, md.DataPointCount())
+	assert.Equal(t, 0, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().Len())
+
+	md = newDeltaTestMetrics("counter", true, t2, 25)
+	require.NoError(t, adjuster.AdjustMetrics(md))
+	sum := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, sum.AggregationTemporality())
+	require.Equal(t, 1, sum.DataPoints().Len())
+	assert.Equal(t, 15.0, sum.DataPoints().At(0).DoubleValue())
+	assert.Equal(t, t1, sum.DataPoints().At(0).StartTimestamp())
+	assert.Equal(t, t2, sum.DataPoints().At(0).Timestamp())
+
+	// A decreasing value is a counter reset, the new value is the delta.
+	md = newDeltaTestMetrics("counter", true, t3, 4)
+	require.NoError(t, adjuster.AdjustMetrics(md))
+	sum = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum()
+	require.Equal(t, 1, sum.DataPoints().Len())
+	assert.Equal(t, 4.0, sum.DataPoints().At(0).DoubleValue())
+	assert.Equal(t, t2, sum.DataPoints().At(0).StartTimestamp())
+	assert.Equal(t, t3, sum.DataPoints().At(0).Timestamp())
+}
+
+func TestCumulativeToDeltaAdjusterIgnoresNonMonotonicSums(t *testing.T) {
+	adjuster := NewCumulativeToDeltaAdjuster(time.Minute)
+
+	md := newDeltaTestMetrics("up_down", false, pcommon.Timestamp(10*time.Second), 10)
+	require.NoError(t, adjuster.AdjustMetrics(md))
+	sum := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, sum.AggregationTemporality())
+	require.Equal(t, 1, sum.DataPoints().Len())
+	assert.Equal(t, 10.0, sum.DataPoints().At(0).DoubleValue())
+}
+
+func TestCumulativeToDeltaAdjusterKeepsStalePoints(t *testing.T) {
+	adjuster := NewCumulativeToDeltaAdjuster(time.Minute)
+
+	md := newDeltaTestMetrics("counter", true, pcommon.Timestamp(10*time.Second), math.Float64frombits(value.StaleNaN))
+	point := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+	point.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+	require.NoError(t, adjuster.AdjustMetrics(md))
+	assert.Equal(t, 1, md.DataPointCount())
+}
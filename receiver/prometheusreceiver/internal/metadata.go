@@ -41,6 +41,24 @@ var internalMetricMetadata = map[string]*scrape.MetricMetadata{
 		Type:         model.MetricTypeGauge,
 		Help:         "The number of samples remaining after metric relabeling was applied",
 	},
+	// The following metrics are only reported if report_extra_scrape_metrics is enabled.
+	"scrape_timeout_seconds": {
+		MetricFamily: "scrape_timeout_seconds",
+		Unit:         "seconds",
+		Type:         model.MetricTypeGauge,
+		Help:         "The configured scrape timeout for a target",
+	},
+	"scrape_sample_limit": {
+		MetricFamily: "scrape_sample_limit",
+		Type:         model.MetricTypeGauge,
+		Help:         "The configured sample limit for a target, zero if there is no limit configured",
+	},
+	"scrape_body_size_bytes": {
+		MetricFamily: "scrape_body_size_bytes",
+		Unit:         "bytes",
+		Type:         model.MetricTypeGauge,
+		Help:         "The uncompressed size of the last scrape response",
+	},
 }
 
 func metadataForMetric(metricName string, mc scrape.MetricMetadataStore) (*scrape.MetricMetadata, string) {
@@ -65,3 +83,18 @@ func metadataForMetric(metricName string, mc scrape.MetricMetadataStore) (*scrap
 		Type:         model.MetricTypeUnknown,
 	}, metricName
 }
+
+// exactMetadataForMetric returns the metadata of the metric without falling back to the
+// metadata of the metric name with suffixes trimmed.
+func exactMetadataForMetric(metricName string, mc scrape.MetricMetadataStore) *scrape.MetricMetadata {
+	if metadata, ok := internalMetricMetadata[metricName]; ok {
+		return metadata
+	}
+	if metadata, ok := mc.GetMetadata(metricName); ok {
+		return &metadata
+	}
+	return &scrape.MetricMetadata{
+		MetricFamily: metricName,
+		Type:         model.MetricTypeUnknown,
+	}
+}
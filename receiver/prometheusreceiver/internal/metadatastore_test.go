@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/prometheusreceivertest"
+)
+
+func TestMetadataStoreUnitAndDescription(t *testing.T) {
+	store := prometheusreceivertest.NewMetadataStore()
+	store.SetType("request_duration_seconds", model.MetricTypeGauge)
+	store.SetUnit("request_duration_seconds", "seconds")
+	store.SetHelp("request_duration_seconds", "The duration of the last request")
+
+	mf := newMetricFamily("request_duration_seconds", store, zap.NewNop())
+	lb := labels.FromStrings("a", "A")
+	sRef, _ := getSeriesRef(nil, lb, mf.mtype)
+	require.NoError(t, mf.addSeries(sRef, "request_duration_seconds", lb, 13, 1))
+
+	sl := pmetric.NewMetricSlice()
+	mf.appendMetric(sl, false, false)
+
+	require.Equal(t, 1, sl.Len())
+	assert.Equal(t, pmetric.MetricTypeGauge, sl.At(0).Type())
+	assert.Equal(t, "s", sl.At(0).Unit())
+	assert.Equal(t, "The duration of the last request", sl.At(0).Description())
+}
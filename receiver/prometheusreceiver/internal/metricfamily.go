@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
@@ -22,6 +23,28 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 )
 
+// trimMetricSuffixes trims the type suffix (_total of counters) and/or the unit suffix from the
+// metric name. The unit suffix is the one preceding the type suffix, e.g. _seconds in
+// request_duration_seconds_total, and is trimmed even if the type suffix is kept.
+func trimMetricSuffixes(name string, mtype pmetric.MetricType, unit string, trimTypeSuffixes, trimUnitSuffixes bool) string {
+	if !trimTypeSuffixes && !trimUnitSuffixes {
+		return name
+	}
+	typeSuffix := ""
+	if mtype == pmetric.MetricTypeSum && strings.HasSuffix(name, "_total") && name != "_total" {
+		typeSuffix = "_total"
+		name = strings.TrimSuffix(name, typeSuffix)
+	}
+	if trimUnitSuffixes {
+		// a gauge has no type suffix, so only the unit suffix is trimmed
+		name = prometheus.TrimPromSuffixes(name, pmetric.MetricTypeGauge, unit)
+	}
+	if !trimTypeSuffixes {
+		name += typeSuffix
+	}
+	return name
+}
+
 type metricFamily struct {
 	mtype pmetric.MetricType
 	// isMonotonic only applies to sums
@@ -30,6 +53,10 @@ type metricFamily struct {
 	name        string
 	metadata    *scrape.MetricMetadata
 	groupOrders []*metricGroup
+	// typeChanged is set if the family had a different type in a previous scrape of the target.
+	typeChanged bool
+	// targetLabelAttributes is set if the target labels are added as data point attributes.
+	targetLabelAttributes bool
 }
 
 // metricGroup, represents a single metric of a metric family. for example a histogram metric is usually represent by
@@ -52,11 +79,25 @@ type metricGroup struct {
 	fhValue        *histogram.FloatHistogram
 	complexValue   []*dataPoint
 	exemplars      pmetric.ExemplarSlice
-	isNHCB         bool // true if this is a Native Histogram Custom Buckets (schema -53)
+	// exemplarBounds holds the upper bound of the bucket each exemplar was scraped
+	// with, in the same order as exemplars. Only populated for classic histograms.
+	exemplarBounds        []float64
+	isNHCB                bool // true if this is a Native Histogram Custom Buckets (schema -53)
+	targetLabelAttributes bool
 }
 
 func newMetricFamily(metricName string, mc scrape.MetricMetadataStore, logger *zap.Logger) *metricFamily {
 	metadata, familyName := metadataForMetric(metricName, mc)
+	return newMetricFamilyFromMetadata(metricName, familyName, metadata, logger)
+}
+
+// newUnnormalizedMetricFamily returns a family named exactly like the metric, which only uses
+// metadata reported for the metric name itself.
+func newUnnormalizedMetricFamily(metricName string, mc scrape.MetricMetadataStore, logger *zap.Logger) *metricFamily {
+	return newMetricFamilyFromMetadata(metricName, metricName, exactMetadataForMetric(metricName, mc), logger)
+}
+
+func newMetricFamilyFromMetadata(metricName, familyName string, metadata *scrape.MetricMetadata, logger *zap.Logger) *metricFamily {
 	mtype, isMonotonic := convToMetricType(metadata.Type)
 	if mtype == pmetric.MetricTypeEmpty {
 		logger.Debug(fmt.Sprintf("Unknown-typed metric : %s %+v", metricName, metadata))
@@ -82,15 +123,60 @@ func (mf *metricFamily) includesMetric(metricName string) bool {
 	return metricName == mf.name
 }
 
+// acceptsSample returns true if a sample with the given metric name can be part of the family.
+// Samples which were attached to the family because their normalized name matches the family
+// name must carry a suffix that is valid for the family type, otherwise unrelated metrics would
+// silently be merged into the family.
+func (mf *metricFamily) acceptsSample(metricName string) bool {
+	if metricName == mf.name || metricName == mf.metadata.MetricFamily {
+		return true
+	}
+	var suffixes []string
+	switch mf.mtype {
+	case pmetric.MetricTypeHistogram:
+		suffixes = []string{metricsSuffixBucket, metricsSuffixSum, metricsSuffixCount, metricSuffixCreated}
+	case pmetric.MetricTypeSummary:
+		suffixes = []string{metricsSuffixSum, metricsSuffixCount, metricSuffixCreated}
+	case pmetric.MetricTypeSum:
+		suffixes = []string{metricSuffixTotal, metricSuffixInfo, metricSuffixCreated}
+	case pmetric.MetricTypeExponentialHistogram:
+		suffixes = []string{metricSuffixCreated}
+	default:
+		return true
+	}
+	for _, suffix := range suffixes {
+		if metricName == mf.name+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMonotonicBuckets returns true if the cumulative bucket counts of the sorted
+// classic histogram buckets never decrease and don't exceed the total count.
+func (mg *metricGroup) hasMonotonicBuckets() bool {
+	for i := 1; i < len(mg.complexValue); i++ {
+		if mg.complexValue[i].value < mg.complexValue[i-1].value {
+			return false
+		}
+	}
+	if n := len(mg.complexValue); n > 0 && mg.count < mg.complexValue[n-1].value {
+		return false
+	}
+	return true
+}
+
 func (mg *metricGroup) sortPoints() {
 	sort.Slice(mg.complexValue, func(i, j int) bool {
 		return mg.complexValue[i].boundary < mg.complexValue[j].boundary
 	})
 }
 
-func (mg *metricGroup) toDistributionPoint(dest pmetric.HistogramDataPointSlice) {
+// toDistributionPoint appends the classic or NHCB histogram point of the group to dest.
+// It reports whether the point was dropped because its bucket counts are not monotonic.
+func (mg *metricGroup) toDistributionPoint(dest pmetric.HistogramDataPointSlice) (nonMonotonic bool) {
 	if !mg.hasCount {
-		return
+		return false
 	}
 
 	mg.sortPoints()
@@ -103,20 +189,27 @@ func (mg *metricGroup) toDistributionPoint(dest pmetric.HistogramDataPointSlice)
 		switch {
 		case mg.hValue != nil:
 			if len(mg.hValue.CustomValues) == 0 {
-				return
+				return false
 			}
 			bounds = make([]float64, len(mg.hValue.CustomValues))
 			copy(bounds, mg.hValue.CustomValues)
 			bucketCounts = convertNHCBBDeltBuckets(mg.hValue)
 		case mg.fhValue != nil:
 			if len(mg.fhValue.CustomValues) == 0 {
-				return
+				return false
 			}
 			bounds = make([]float64, len(mg.fhValue.CustomValues))
 			copy(bounds, mg.fhValue.CustomValues)
 			bucketCounts = convertNHCBAbsoluteBuckets(mg.fhValue)
 		}
 	} else {
+		// Cumulative bucket counts which decrease with increasing boundaries
+		// can't be converted to OTLP bucket counts, drop the point instead of
+		// reporting wrapped around counts.
+		if !pointIsStale && !mg.hasMonotonicBuckets() {
+			return true
+		}
+
 		bucketCount := len(mg.complexValue) + 1
 		// if the final bucket is +Inf, we ignore it
 		if bucketCount > 1 && mg.complexValue[bucketCount-2].boundary == math.Inf(1) {
@@ -176,8 +269,34 @@ func (mg *metricGroup) toDistributionPoint(dest pmetric.HistogramDataPointSlice)
 		point.SetStartTimestamp(tsNanos)
 	}
 	point.SetTimestamp(tsNanos)
-	populateAttributes(pmetric.MetricTypeHistogram, mg.ls, point.Attributes())
+	populateAttributes(pmetric.MetricTypeHistogram, mg.ls, mg.targetLabelAttributes, point.Attributes())
+	mg.sortExemplarsByBucket()
 	mg.setExemplars(point.Exemplars())
+	return false
+}
+
+// sortExemplarsByBucket orders the exemplars of a classic histogram by the upper bound
+// of the bucket they were scraped with, such that they line up with the bucket counts.
+func (mg *metricGroup) sortExemplarsByBucket() {
+	if len(mg.exemplarBounds) != mg.exemplars.Len() || sort.Float64sAreSorted(mg.exemplarBounds) {
+		return
+	}
+
+	order := make([]int, len(mg.exemplarBounds))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return mg.exemplarBounds[order[i]] < mg.exemplarBounds[order[j]]
+	})
+
+	sorted := pmetric.NewExemplarSlice()
+	sorted.EnsureCapacity(len(order))
+	for _, i := range order {
+		mg.exemplars.At(i).MoveTo(sorted.AppendEmpty())
+	}
+	mg.exemplars = sorted
+	sort.Float64s(mg.exemplarBounds)
 }
 
 // toExponentialHistogramDataPoints is based on
@@ -254,7 +373,7 @@ func (mg *metricGroup) toExponentialHistogramDataPoints(dest pmetric.Exponential
 		point.SetStartTimestamp(tsNanos)
 	}
 	point.SetTimestamp(tsNanos)
-	populateAttributes(pmetric.MetricTypeHistogram, mg.ls, point.Attributes())
+	populateAttributes(pmetric.MetricTypeHistogram, mg.ls, mg.targetLabelAttributes, point.Attributes())
 	mg.setExemplars(point.Exemplars())
 }
 
@@ -394,7 +513,7 @@ func (mg *metricGroup) toSummaryPoint(dest pmetric.SummaryDataPointSlice) {
 		// metrics_adjuster adjusts the startTimestamp to the initial scrape timestamp
 		point.SetStartTimestamp(tsNanos)
 	}
-	populateAttributes(pmetric.MetricTypeSummary, mg.ls, point.Attributes())
+	populateAttributes(pmetric.MetricTypeSummary, mg.ls, mg.targetLabelAttributes, point.Attributes())
 }
 
 func (mg *metricGroup) toNumberDataPoint(dest pmetric.NumberDataPointSlice) {
@@ -415,12 +534,15 @@ func (mg *metricGroup) toNumberDataPoint(dest pmetric.NumberDataPointSlice) {
 	} else {
 		point.SetDoubleValue(mg.value)
 	}
-	populateAttributes(pmetric.MetricTypeGauge, mg.ls, point.Attributes())
+	populateAttributes(pmetric.MetricTypeGauge, mg.ls, mg.targetLabelAttributes, point.Attributes())
 	mg.setExemplars(point.Exemplars())
 }
 
-func populateAttributes(mType pmetric.MetricType, ls labels.Labels, dest pcommon.Map) {
+func populateAttributes(mType pmetric.MetricType, ls labels.Labels, targetLabelAttributes bool, dest pcommon.Map) {
 	dest.EnsureCapacity(ls.Len())
+	if targetLabelAttributes {
+		addTargetLabelAttributes(ls, dest)
+	}
 	names := getSortedNotUsefulLabels(mType)
 	j := 0
 	ls.Range(func(l labels.Label) {
@@ -438,14 +560,36 @@ func populateAttributes(mType pmetric.MetricType, ls labels.Labels, dest pcommon
 	})
 }
 
+// targetLabelAttributePrefix prefixes the data point attributes holding target labels. It
+// can't collide with the names of other labels, as Prometheus label names must not contain dots.
+const targetLabelAttributePrefix = "target."
+
+// targetLabelAttributeNames maps the well-known target labels to their data point attribute names.
+var targetLabelAttributeNames = []struct{ label, attribute string }{
+	{model.JobLabel, targetLabelAttributePrefix + "job"},
+	{model.InstanceLabel, targetLabelAttributePrefix + "instance"},
+	{model.SchemeLabel, targetLabelAttributePrefix + "scheme"},
+	{model.MetricsPathLabel, targetLabelAttributePrefix + "metrics_path"},
+}
+
+// addTargetLabelAttributes adds the well-known target labels of the sample as data point attributes.
+func addTargetLabelAttributes(ls labels.Labels, dest pcommon.Map) {
+	for _, names := range targetLabelAttributeNames {
+		if value := ls.Get(names.label); value != "" {
+			dest.PutStr(names.attribute, value)
+		}
+	}
+}
+
 func (mf *metricFamily) loadMetricGroupOrCreate(groupKey uint64, ls labels.Labels, ts int64) *metricGroup {
 	mg, ok := mf.groups[groupKey]
 	if !ok {
 		mg = &metricGroup{
-			mtype:     mf.mtype,
-			ts:        ts,
-			ls:        ls,
-			exemplars: pmetric.NewExemplarSlice(),
+			mtype:                 mf.mtype,
+			ts:                    ts,
+			ls:                    ls,
+			exemplars:             pmetric.NewExemplarSlice(),
+			targetLabelAttributes: mf.targetLabelAttributes,
 		}
 		mf.groups[groupKey] = mg
 		// maintaining data insertion order is helpful to generate stable/reproducible metric output
@@ -566,14 +710,11 @@ func (mf *metricFamily) addNHCBSeries(seriesRef uint64, metricName string, ls la
 	return nil
 }
 
-func (mf *metricFamily) appendMetric(metrics pmetric.MetricSlice, trimSuffixes bool) {
+// appendMetric appends the metric of the family to metrics. It returns the number of
+// histogram points that were dropped because their bucket counts are not monotonic.
+func (mf *metricFamily) appendMetric(metrics pmetric.MetricSlice, trimTypeSuffixes, trimUnitSuffixes bool) (nonMonotonicPoints int) {
 	metric := pmetric.NewMetric()
-	// Trims type and unit suffixes from metric name
-	name := mf.name
-	if trimSuffixes {
-		name = prometheus.TrimPromSuffixes(name, mf.mtype, mf.metadata.Unit)
-	}
-	metric.SetName(name)
+	metric.SetName(trimMetricSuffixes(mf.name, mf.mtype, mf.metadata.Unit, trimTypeSuffixes, trimUnitSuffixes))
 	metric.SetDescription(mf.metadata.Help)
 	metric.SetUnit(prometheus.UnitWordToUCUM(mf.metadata.Unit))
 	metric.Metadata().PutStr(prometheus.MetricMetadataTypeKey, string(mf.metadata.Type))
@@ -586,7 +727,9 @@ func (mf *metricFamily) appendMetric(metrics pmetric.MetricSlice, trimSuffixes b
 		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
 		hdpL := histogram.DataPoints()
 		for _, mg := range mf.groupOrders {
-			mg.toDistributionPoint(hdpL)
+			if mg.toDistributionPoint(hdpL) {
+				nonMonotonicPoints++
+			}
 		}
 		pointCount = hdpL.Len()
 
@@ -627,19 +770,61 @@ func (mf *metricFamily) appendMetric(metrics pmetric.MetricSlice, trimSuffixes b
 	}
 
 	if pointCount == 0 {
-		return
+		return nonMonotonicPoints
 	}
 
 	metric.MoveTo(metrics.AppendEmpty())
+	return nonMonotonicPoints
 }
 
-func (mf *metricFamily) addExemplar(seriesRef uint64, e exemplar.Exemplar) {
+// addExemplar adds the exemplar to the data point of the series. It reports whether the exemplar
+// was dropped because the data point already holds maxExemplars exemplars. Zero means unlimited.
+func (mf *metricFamily) addExemplar(seriesRef uint64, ls labels.Labels, e exemplar.Exemplar, maxExemplars int) bool {
 	mg := mf.groups[seriesRef]
 	if mg == nil {
-		return
+		return false
 	}
 	es := mg.exemplars
+	if maxExemplars > 0 && es.Len() >= maxExemplars {
+		return true
+	}
 	convertExemplar(e, es.AppendEmpty())
+
+	// Remember the bucket the exemplar was scraped with, so that the exemplars
+	// can be ordered by bucket when the histogram data point is built.
+	if mf.mtype == pmetric.MetricTypeHistogram && !mg.isNHCB {
+		boundary, err := getBoundary(mf.mtype, ls)
+		if err != nil {
+			// Exemplars of the _count or _sum series are not associated with a bucket.
+			boundary = math.Inf(1)
+		}
+		mg.exemplarBounds = append(mg.exemplarBounds, boundary)
+	}
+	return false
+}
+
+// limitExemplarLabels keeps the first maxLabels labels of an exemplar, in name order, and returns
+// the number of dropped labels. The trace and span id labels are not counted and always kept, as
+// they are not converted to filtered attributes.
+func limitExemplarLabels(ls labels.Labels, maxLabels int) (labels.Labels, int) {
+	kept, dropped := 0, 0
+	b := labels.NewScratchBuilder(ls.Len())
+	ls.Range(func(lb labels.Label) {
+		switch strings.ToLower(lb.Name) {
+		case prometheus.ExemplarTraceIDKey, prometheus.ExemplarSpanIDKey:
+		default:
+			if kept >= maxLabels {
+				dropped++
+				return
+			}
+			kept++
+		}
+		b.Add(lb.Name, lb.Value)
+	})
+	if dropped == 0 {
+		return ls, 0
+	}
+	return b.Labels(), dropped
 }
 
 func convertExemplar(pe exemplar.Exemplar, e pmetric.Exemplar) {
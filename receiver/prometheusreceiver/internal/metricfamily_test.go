@@ -145,6 +145,31 @@ func TestMetricGroupData_toDistributionUnitTest(t *testing.T) {
 				return point
 			},
 		},
+		{
+			name:                "histogram excludes +Inf from explicit bounds",
+			metricName:          "histogram",
+			intervalStartTimeMs: 11,
+			labels:              labels.FromMap(map[string]string{"a": "A"}),
+			scrapes: []*scrape{
+				{at: 11, value: 10, metric: "histogram_count"},
+				{at: 11, value: 7.5, metric: "histogram_sum"},
+				{at: 11, value: 2, metric: "histogram_bucket", extraLabel: labels.Label{Name: "le", Value: "0.1"}},
+				{at: 11, value: 6, metric: "histogram_bucket", extraLabel: labels.Label{Name: "le", Value: "1"}},
+				{at: 11, value: 10, metric: "histogram_bucket", extraLabel: labels.Label{Name: "le", Value: "+Inf"}},
+			},
+			want: func() pmetric.HistogramDataPoint {
+				point := pmetric.NewHistogramDataPoint()
+				point.SetCount(10)
+				point.SetSum(7.5)
+				point.SetTimestamp(pcommon.Timestamp(11 * time.Millisecond))      // the time in milliseconds -> nanoseconds.
+				point.SetStartTimestamp(pcommon.Timestamp(11 * time.Millisecond)) // the time in milliseconds -> nanoseconds.
+				point.ExplicitBounds().FromRaw([]float64{0.1, 1})
+				point.BucketCounts().FromRaw([]uint64{2, 4, 4})
+				attributes := point.Attributes()
+				attributes.PutStr("a", "A")
+				return point
+			},
+		},
 		{
 			name:                "histogram with startTimestamp from _created",
 			metricName:          "histogram_with_created",
@@ -278,7 +303,7 @@ func TestMetricGroupData_toDistributionUnitTest(t *testing.T) {
 			require.Len(t, mp.groups, 1)
 
 			sl := pmetric.NewMetricSlice()
-			mp.appendMetric(sl, false)
+			mp.appendMetric(sl, false, false)
 
 			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
 			metric := sl.At(0)
@@ -420,7 +445,7 @@ func TestMetricGroupData_toNHCBDistributionUnitTest(t *testing.T) {
 			require.Len(t, mp.groups, 1)
 
 			sl := pmetric.NewMetricSlice()
-			mp.appendMetric(sl, false)
+			mp.appendMetric(sl, false, false)
 
 			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
 			metric := sl.At(0)
@@ -610,7 +635,7 @@ func TestMetricGroupData_toExponentialDistributionUnitTest(t *testing.T) {
 			require.Len(t, mp.groups, 1)
 
 			sl := pmetric.NewMetricSlice()
-			mp.appendMetric(sl, false)
+			mp.appendMetric(sl, false, false)
 
 			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
 			metric := sl.At(0)
@@ -897,7 +922,7 @@ func TestMetricGroupData_toSummaryUnitTest(t *testing.T) {
 			require.Len(t, mp.groups, 1)
 
 			sl := pmetric.NewMetricSlice()
-			mp.appendMetric(sl, false)
+			mp.appendMetric(sl, false, false)
 
 			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
 			metric := sl.At(0)
@@ -992,6 +1017,43 @@ func TestMetricGroupData_toNumberDataUnitTest(t *testing.T) {
 				return point
 			},
 		},
+		{
+			name:                     "counter:: staleNaN sets no recorded value flag",
+			metricKind:               "counter",
+			intervalStartTimestampMs: 11,
+			labels:                   labels.FromMap(map[string]string{"a": "A", "b": "B"}),
+			scrapes: []*scrape{
+				{at: 13, value: math.Float64frombits(value.StaleNaN), metric: "value"},
+			},
+			want: func() pmetric.NumberDataPoint {
+				point := pmetric.NewNumberDataPoint()
+				point.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+				point.SetTimestamp(pcommon.Timestamp(13 * time.Millisecond))      // the time in milliseconds -> nanoseconds.
+				point.SetStartTimestamp(pcommon.Timestamp(13 * time.Millisecond)) // the time in milliseconds -> nanoseconds.
+				attributes := point.Attributes()
+				attributes.PutStr("a", "A")
+				attributes.PutStr("b", "B")
+				return point
+			},
+		},
+		{
+			name:                     "gauge:: staleNaN sets no recorded value flag",
+			metricKind:               "gauge",
+			intervalStartTimestampMs: 11,
+			labels:                   labels.FromMap(map[string]string{"a": "A", "b": "B"}),
+			scrapes: []*scrape{
+				{at: 13, value: math.Float64frombits(value.StaleNaN), metric: "gauge"},
+			},
+			want: func() pmetric.NumberDataPoint {
+				point := pmetric.NewNumberDataPoint()
+				point.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+				point.SetTimestamp(pcommon.Timestamp(13 * time.Millisecond)) // the time in milliseconds -> nanoseconds.
+				attributes := point.Attributes()
+				attributes.PutStr("a", "A")
+				attributes.PutStr("b", "B")
+				return point
+			},
+		},
 		{
 			name:                     "counter:: startTimestampMs of 0",
 			metricKind:               "counter",
@@ -1025,14 +1087,19 @@ func TestMetricGroupData_toNumberDataUnitTest(t *testing.T) {
 			require.Len(t, mp.groups, 1)
 
 			sl := pmetric.NewMetricSlice()
-			mp.appendMetric(sl, false)
+			mp.appendMetric(sl, false, false)
 
 			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
 			metric := sl.At(0)
 			require.Equal(t, mc[tt.metricKind].Help, metric.Description(), "Expected help metadata in metric description")
 			require.Equal(t, mc[tt.metricKind].Unit, metric.Unit(), "Expected unit metadata in metric")
 
-			ndpL := metric.Sum().DataPoints()
+			var ndpL pmetric.NumberDataPointSlice
+			if metric.Type() == pmetric.MetricTypeGauge {
+				ndpL = metric.Gauge().DataPoints()
+			} else {
+				ndpL = metric.Sum().DataPoints()
+			}
 			require.Equal(t, 1, ndpL.Len(), "Exactly one point expected")
 			got := ndpL.At(0)
 			want := tt.want()
@@ -1040,3 +1107,207 @@ func TestMetricGroupData_toNumberDataUnitTest(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricFamily_appendMetricUnit(t *testing.T) {
+	store := testMetadataStore{
+		"http_request_duration_seconds_total": scrape.MetricMetadata{
+			MetricFamily: "http_request_duration_seconds_total",
+			Type:         model.MetricTypeCounter,
+			Unit:         "seconds",
+		},
+		"memory_usage_bytes": scrape.MetricMetadata{
+			MetricFamily: "memory_usage_bytes",
+			Type:         model.MetricTypeGauge,
+			Unit:         "bytes",
+		},
+		"queue_length": scrape.MetricMetadata{
+			MetricFamily: "queue_length",
+			Type:         model.MetricTypeGauge,
+		},
+	}
+
+	tests := []struct {
+		metricName   string
+		trimSuffixes bool
+		wantName     string
+		wantUnit     string
+	}{
+		{
+			metricName: "http_request_duration_seconds_total",
+			wantName:   "http_request_duration_seconds_total",
+			wantUnit:   "s",
+		},
+		{
+			metricName:   "http_request_duration_seconds_total",
+			trimSuffixes: true,
+			wantName:     "http_request_duration",
+			wantUnit:     "s",
+		},
+		{
+			metricName:   "memory_usage_bytes",
+			trimSuffixes: true,
+			wantName:     "memory_usage",
+			wantUnit:     "By",
+		},
+		{
+			metricName:   "queue_length",
+			trimSuffixes: true,
+			wantName:     "queue_length",
+			wantUnit:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			mf := newMetricFamily(tt.metricName, store, zap.NewNop())
+			lb := labels.FromStrings("a", "A")
+			sRef, _ := getSeriesRef(nil, lb, mf.mtype)
+			require.NoError(t, mf.addSeries(sRef, tt.metricName, lb, 13, 1))
+
+			sl := pmetric.NewMetricSlice()
+			mf.appendMetric(sl, tt.trimSuffixes, tt.trimSuffixes)
+
+			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
+			require.Equal(t, tt.wantName, sl.At(0).Name())
+			require.Equal(t, tt.wantUnit, sl.At(0).Unit())
+		})
+	}
+}
+
+func TestMetricFamily_appendMetricSuffixTrimming(t *testing.T) {
+	store := testMetadataStore{
+		"request_duration_seconds_total": scrape.MetricMetadata{
+			MetricFamily: "request_duration_seconds_total",
+			Type:         model.MetricTypeCounter,
+			Unit:         "seconds",
+		},
+	}
+
+	tests := []struct {
+		name             string
+		trimTypeSuffixes bool
+		trimUnitSuffixes bool
+		wantName         string
+	}{
+		{
+			name:     "no trimming",
+			wantName: "request_duration_seconds_total",
+		},
+		{
+			name:             "type suffix",
+			trimTypeSuffixes: true,
+			wantName:         "request_duration_seconds",
+		},
+		{
+			name:             "unit suffix",
+			trimUnitSuffixes: true,
+			wantName:         "request_duration_total",
+		},
+		{
+			name:             "type and unit suffixes",
+			trimTypeSuffixes: true,
+			trimUnitSuffixes: true,
+			wantName:         "request_duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mf := newMetricFamily("request_duration_seconds_total", store, zap.NewNop())
+			lb := labels.FromStrings("a", "A")
+			sRef, _ := getSeriesRef(nil, lb, mf.mtype)
+			require.NoError(t, mf.addSeries(sRef, "request_duration_seconds_total", lb, 13, 1))
+
+			sl := pmetric.NewMetricSlice()
+			mf.appendMetric(sl, tt.trimTypeSuffixes, tt.trimUnitSuffixes)
+
+			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
+			require.Equal(t, tt.wantName, sl.At(0).Name())
+			require.Equal(t, "s", sl.At(0).Unit())
+		})
+	}
+}
+
+func TestMetricFamily_appendMetricDescription(t *testing.T) {
+	store := testMetadataStore{
+		"with_help": scrape.MetricMetadata{
+			MetricFamily: "with_help",
+			Type:         model.MetricTypeGauge,
+			Help:         "A gauge with help text",
+		},
+		"without_help": scrape.MetricMetadata{
+			MetricFamily: "without_help",
+			Type:         model.MetricTypeGauge,
+		},
+	}
+
+	tests := []struct {
+		metricName      string
+		wantDescription string
+	}{
+		{
+			metricName:      "with_help",
+			wantDescription: "A gauge with help text",
+		},
+		{
+			metricName:      "without_help",
+			wantDescription: "",
+		},
+		{
+			metricName:      "without_metadata",
+			wantDescription: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metricName, func(t *testing.T) {
+			mf := newMetricFamily(tt.metricName, store, zap.NewNop())
+			lb := labels.FromStrings("a", "A")
+			sRef, _ := getSeriesRef(nil, lb, mf.mtype)
+			require.NoError(t, mf.addSeries(sRef, tt.metricName, lb, 13, 1))
+
+			sl := pmetric.NewMetricSlice()
+			mf.appendMetric(sl, false, false)
+
+			require.Equal(t, 1, sl.Len(), "Exactly one metric expected")
+			require.Equal(t, tt.wantDescription, sl.At(0).Description())
+		})
+	}
+}
+
+func TestMetricGroupData_toDistributionNonMonotonicBuckets(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   float64
+		buckets map[string]float64
+	}{
+		{
+			name:    "decreasing bucket counts",
+			count:   10,
+			buckets: map[string]float64{"0.1": 5, "1": 3, "+Inf": 10},
+		},
+		{
+			name:    "count lower than last bucket",
+			count:   4,
+			buckets: map[string]float64{"0.1": 2, "1": 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp := newMetricFamily("histogram", mc, zap.NewNop())
+			ls := labels.FromStrings("a", "A")
+			sRef, _ := getSeriesRef(nil, ls, mp.mtype)
+			require.NoError(t, mp.addSeries(sRef, "histogram_count", ls, 11, tt.count))
+			require.NoError(t, mp.addSeries(sRef, "histogram_sum", ls, 11, 7.5))
+			for le, v := range tt.buckets {
+				bls := labels.NewBuilder(ls).Set("le", le).Labels()
+				require.NoError(t, mp.addSeries(sRef, "histogram_bucket", bls, 11, v))
+			}
+
+			sl := pmetric.NewMetricSlice()
+			require.Equal(t, 1, mp.appendMetric(sl, false, false))
+			require.Equal(t, 0, sl.Len(), "Expected the point with non-monotonic buckets to be dropped")
+		})
+	}
+}
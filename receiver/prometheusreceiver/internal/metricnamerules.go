@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"regexp"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+)
+
+// MetricNameRule renames or drops the samples whose metric name matches Regex.
+type MetricNameRule struct {
+	// Regex is matched against the full metric name of each sample, including suffixes
+	// like _bucket or _total. It should be anchored.
+	Regex *regexp.Regexp
+	// TargetName replaces the metric name of matching samples. It can reference the
+	// capture groups of Regex, e.g. ${1}. It is ignored if Drop is set.
+	TargetName string
+	// Drop drops the matching samples.
+	Drop bool
+}
+
+// applyMetricNameRules applies the first rule of TransactionOptions.MetricNameRules matching the
+// metric name. It returns the resulting metric name and labels, and false if the sample is dropped.
+func (t *transaction) applyMetricNameRules(metricName string, ls labels.Labels) (string, labels.Labels, bool) {
+	for _, rule := range t.opts.MetricNameRules {
+		if !rule.Regex.MatchString(metricName) {
+			continue
+		}
+		if rule.Drop {
+			return metricName, ls, false
+		}
+		target := rule.Regex.ReplaceAllString(metricName, rule.TargetName)
+		if target == "" || target == metricName {
+			return metricName, ls, true
+		}
+		// Remember the original names, so that the metadata of the renamed metric, and
+		// thereby its type, can still be found.
+		t.renamedMetrics[target] = metricName
+		t.renamedMetrics[normalizeMetricName(target)] = normalizeMetricName(metricName)
+		return target, labels.NewBuilder(ls).Set(model.MetricNameLabel, target).Labels(), true
+	}
+	return metricName, ls, true
+}
+
+// renamedMetadataStore looks up the metadata of renamed metrics by their original name.
+type renamedMetadataStore struct {
+	scrape.MetricMetadataStore
+	renamed map[string]string
+}
+
+func (s renamedMetadataStore) GetMetadata(mfName string) (scrape.MetricMetadata, bool) {
+	original, ok := s.renamed[mfName]
+	if !ok {
+		return s.MetricMetadataStore.GetMetadata(mfName)
+	}
+	md, ok := s.MetricMetadataStore.GetMetadata(original)
+	if ok {
+		md.MetricFamily = mfName
+	}
+	return md, ok
+}
@@ -4,16 +4,20 @@
 package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadata"
 )
 
 // Notes on garbage collection (gc):
@@ -239,6 +243,7 @@ type MetricsAdjuster interface {
 type initialPointAdjuster struct {
 	jobsMap          *JobsMap
 	logger           *zap.Logger
+	telemetryBuilder *metadata.TelemetryBuilder
 	useCreatedMetric bool
 	// usePointTimeForReset forces the adjuster to use the timestamp of the
 	// point instead of the start timestamp when it detects resets.  This is
@@ -248,10 +253,11 @@ type initialPointAdjuster struct {
 }
 
 // NewInitialPointAdjuster returns a new MetricsAdjuster that adjust metrics' start times based on the initial received points.
-func NewInitialPointAdjuster(logger *zap.Logger, gcInterval time.Duration, useCreatedMetric bool) MetricsAdjuster {
+func NewInitialPointAdjuster(logger *zap.Logger, gcInterval time.Duration, useCreatedMetric bool, telemetryBuilder *metadata.TelemetryBuilder) MetricsAdjuster {
 	return &initialPointAdjuster{
 		jobsMap:          NewJobsMap(gcInterval),
 		logger:           logger,
+		telemetryBuilder: telemetryBuilder,
 		useCreatedMetric: useCreatedMetric,
 	}
 }
@@ -446,6 +452,10 @@ func (a *initialPointAdjuster) adjustMetricSum(tsm *timeseriesMap, current pmetr
 		}
 
 		if currentSum.DoubleValue() < tsi.number.previousValue {
+			if current.Sum().IsMonotonic() {
+				a.telemetryBuilder.PrometheusreceiverCounterResets.Add(context.Background(), 1,
+					metric.WithAttributes(attribute.String("metric", current.Name())))
+			}
 			// reset re-initialize everything.
 			tsi.number.startTime = currentSum.StartTimestamp()
 			if a.usePointTimeForReset {
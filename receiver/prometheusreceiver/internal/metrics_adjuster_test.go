@@ -4,14 +4,23 @@
 package internal
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadatatest"
 )
 
 var (
@@ -70,7 +79,7 @@ func TestGauge(t *testing.T) {
 			adjusted:    metrics(gaugeMetric(gauge1, doublePoint(k1v1k2v2, t3, t3, 55))),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestSum(t *testing.T) {
@@ -101,7 +110,7 @@ func TestSum(t *testing.T) {
 			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t5, 72))),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestSumWithDifferentResources(t *testing.T) {
@@ -132,7 +141,7 @@ func TestSumWithDifferentResources(t *testing.T) {
 			adjusted:    metricsFromResourceMetrics(resourceMetrics("job1", "instance1", sumMetric(sum1, doublePoint(k1v1k2v2, t3, t5, 72))), resourceMetrics("job2", "instance2", sumMetric(sum2, doublePoint(k1v1k2v2, t5, t5, 10)))),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestSummaryNoCount(t *testing.T) {
@@ -159,7 +168,7 @@ func TestSummaryNoCount(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestSummaryFlagNoRecordedValue(t *testing.T) {
@@ -176,7 +185,41 @@ func TestSummaryFlagNoRecordedValue(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
+}
+
+func TestSumCounterResetTelemetry(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) }) //nolint:usetesting
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(tel.NewTelemetrySettings())
+	require.NoError(t, err)
+
+	ma := NewInitialPointAdjuster(zap.NewNop(), time.Minute, false, telemetryBuilder)
+	for _, md := range []pmetric.Metrics{
+		metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))),
+		metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t2, t2, 66))),
+		// The value decreased, which is a counter reset.
+		metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 55))),
+		metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t4, t4, 72))),
+	} {
+		rm := md.ResourceMetrics().At(0)
+		rm.Resource().Attributes().PutStr(string(semconv.ServiceNameKey), "job")
+		rm.Resource().Attributes().PutStr(string(semconv.ServiceInstanceIDKey), "0")
+		require.NoError(t, ma.AdjustMetrics(md))
+	}
+
+	metadatatest.AssertEqualPrometheusreceiverCounterResets(t, tel,
+		[]metricdata.DataPoint[int64]{{
+			Value:      1,
+			Attributes: attribute.NewSet(attribute.String("metric", sum1)),
+		}},
+		metricdatatest.IgnoreTimestamp())
+}
+
+func newNopTelemetryBuilder(t *testing.T) *metadata.TelemetryBuilder {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	return telemetryBuilder
 }
 
 func TestSummary(t *testing.T) {
@@ -219,7 +262,7 @@ func TestSummary(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestHistogram(t *testing.T) {
@@ -242,7 +285,7 @@ func TestHistogram(t *testing.T) {
 			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t3, t4, bounds0, []uint64{7, 4, 2, 12}))),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestHistogramFlagNoRecordedValue(t *testing.T) {
@@ -259,7 +302,7 @@ func TestHistogramFlagNoRecordedValue(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestHistogramFlagNoRecordedValueFirstObservation(t *testing.T) {
@@ -276,7 +319,7 @@ func TestHistogramFlagNoRecordedValueFirstObservation(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 // In TestExponentHistogram we exclude negative buckets on purpose as they are
@@ -303,7 +346,7 @@ func TestExponentialHistogram(t *testing.T) {
 			adjusted:    metrics(exponentialHistogramMetric(histogram1, exponentialHistogramPoint(k1v1k2v2, t3, t4, 3, 1, 0, []uint64{}, -2, []uint64{7, 4, 2, 12}))),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestExponentialHistogramFlagNoRecordedValue(t *testing.T) {
@@ -320,7 +363,7 @@ func TestExponentialHistogramFlagNoRecordedValue(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestExponentialHistogramFlagNoRecordedValueFirstObservation(t *testing.T) {
@@ -337,7 +380,7 @@ func TestExponentialHistogramFlagNoRecordedValueFirstObservation(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestSummaryFlagNoRecordedValueFirstObservation(t *testing.T) {
@@ -354,7 +397,7 @@ func TestSummaryFlagNoRecordedValueFirstObservation(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestGaugeFlagNoRecordedValueFirstObservation(t *testing.T) {
@@ -371,7 +414,7 @@ func TestGaugeFlagNoRecordedValueFirstObservation(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestSumFlagNoRecordedValueFirstObservation(t *testing.T) {
@@ -388,7 +431,7 @@ func TestSumFlagNoRecordedValueFirstObservation(t *testing.T) {
 		},
 	}
 
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestMultiMetrics(t *testing.T) {
@@ -452,7 +495,7 @@ func TestMultiMetrics(t *testing.T) {
 			),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestNewDataPointsAdded(t *testing.T) {
@@ -514,7 +557,7 @@ func TestNewDataPointsAdded(t *testing.T) {
 			),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestMultiTimeseries(t *testing.T) {
@@ -573,7 +616,7 @@ func TestMultiTimeseries(t *testing.T) {
 			),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestEmptyLabels(t *testing.T) {
@@ -599,7 +642,7 @@ func TestEmptyLabels(t *testing.T) {
 			adjusted:    metrics(sumMetric(sum1, doublePoint(k1vEmptyk2vEmptyk3vEmpty, t1, t3, 88))),
 		},
 	}
-	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true), "job", "0", script)
+	runScript(t, NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t)), "job", "0", script)
 }
 
 func TestTsGC(t *testing.T) {
@@ -653,7 +696,7 @@ func TestTsGC(t *testing.T) {
 		},
 	}
 
-	ma := NewInitialPointAdjuster(zap.NewNop(), time.Minute, true)
+	ma := NewInitialPointAdjuster(zap.NewNop(), time.Minute, true, newNopTelemetryBuilder(t))
 
 	// run round 1
 	runScript(t, ma, "job", "0", script1)
@@ -713,7 +756,7 @@ func TestJobGC(t *testing.T) {
 	}
 
 	gcInterval := 10 * time.Millisecond
-	ma := NewInitialPointAdjuster(zap.NewNop(), gcInterval, true)
+	ma := NewInitialPointAdjuster(zap.NewNop(), gcInterval, true, newNopTelemetryBuilder(t))
 
 	// run job 1, round 1 - all entries marked
 	runScript(t, ma, "job1", "0", job1Script1)
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+type trackedMetricFamilyKey struct {
+	resource resourceKey
+	family   metricFamilyKey
+}
+
+type trackedMetricType struct {
+	mtype    pmetric.MetricType
+	lastSeen time.Time
+}
+
+// metricTypeTracker remembers the type of each metric family of each target across scrapes,
+// so that a family which changes its type between scrapes (e.g. from gauge to counter) can be
+// detected. It is safe for concurrent use by the transactions of all targets.
+type metricTypeTracker struct {
+	sync.Mutex
+	gcInterval time.Duration
+	lastGC     time.Time
+	types      map[trackedMetricFamilyKey]trackedMetricType
+}
+
+func newMetricTypeTracker(gcInterval time.Duration) *metricTypeTracker {
+	return &metricTypeTracker{
+		gcInterval: gcInterval,
+		lastGC:     time.Now(),
+		types:      make(map[trackedMetricFamilyKey]trackedMetricType),
+	}
+}
+
+// observe records the type of the metric family for the target. If the family was seen with a
+// different type before, the recorded type is kept and returned with false. The recorded type
+// is forgotten once the family hasn't been observed with it for gcInterval, after which the new
+// type is accepted.
+func (tr *metricTypeTracker) observe(rKey resourceKey, mfKey metricFamilyKey, mtype pmetric.MetricType) (pmetric.MetricType, bool) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	now := time.Now()
+	if now.Sub(tr.lastGC) > tr.gcInterval {
+		for key, tracked := range tr.types {
+			if now.Sub(tracked.lastSeen) > tr.gcInterval {
+				delete(tr.types, key)
+			}
+		}
+		tr.lastGC = now
+	}
+
+	key := trackedMetricFamilyKey{resource: rKey, family: mfKey}
+	if tracked, ok := tr.types[key]; ok && tracked.mtype != mtype {
+		return tracked.mtype, false
+	}
+	tr.types[key] = trackedMetricType{mtype: mtype, lastSeen: now}
+	return mtype, true
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestMetricTypeTracker(t *testing.T) {
+	tracker := newMetricTypeTracker(time.Hour)
+	target1 := resourceKey{job: "job", instance: "target1"}
+	target2 := resourceKey{job: "job", instance: "target2"}
+	family := metricFamilyKey{name: "requests"}
+
+	mtype, ok := tracker.observe(target1, family, pmetric.MetricTypeGauge)
+	assert.True(t, ok)
+	assert.Equal(t, pmetric.MetricTypeGauge, mtype)
+
+	mtype, ok = tracker.observe(target1, family, pmetric.MetricTypeGauge)
+	assert.True(t, ok)
+	assert.Equal(t, pmetric.MetricTypeGauge, mtype)
+
+	// the type changed, the previous type is kept
+	mtype, ok = tracker.observe(target1, family, pmetric.MetricTypeSum)
+	assert.False(t, ok)
+	assert.Equal(t, pmetric.MetricTypeGauge, mtype)
+
+	// types are tracked per target and per family
+	_, ok = tracker.observe(target2, family, pmetric.MetricTypeSum)
+	assert.True(t, ok)
+	_, ok = tracker.observe(target1, metricFamilyKey{name: "requests", isExponentialHistogram: true}, pmetric.MetricTypeExponentialHistogram)
+	assert.True(t, ok)
+}
+
+func TestMetricTypeTrackerForgetsExpiredTypes(t *testing.T) {
+	tracker := newMetricTypeTracker(time.Minute)
+	rKey := resourceKey{job: "job", instance: "target"}
+	family := metricFamilyKey{name: "requests"}
+
+	_, ok := tracker.observe(rKey, family, pmetric.MetricTypeGauge)
+	assert.True(t, ok)
+	_, ok = tracker.observe(rKey, family, pmetric.MetricTypeSum)
+	assert.False(t, ok)
+
+	// the gauge hasn't been observed for longer than the gc interval
+	key := trackedMetricFamilyKey{resource: rKey, family: family}
+	tracked := tracker.types[key]
+	tracked.lastSeen = tracked.lastSeen.Add(-2 * time.Minute)
+	tracker.types[key] = tracked
+	tracker.lastGC = tracker.lastGC.Add(-2 * time.Minute)
+
+	mtype, ok := tracker.observe(rKey, family, pmetric.MetricTypeSum)
+	assert.True(t, ok)
+	assert.Equal(t, pmetric.MetricTypeSum, mtype)
+}
@@ -62,6 +62,20 @@ func CreateResource(job, instance string, serviceDiscoveryLabels labels.Labels)
 	return resource
 }
 
+// addTargetLabelsResource adds the job, instance, scheme and metrics path labels
+// of the scrape target as resource attributes, using their Prometheus label names.
+// These labels are never converted to data point attributes.
+func addTargetLabelsResource(attrs pcommon.Map, job, instance string, serviceDiscoveryLabels labels.Labels) {
+	attrs.PutStr(model.JobLabel, job)
+	attrs.PutStr(model.InstanceLabel, instance)
+	if scheme := serviceDiscoveryLabels.Get(model.SchemeLabel); scheme != "" {
+		attrs.PutStr("scheme", scheme)
+	}
+	if metricsPath := serviceDiscoveryLabels.Get(model.MetricsPathLabel); metricsPath != "" {
+		attrs.PutStr("metrics_path", metricsPath)
+	}
+}
+
 // kubernetesDiscoveryToResourceAttributes maps from metadata labels discovered
 // through the kubernetes implementation of service discovery to opentelemetry
 // resource attribute keys.
@@ -11,6 +11,8 @@ import (
 	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadata"
 )
 
 var (
@@ -45,10 +47,11 @@ type startTimeMetricAdjuster struct {
 }
 
 // NewStartTimeMetricAdjuster returns a new MetricsAdjuster that adjust metrics' start times based on a start time metric.
-func NewStartTimeMetricAdjuster(logger *zap.Logger, startTimeMetricRegex *regexp.Regexp, gcInterval time.Duration) MetricsAdjuster {
+func NewStartTimeMetricAdjuster(logger *zap.Logger, startTimeMetricRegex *regexp.Regexp, gcInterval time.Duration, telemetryBuilder *metadata.TelemetryBuilder) MetricsAdjuster {
 	resetPointAdjuster := &initialPointAdjuster{
 		jobsMap:              NewJobsMap(gcInterval),
 		logger:               logger,
+		telemetryBuilder:     telemetryBuilder,
 		useCreatedMetric:     false,
 		usePointTimeForReset: true,
 	}
@@ -119,7 +119,7 @@ func TestStartTimeMetricMatch(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			gcInterval := 10 * time.Millisecond
-			stma := NewStartTimeMetricAdjuster(zap.NewNop(), tt.startTimeMetricRegex, gcInterval)
+			stma := NewStartTimeMetricAdjuster(zap.NewNop(), tt.startTimeMetricRegex, gcInterval, newNopTelemetryBuilder(t))
 			if tt.expectedErr != nil {
 				assert.ErrorIs(t, stma.AdjustMetrics(tt.inputs), tt.expectedErr)
 				return
@@ -219,7 +219,7 @@ func TestStartTimeMetricFallback(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			testutil.SetFeatureGateForTest(t, useCollectorStartTimeFallbackGate, true)
 			gcInterval := 10 * time.Millisecond
-			stma := NewStartTimeMetricAdjuster(zap.NewNop(), tt.startTimeMetricRegex, gcInterval)
+			stma := NewStartTimeMetricAdjuster(zap.NewNop(), tt.startTimeMetricRegex, gcInterval, newNopTelemetryBuilder(t))
 			if tt.expectedErr != nil {
 				assert.ErrorIs(t, stma.AdjustMetrics(tt.inputs), tt.expectedErr)
 				return
@@ -464,7 +464,7 @@ func TestFallbackAndReset(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			testutil.SetFeatureGateForTest(t, useCollectorStartTimeFallbackGate, tt.useFallback)
 			gcInterval := 10 * time.Millisecond
-			stma := NewStartTimeMetricAdjuster(zap.NewNop(), nil, gcInterval)
+			stma := NewStartTimeMetricAdjuster(zap.NewNop(), nil, gcInterval, newNopTelemetryBuilder(t))
 			// To test that the adjuster is using the fallback correctly, override the fallback time to use
 			// directly.
 			approximateCollectorStartTime = mockStartTime
@@ -8,6 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/exemplar"
@@ -24,6 +28,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
@@ -51,6 +57,80 @@ type metricFamilyKey struct {
 	name                   string
 }
 
+// TransactionOptions holds optional settings which control how the samples
+// of a scrape are converted into OTLP metrics.
+type TransactionOptions struct {
+	// AlignTimestampsToScrapeStart sets the timestamp of all data points of a scrape
+	// to the scrape start time, instead of the timestamp of the individual samples.
+	AlignTimestampsToScrapeStart bool
+	// ExcludedLabels lists label names which are dropped from all samples, in addition
+	// to the well-known labels which are never converted to data point attributes.
+	ExcludedLabels []string
+	// CumulativeToDelta converts monotonic cumulative sums into delta sums by
+	// differencing the values of consecutive scrapes of each series.
+	CumulativeToDelta bool
+	// PromoteTargetLabels adds the job, instance, scheme and metrics_path labels of the
+	// scrape target as resource attributes, in addition to the derived semantic
+	// convention attributes.
+	PromoteTargetLabels bool
+	// DisableMetricFamilyNormalization treats each metric name as its own metric family,
+	// instead of grouping names which only differ by a well-known suffix.
+	DisableMetricFamilyNormalization bool
+	// NonFiniteValues selects how NaN and infinite counter and gauge values, other than
+	// the staleness marker, are handled: NonFiniteValuesKeep (or empty), NonFiniteValuesDrop
+	// or NonFiniteValuesClamp.
+	NonFiniteValues string
+	// TrimTypeSuffixes trims the type suffix (_total of counters) from metric names.
+	TrimTypeSuffixes bool
+	// TrimUnitSuffixes trims the unit suffix (e.g. _seconds) from metric names.
+	TrimUnitSuffixes bool
+	// DetectMetricTypeChanges drops the samples of a metric family whose type differs from its
+	// type in previous scrapes of the same target, e.g. a gauge which became a counter.
+	DetectMetricTypeChanges bool
+	// MaxHistogramBuckets limits the number of buckets of each histogram data point, including the
+	// +Inf bucket. The counts of the buckets exceeding the limit are merged into the +Inf bucket.
+	// Zero means unlimited.
+	MaxHistogramBuckets int
+	// TargetLabelAttributes adds the job, instance, scheme and metrics_path labels of each sample
+	// as data point attributes, prefixed with "target.". These labels are otherwise never
+	// converted to data point attributes.
+	TargetLabelAttributes bool
+	// MetricNameValidation selects the naming rules metric names are validated against:
+	// MetricNameValidationNone (or empty), MetricNameValidationLegacy or MetricNameValidationUTF8.
+	// Samples with invalid names are dropped.
+	MetricNameValidation string
+	// HistogramRepresentations selects which representation is kept when a histogram is exposed
+	// with both classic and native buckets: HistogramRepresentationsBoth (or empty),
+	// HistogramRepresentationsNative or HistogramRepresentationsClassic.
+	HistogramRepresentations string
+	// MetricNameRules rename or drop samples by their metric name before they are assigned to
+	// a metric family. The first matching rule applies. The type of a renamed metric is looked
+	// up by its original name.
+	MetricNameRules []MetricNameRule
+	// FutureTimestamps selects how samples whose timestamp exceeds the current time by more than
+	// FutureTimestampTolerance are handled: FutureTimestampsKeep (or empty), FutureTimestampsDrop
+	// or FutureTimestampsClamp, which sets their timestamp to the current time.
+	FutureTimestamps string
+	// FutureTimestampTolerance is how far the timestamp of a sample may exceed the current time
+	// before it is handled according to FutureTimestamps.
+	FutureTimestampTolerance time.Duration
+	// SummaryAsGauges replaces each summary by a gauge holding a data point per quantile, with a
+	// quantile attribute, and the _count and _sum gauges of the summary.
+	SummaryAsGauges bool
+	// MaxExemplarsPerDataPoint limits the number of exemplars of each data point. Exemplars exceeding
+	// the limit are dropped, keeping the ones scraped first. Zero means unlimited.
+	MaxExemplarsPerDataPoint int
+	// MaxExemplarLabels limits the number of labels of each exemplar converted to filtered attributes.
+	// Labels exceeding the limit are dropped in name order; the trace and span id labels are always
+	// kept. Zero means unlimited.
+	MaxExemplarLabels int
+	// MaxLabelValueLength limits the number of characters of label values. Longer values are
+	// truncated, and the names of the truncated labels are added to the sample in the
+	// TruncatedLabelsAttribute label. The metric name, job, instance, le and quantile labels are
+	// never truncated. Zero means unlimited.
+	MaxLabelValueLength int
+}
+
 type transaction struct {
 	isNew                  bool
 	trimSuffixes           bool
@@ -67,7 +147,26 @@ type transaction struct {
 	logger                 *zap.Logger
 	buildInfo              component.BuildInfo
 	metricAdjuster         MetricsAdjuster
+	deltaAdjuster          MetricsAdjuster    // only set if cumulative sums are converted to delta sums.
+	typeTracker            *metricTypeTracker // only set if metric type changes are detected.
 	obsrecv                *receiverhelper.ObsReport
+	telemetryBuilder       *mdata.TelemetryBuilder // not set in tests which don't check the receiver telemetry.
+	opts                   TransactionOptions
+	// droppedTimeseries counts the samples dropped in this scrape by reason.
+	droppedTimeseries map[droppedReason]int
+	// mergedHistogramBuckets counts the histogram buckets merged into the +Inf bucket in this scrape.
+	mergedHistogramBuckets int
+	// droppedExemplars counts the exemplars dropped in this scrape because of
+	// TransactionOptions.MaxExemplarsPerDataPoint.
+	droppedExemplars int
+	// droppedExemplarLabels counts the exemplar labels dropped in this scrape because of
+	// TransactionOptions.MaxExemplarLabels.
+	droppedExemplarLabels int
+	// renamedMetrics maps the names of metrics renamed by TransactionOptions.MetricNameRules
+	// to their original names.
+	renamedMetrics map[string]string
+	// scrapeStartMs is the scrape start time, taken from the timestamp of the `up` metric.
+	scrapeStartMs int64
 	// Used as buffer to calculate series ref hash.
 	bufBytes []byte
 }
@@ -126,6 +225,8 @@ func (t *transaction) Append(_ storage.SeriesRef, ls labels.Labels, atMs int64,
 		})
 		ls = b.Labels()
 	}
+	ls = t.withoutExcludedLabels(ls)
+	ls = t.withTruncatedLabelValues(ls)
 
 	rKey, err := t.initTransaction(ls)
 	if err != nil {
@@ -137,13 +238,29 @@ func (t *transaction) Append(_ storage.SeriesRef, ls labels.Labels, atMs int64,
 	// * https://github.com/open-telemetry/opentelemetry-collector/issues/3407
 	// as Prometheus rejects such too as of version 2.16.0, released on 2020-02-13.
 	if dupLabel, hasDup := ls.HasDuplicateLabelNames(); hasDup {
+		t.recordDropped(droppedReasonDuplicateLabels)
 		return 0, fmt.Errorf("invalid sample: non-unique label names: %q", dupLabel)
 	}
 
 	metricName := ls.Get(model.MetricNameLabel)
 	if metricName == "" {
+		t.recordDropped(droppedReasonNoMetricName)
 		return 0, errMetricNameNotFound
 	}
+	if !t.isValidMetricName(metricName) {
+		t.dropInvalidMetricName(metricName, ls)
+		return 0, nil
+	}
+	metricName, ls, keep := t.applyMetricNameRules(metricName, ls)
+	if !keep {
+		t.recordDropped(droppedReasonMetricNameRule)
+		return 0, nil
+	}
+
+	// The `up` metric is always reported with the timestamp of the scrape start.
+	if metricName == scrapeUpMetricName {
+		t.scrapeStartMs = atMs
+	}
 
 	// See https://www.prometheus.io/docs/concepts/jobs_instances/#automatically-generated-labels-and-time-series
 	// up: 1 if the instance is healthy, i.e. reachable, or 0 if the scrape failed.
@@ -182,10 +299,33 @@ func (t *transaction) Append(_ storage.SeriesRef, ls labels.Labels, atMs int64,
 	}
 
 	curMF := t.getOrCreateMetricFamily(*rKey, scope, metricName)
+	if !curMF.acceptsSample(metricName) {
+		t.recordDropped(droppedReasonIncompatibleFamily)
+		t.logger.Warn("dropping datapoint whose name collides with an incompatible metric family",
+			zap.String("metric_name", metricName),
+			zap.String("metric_family", curMF.name),
+			zap.Stringer("metric_type", curMF.mtype),
+			zap.Any("labels", ls))
+		return 0, nil
+	}
+	if curMF.typeChanged {
+		t.recordDropped(droppedReasonTypeChanged)
+		return 0, nil
+	}
+
+	val, ok := t.handleNonFiniteValue(curMF, metricName, ls, val)
+	if !ok {
+		return 0, nil
+	}
+	atMs, ok = t.handleFutureTimestamp(metricName, ls, atMs)
+	if !ok {
+		return 0, nil
+	}
 
 	seriesRef := t.getSeriesRef(ls, curMF.mtype)
 	err = curMF.addSeries(seriesRef, metricName, ls, atMs, val)
 	if err != nil {
+		t.recordDropped(droppedReasonForError(err))
 		t.logger.Warn("failed to add datapoint", zap.Error(err), zap.String("metric_name", metricName), zap.Any("labels", ls))
 	}
 
@@ -240,18 +380,33 @@ func (t *transaction) getOrCreateMetricFamily(key resourceKey, scope scopeID, mn
 
 	if !ok {
 		fn := mn
-		if _, ok := t.mc.GetMetadata(mn); !ok {
+		if _, ok := t.mc.GetMetadata(mn); !ok && !t.opts.DisableMetricFamilyNormalization {
 			fn = normalizeMetricName(mn)
 		}
 		fnKey := metricFamilyKey{isExponentialHistogram: mfKey.isExponentialHistogram, name: fn}
 		mf, ok := t.families[key][scope][fnKey]
 		if !ok || !mf.includesMetric(mn) {
-			curMf = newMetricFamily(mn, t.mc, t.logger)
+			if t.opts.DisableMetricFamilyNormalization {
+				curMf = newUnnormalizedMetricFamily(mn, t.mc, t.logger)
+			} else {
+				curMf = newMetricFamily(mn, t.mc, t.logger)
+			}
 			// Don't convert NHCB to ExponentialHistogram.
 			if curMf.mtype == pmetric.MetricTypeHistogram && mfKey.isExponentialHistogram && !t.addingNHCB {
 				curMf.mtype = pmetric.MetricTypeExponentialHistogram
 			}
-			t.families[key][scope][metricFamilyKey{isExponentialHistogram: mfKey.isExponentialHistogram, name: curMf.name}] = curMf
+			curMf.targetLabelAttributes = t.opts.TargetLabelAttributes
+			curMfKey := metricFamilyKey{isExponentialHistogram: mfKey.isExponentialHistogram, name: curMf.name}
+			if t.typeTracker != nil {
+				if previousType, ok := t.typeTracker.observe(key, curMfKey, curMf.mtype); !ok {
+					curMf.typeChanged = true
+					t.logger.Warn("dropping metric family whose type changed since a previous scrape",
+						zap.String("metric_family", curMf.name),
+						zap.Stringer("previous_type", previousType),
+						zap.Stringer("metric_type", curMf.mtype))
+				}
+			}
+			t.families[key][scope][curMfKey] = curMf
 			return curMf
 		}
 		curMf = mf
@@ -271,7 +426,8 @@ func (t *transaction) AppendExemplar(_ storage.SeriesRef, l labels.Labels, e exe
 		return 0, err
 	}
 
-	l = l.WithoutEmpty()
+	l = t.withoutExcludedLabels(l.WithoutEmpty())
+	l = t.withTruncatedLabelValues(l)
 
 	if dupLabel, hasDup := l.HasDuplicateLabelNames(); hasDup {
 		return 0, fmt.Errorf("invalid sample: non-unique label names: %q", dupLabel)
@@ -281,9 +437,25 @@ func (t *transaction) AppendExemplar(_ storage.SeriesRef, l labels.Labels, e exe
 	if mn == "" {
 		return 0, errMetricNameNotFound
 	}
+	if !t.isValidMetricName(mn) {
+		// The sample of the exemplar is dropped as well, so there is nothing to attach it to.
+		return 0, nil
+	}
+	mn, l, keep := t.applyMetricNameRules(mn, l)
+	if !keep {
+		return 0, nil
+	}
+
+	if t.opts.MaxExemplarLabels > 0 {
+		var dropped int
+		e.Labels, dropped = limitExemplarLabels(e.Labels, t.opts.MaxExemplarLabels)
+		t.droppedExemplarLabels += dropped
+	}
 
 	mf := t.getOrCreateMetricFamily(*rKey, getScopeID(l), mn)
-	mf.addExemplar(t.getSeriesRef(l, mf.mtype), e)
+	if mf.addExemplar(t.getSeriesRef(l, mf.mtype), l, e, t.opts.MaxExemplarsPerDataPoint) {
+		t.droppedExemplars++
+	}
 
 	return 0, nil
 }
@@ -315,6 +487,8 @@ func (t *transaction) AppendHistogram(_ storage.SeriesRef, ls labels.Labels, atM
 		})
 		ls = b.Labels()
 	}
+	ls = t.withoutExcludedLabels(ls)
+	ls = t.withTruncatedLabelValues(ls)
 
 	rKey, err := t.initTransaction(ls)
 	if err != nil {
@@ -326,18 +500,37 @@ func (t *transaction) AppendHistogram(_ storage.SeriesRef, ls labels.Labels, atM
 	// * https://github.com/open-telemetry/opentelemetry-collector/issues/3407
 	// as Prometheus rejects such too as of version 2.16.0, released on 2020-02-13.
 	if dupLabel, hasDup := ls.HasDuplicateLabelNames(); hasDup {
+		t.recordDropped(droppedReasonDuplicateLabels)
 		return 0, fmt.Errorf("invalid sample: non-unique label names: %q", dupLabel)
 	}
 
 	metricName := ls.Get(model.MetricNameLabel)
 	if metricName == "" {
+		t.recordDropped(droppedReasonNoMetricName)
 		return 0, errMetricNameNotFound
 	}
+	if !t.isValidMetricName(metricName) {
+		t.dropInvalidMetricName(metricName, ls)
+		return 0, nil
+	}
+	metricName, ls, keep := t.applyMetricNameRules(metricName, ls)
+	if !keep {
+		t.recordDropped(droppedReasonMetricNameRule)
+		return 0, nil
+	}
 
 	// The `up`, `target_info`, `otel_scope_info` metrics should never generate native histograms,
 	// thus we don't check for them here as opposed to the Append function.
 
 	curMF := t.getOrCreateMetricFamily(*rKey, getScopeID(ls), metricName)
+	if curMF.typeChanged {
+		t.recordDropped(droppedReasonTypeChanged)
+		return 0, nil
+	}
+	atMs, keep = t.handleFutureTimestamp(metricName, ls, atMs)
+	if !keep {
+		return 0, nil
+	}
 
 	if h != nil && h.CounterResetHint == histogram.GaugeType || fh != nil && fh.CounterResetHint == histogram.GaugeType {
 		t.logger.Warn("dropping unsupported gauge histogram datapoint", zap.String("metric_name", metricName), zap.Any("labels", ls))
@@ -349,6 +542,7 @@ func (t *transaction) AppendHistogram(_ storage.SeriesRef, ls labels.Labels, atM
 		err = curMF.addExponentialHistogramSeries(t.getSeriesRef(ls, curMF.mtype), metricName, ls, atMs, h, fh)
 	}
 	if err != nil {
+		t.recordDropped(droppedReasonForError(err))
 		t.logger.Warn("failed to add histogram datapoint", zap.Error(err), zap.String("metric_name", metricName), zap.Any("labels", ls))
 	}
 
@@ -387,6 +581,8 @@ func (t *transaction) setCreationTimestamp(ls labels.Labels, atMs, ctMs int64) (
 		})
 		ls = b.Labels()
 	}
+	ls = t.withoutExcludedLabels(ls)
+	ls = t.withTruncatedLabelValues(ls)
 
 	rKey, err := t.initTransaction(ls)
 	if err != nil {
@@ -405,8 +601,18 @@ func (t *transaction) setCreationTimestamp(ls labels.Labels, atMs, ctMs int64) (
 	if metricName == "" {
 		return 0, errMetricNameNotFound
 	}
+	if !t.isValidMetricName(metricName) {
+		return 0, nil
+	}
+	metricName, ls, keep := t.applyMetricNameRules(metricName, ls)
+	if !keep {
+		return 0, nil
+	}
 
 	curMF := t.getOrCreateMetricFamily(*rKey, getScopeID(ls), metricName)
+	if curMF.typeChanged {
+		return 0, nil
+	}
 
 	seriesRef := t.getSeriesRef(ls, curMF.mtype)
 	curMF.addCreationTimestamp(seriesRef, ls, atMs, ctMs)
@@ -418,6 +624,154 @@ func (*transaction) SetOptions(_ *storage.AppendOptions) {
 	// TODO: implement this func
 }
 
+// withoutExcludedLabels removes the configured excluded labels from the label set.
+// Labels which are required to build the metrics, like the metric name, job, instance
+// and the histogram bucket and summary quantile labels, are never removed.
+func (t *transaction) withoutExcludedLabels(ls labels.Labels) labels.Labels {
+	if len(t.opts.ExcludedLabels) == 0 {
+		return ls
+	}
+	b := labels.NewBuilder(ls)
+	for _, name := range t.opts.ExcludedLabels {
+		if isRequiredLabel(name) {
+			continue
+		}
+		b.Del(name)
+	}
+	return b.Labels()
+}
+
+// withTruncatedLabelValues truncates the label values exceeding TransactionOptions.MaxLabelValueLength
+// characters, and flags the sample by listing the names of the truncated labels, joined by commas, in
+// the TruncatedLabelsAttribute label.
+func (t *transaction) withTruncatedLabelValues(ls labels.Labels) labels.Labels {
+	maxLength := t.opts.MaxLabelValueLength
+	if maxLength <= 0 {
+		return ls
+	}
+	var truncated []string
+	ls.Range(func(l labels.Label) {
+		if len(l.Value) > maxLength && !isRequiredLabel(l.Name) && utf8.RuneCountInString(l.Value) > maxLength {
+			truncated = append(truncated, l.Name)
+		}
+	})
+	if len(truncated) == 0 {
+		return ls
+	}
+	b := labels.NewBuilder(ls)
+	for _, name := range truncated {
+		b.Set(name, truncateString(ls.Get(name), maxLength))
+	}
+	b.Set(TruncatedLabelsAttribute, strings.Join(truncated, ","))
+	return b.Labels()
+}
+
+// isValidMetricName returns false if the metric name violates the configured naming rules.
+func (t *transaction) isValidMetricName(metricName string) bool {
+	switch t.opts.MetricNameValidation {
+	case MetricNameValidationLegacy:
+		return model.LegacyValidation.IsValidMetricName(metricName)
+	case MetricNameValidationUTF8:
+		return model.UTF8Validation.IsValidMetricName(metricName)
+	default:
+		return true
+	}
+}
+
+// dropInvalidMetricName records and logs a sample dropped because of its metric name.
+func (t *transaction) dropInvalidMetricName(metricName string, ls labels.Labels) {
+	t.recordDropped(droppedReasonInvalidMetricName)
+	t.logger.Debug("dropping datapoint with invalid metric name",
+		zap.String("metric_name", metricName),
+		zap.Any("labels", ls))
+}
+
+// handleNonFiniteValue applies the configured handling to non-stale NaN and infinite values
+// of counters and gauges. It returns the value to add and false if the sample is dropped.
+func (t *transaction) handleNonFiniteValue(mf *metricFamily, metricName string, ls labels.Labels, val float64) (float64, bool) {
+	if mf.mtype != pmetric.MetricTypeSum && mf.mtype != pmetric.MetricTypeGauge {
+		return val, true
+	}
+	if value.IsStaleNaN(val) || (!math.IsNaN(val) && !math.IsInf(val, 0)) {
+		return val, true
+	}
+	switch t.opts.NonFiniteValues {
+	case NonFiniteValuesDrop:
+		t.recordDropped(droppedReasonNonFiniteValue)
+		t.logger.Debug("dropping datapoint with non-finite value",
+			zap.String("metric_name", metricName),
+			zap.Float64("value", val),
+			zap.Any("labels", ls))
+		return 0, false
+	case NonFiniteValuesClamp:
+		clamped := clampNonFinite(val)
+		t.logger.Debug("clamping non-finite datapoint value",
+			zap.String("metric_name", metricName),
+			zap.Float64("value", val),
+			zap.Float64("clamped_value", clamped),
+			zap.Any("labels", ls))
+		return clamped, true
+	default:
+		return val, true
+	}
+}
+
+// handleFutureTimestamp applies the configured handling to samples whose timestamp exceeds the
+// current time by more than the tolerance. It returns the timestamp to use and false if the
+// sample is dropped.
+func (t *transaction) handleFutureTimestamp(metricName string, ls labels.Labels, atMs int64) (int64, bool) {
+	if t.opts.FutureTimestamps == "" || t.opts.FutureTimestamps == FutureTimestampsKeep {
+		return atMs, true
+	}
+	nowMs := time.Now().UnixMilli()
+	if atMs <= nowMs+t.opts.FutureTimestampTolerance.Milliseconds() {
+		return atMs, true
+	}
+	switch t.opts.FutureTimestamps {
+	case FutureTimestampsDrop:
+		t.recordDropped(droppedReasonFutureTimestamp)
+		t.logger.Debug("dropping datapoint with future timestamp",
+			zap.String("metric_name", metricName),
+			zap.Int64("timestamp", atMs),
+			zap.Any("labels", ls))
+		return atMs, false
+	case FutureTimestampsClamp:
+		t.logger.Debug("clamping future datapoint timestamp",
+			zap.String("metric_name", metricName),
+			zap.Int64("timestamp", atMs),
+			zap.Int64("clamped_timestamp", nowMs),
+			zap.Any("labels", ls))
+		return nowMs, true
+	default:
+		return atMs, true
+	}
+}
+
+func (t *transaction) recordDropped(reason droppedReason) {
+	if t.droppedTimeseries == nil {
+		t.droppedTimeseries = make(map[droppedReason]int)
+	}
+	t.droppedTimeseries[reason]++
+}
+
+// reportDropped logs and records the telemetry of the samples dropped in this scrape.
+func (t *transaction) reportDropped() {
+	if len(t.droppedTimeseries) == 0 {
+		return
+	}
+	fields := make([]zap.Field, 0, len(t.droppedTimeseries))
+	for reason, count := range t.droppedTimeseries {
+		fields = append(fields, zap.Int(string(reason), count))
+	}
+	t.logger.Debug("dropped timeseries during scrape", fields...)
+	if t.telemetryBuilder != nil {
+		for reason, count := range t.droppedTimeseries {
+			t.telemetryBuilder.PrometheusreceiverDroppedTimeseries.Add(context.Background(), int64(count),
+				metric.WithAttributes(attribute.String("reason", string(reason))))
+		}
+	}
+}
+
 func (t *transaction) getSeriesRef(ls labels.Labels, mtype pmetric.MetricType) uint64 {
 	var hash uint64
 	hash, t.bufBytes = getSeriesRef(t.bufBytes, ls, mtype)
@@ -467,8 +821,28 @@ func (t *transaction) getMetrics() (pmetric.Metrics, error) {
 				}
 			}
 			metrics := ils.Metrics()
-			for _, mf := range mfs {
-				mf.appendMetric(metrics, t.trimSuffixes)
+			for mfKey, mf := range mfs {
+				if t.isDuplicateHistogram(mfs, mfKey) {
+					continue
+				}
+				nonMonotonicPoints := mf.appendMetric(metrics, t.trimSuffixes || t.opts.TrimTypeSuffixes, t.trimSuffixes || t.opts.TrimUnitSuffixes)
+				if nonMonotonicPoints > 0 {
+					t.logger.Debug("dropping histogram datapoints with non-monotonic bucket counts",
+						zap.String("metric_name", mf.name),
+						zap.Int("count", nonMonotonicPoints))
+					for range nonMonotonicPoints {
+						t.recordDropped(droppedReasonNonMonotonicBuckets)
+					}
+				}
+			}
+			if t.opts.SummaryAsGauges {
+				expandSummaries(metrics)
+			}
+			if t.opts.AlignTimestampsToScrapeStart && t.scrapeStartMs != 0 {
+				alignTimestamps(metrics, timestampFromMs(t.scrapeStartMs))
+			}
+			if t.opts.MaxHistogramBuckets > 0 {
+				t.mergedHistogramBuckets += limitHistogramBuckets(metrics, t.opts.MaxHistogramBuckets)
 			}
 		}
 	}
@@ -490,6 +864,129 @@ func (t *transaction) getMetrics() (pmetric.Metrics, error) {
 	return md, nil
 }
 
+// isDuplicateHistogram reports whether the family with the given key is the representation
+// of a histogram which isn't kept because the other representation of the same histogram
+// exists in mfs, according to TransactionOptions.HistogramRepresentations.
+func (t *transaction) isDuplicateHistogram(mfs map[metricFamilyKey]*metricFamily, mfKey metricFamilyKey) bool {
+	switch t.opts.HistogramRepresentations {
+	case HistogramRepresentationsNative:
+		if mfKey.isExponentialHistogram || mfs[mfKey].mtype != pmetric.MetricTypeHistogram {
+			return false
+		}
+		_, ok := mfs[metricFamilyKey{isExponentialHistogram: true, name: mfKey.name}]
+		return ok
+	case HistogramRepresentationsClassic:
+		if !mfKey.isExponentialHistogram {
+			return false
+		}
+		classic, ok := mfs[metricFamilyKey{name: mfKey.name}]
+		return ok && classic.mtype == pmetric.MetricTypeHistogram
+	default:
+		return false
+	}
+}
+
+// alignTimestamps sets the timestamp of all data points to ts. Start timestamps are preserved.
+func alignTimestamps(metrics pmetric.MetricSlice, ts pcommon.Timestamp) {
+	for _, metric := range metrics.All() {
+		switch metric.Type() {
+		case pmetric.MetricTypeGauge:
+			for _, dp := range metric.Gauge().DataPoints().All() {
+				dp.SetTimestamp(ts)
+			}
+		case pmetric.MetricTypeSum:
+			for _, dp := range metric.Sum().DataPoints().All() {
+				dp.SetTimestamp(ts)
+			}
+		case pmetric.MetricTypeHistogram:
+			for _, dp := range metric.Histogram().DataPoints().All() {
+				dp.SetTimestamp(ts)
+			}
+		case pmetric.MetricTypeExponentialHistogram:
+			for _, dp := range metric.ExponentialHistogram().DataPoints().All() {
+				dp.SetTimestamp(ts)
+			}
+		case pmetric.MetricTypeSummary:
+			for _, dp := range metric.Summary().DataPoints().All() {
+				dp.SetTimestamp(ts)
+			}
+		case pmetric.MetricTypeEmpty:
+		}
+	}
+}
+
+// limitHistogramBuckets merges the buckets of histogram data points exceeding maxBuckets into the
+// +Inf bucket, and returns the number of merged buckets.
+func limitHistogramBuckets(metrics pmetric.MetricSlice, maxBuckets int) int {
+	merged := 0
+	for _, metric := range metrics.All() {
+		if metric.Type() != pmetric.MetricTypeHistogram {
+			continue
+		}
+		for _, dp := range metric.Histogram().DataPoints().All() {
+			bucketCounts := dp.BucketCounts().AsRaw()
+			if len(bucketCounts) <= maxBuckets {
+				continue
+			}
+			overflow := uint64(0)
+			for _, count := range bucketCounts[maxBuckets-1:] {
+				overflow += count
+			}
+			merged += len(bucketCounts) - maxBuckets
+			bucketCounts[maxBuckets-1] = overflow
+			dp.BucketCounts().FromRaw(bucketCounts[:maxBuckets])
+			dp.ExplicitBounds().FromRaw(dp.ExplicitBounds().AsRaw()[:maxBuckets-1])
+		}
+	}
+	return merged
+}
+
+// expandSummaries replaces each summary metric by a gauge holding a data point per quantile, with a
+// quantile attribute, and the _count and _sum gauges of the summary.
+func expandSummaries(metrics pmetric.MetricSlice) {
+	n := metrics.Len()
+	for i := 0; i < n; i++ {
+		metric := metrics.At(i)
+		if metric.Type() != pmetric.MetricTypeSummary {
+			continue
+		}
+		quantiles := appendSummaryGauge(metrics, metric, "", metric.Unit())
+		count := appendSummaryGauge(metrics, metric, metricsSuffixCount, "")
+		sum := appendSummaryGauge(metrics, metric, metricsSuffixSum, metric.Unit())
+		for _, dp := range metric.Summary().DataPoints().All() {
+			for _, qv := range dp.QuantileValues().All() {
+				point := appendSummaryGaugePoint(quantiles, dp)
+				point.Attributes().PutStr(model.QuantileLabel, strconv.FormatFloat(qv.Quantile(), 'f', -1, 64))
+				point.SetDoubleValue(qv.Value())
+			}
+			appendSummaryGaugePoint(count, dp).SetDoubleValue(float64(dp.Count()))
+			appendSummaryGaugePoint(sum, dp).SetDoubleValue(dp.Sum())
+		}
+	}
+	metrics.RemoveIf(func(metric pmetric.Metric) bool {
+		return metric.Type() == pmetric.MetricTypeSummary
+	})
+}
+
+// appendSummaryGauge appends a gauge named after the summary with the given suffix.
+func appendSummaryGauge(metrics pmetric.MetricSlice, summary pmetric.Metric, suffix, unit string) pmetric.Gauge {
+	metric := metrics.AppendEmpty()
+	metric.SetName(summary.Name() + suffix)
+	metric.SetDescription(summary.Description())
+	metric.SetUnit(unit)
+	return metric.SetEmptyGauge()
+}
+
+// appendSummaryGaugePoint appends a data point with the timestamp, attributes and flags of the
+// summary data point to the gauge.
+func appendSummaryGaugePoint(gauge pmetric.Gauge, dp pmetric.SummaryDataPoint) pmetric.NumberDataPoint {
+	point := gauge.DataPoints().AppendEmpty()
+	point.SetTimestamp(dp.Timestamp())
+	point.SetFlags(dp.Flags())
+	dp.Attributes().CopyTo(point.Attributes())
+	return point
+}
+
 func getScopeID(ls labels.Labels) scopeID {
 	var scope scopeID
 	ls.Range(func(lbl labels.Label) {
@@ -515,13 +1012,24 @@ func (t *transaction) initTransaction(lbs labels.Labels) (*resourceKey, error) {
 	if !ok {
 		return nil, errors.New("unable to find MetricMetadataStore in context")
 	}
+	if len(t.opts.MetricNameRules) > 0 {
+		if t.renamedMetrics == nil {
+			t.renamedMetrics = make(map[string]string)
+		}
+		t.mc = renamedMetadataStore{MetricMetadataStore: t.mc, renamed: t.renamedMetrics}
+	}
 
 	rKey, err := t.getJobAndInstance(lbs)
 	if err != nil {
 		return nil, err
 	}
 	if _, ok := t.nodeResources[*rKey]; !ok {
-		t.nodeResources[*rKey] = CreateResource(rKey.job, rKey.instance, target.DiscoveredLabels(labels.NewBuilder(labels.EmptyLabels())))
+		discoveredLabels := target.DiscoveredLabels(labels.NewBuilder(labels.EmptyLabels()))
+		resource := CreateResource(rKey.job, rKey.instance, discoveredLabels)
+		if t.opts.PromoteTargetLabels {
+			addTargetLabelsResource(resource.Attributes(), rKey.job, rKey.instance, discoveredLabels)
+		}
+		t.nodeResources[*rKey] = resource
 	}
 
 	t.isNew = false
@@ -566,12 +1074,25 @@ func (t *transaction) Commit() error {
 	}
 
 	ctx := t.obsrecv.StartMetricsOp(t.ctx)
+	// getMetrics drops histogram points with non-monotonic buckets, report the
+	// dropped timeseries once the metrics are built.
 	md, err := t.getMetrics()
+	t.reportDropped()
 	if err != nil {
 		t.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
 		return err
 	}
 
+	if t.mergedHistogramBuckets > 0 {
+		t.logger.Debug("merged histogram buckets exceeding the bucket limit into the +Inf bucket",
+			zap.Int("merged_buckets", t.mergedHistogramBuckets))
+	}
+	if t.droppedExemplars > 0 || t.droppedExemplarLabels > 0 {
+		t.logger.Debug("dropped exemplars and exemplar labels exceeding the exemplar limits",
+			zap.Int("dropped_exemplars", t.droppedExemplars),
+			zap.Int("dropped_exemplar_labels", t.droppedExemplarLabels))
+	}
+
 	numPoints := md.DataPointCount()
 	if numPoints == 0 {
 		return nil
@@ -584,6 +1105,16 @@ func (t *transaction) Commit() error {
 		}
 	}
 
+	if t.deltaAdjuster != nil {
+		if err = t.deltaAdjuster.AdjustMetrics(md); err != nil {
+			t.obsrecv.EndMetricsOp(ctx, dataformat, numPoints, err)
+			return err
+		}
+		if numPoints = md.DataPointCount(); numPoints == 0 {
+			return nil
+		}
+	}
+
 	err = t.sink.ConsumeMetrics(ctx, md)
 	t.obsrecv.EndMetricsOp(ctx, dataformat, numPoints, err)
 	return err
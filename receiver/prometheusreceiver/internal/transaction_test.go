@@ -7,6 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
 	"testing"
 	"time"
 
@@ -21,16 +24,23 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
 	conventions "go.opentelemetry.io/otel/semconv/v1.27.0"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	mdata "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadatatest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/prometheusreceivertest"
 )
 
 const (
@@ -389,6 +399,100 @@ func testTransactionAppendSummaryNoQuantile(t *testing.T, enableNativeHistograms
 	assert.Empty(t, sink.AllMetrics())
 }
 
+func TestTransactionAppendSummaryInvalidQuantile(t *testing.T) {
+	for _, enableNativeHistograms := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enableNativeHistograms=%v", enableNativeHistograms), func(t *testing.T) {
+			testTransactionAppendSummaryInvalidQuantile(t, enableNativeHistograms)
+		})
+	}
+}
+
+func testTransactionAppendSummaryInvalidQuantile(t *testing.T, enableNativeHistograms bool) {
+	sink := new(consumertest.MetricsSink)
+	receiverSettings := receivertest.NewNopSettings(receivertest.NopType)
+	core, observedLogs := observer.New(zap.InfoLevel)
+	receiverSettings.Logger = zap.New(core)
+	tr := newTransaction(
+		scrapeCtx,
+		&startTimeAdjuster{startTime: startTimestamp},
+		sink,
+		labels.EmptyLabels(),
+		receiverSettings,
+		nopObsRecv(t),
+		false,
+		enableNativeHistograms,
+	)
+
+	for _, quantile := range []string{"-0.1", "2.0"} {
+		badLabels := labels.FromStrings(
+			model.InstanceLabel, "0.0.0.0:8855",
+			model.JobLabel, "test",
+			model.MetricNameLabel, "summary_test",
+			model.QuantileLabel, quantile,
+		)
+		_, err := tr.Append(0, badLabels, 1917, 1.0)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, observedLogs.FilterMessage("failed to add datapoint").Len())
+	for _, entry := range observedLogs.FilterMessage("failed to add datapoint").All() {
+		assert.Contains(t, entry.ContextMap()["error"], errInvalidQuantile.Error())
+	}
+
+	assert.NoError(t, tr.Commit())
+	assert.Empty(t, sink.AllMetrics())
+}
+
+func TestTransactionAppendIncompatibleFamilyCollision(t *testing.T) {
+	for _, enableNativeHistograms := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enableNativeHistograms=%v", enableNativeHistograms), func(t *testing.T) {
+			testTransactionAppendIncompatibleFamilyCollision(t, enableNativeHistograms)
+		})
+	}
+}
+
+func testTransactionAppendIncompatibleFamilyCollision(t *testing.T, enableNativeHistograms bool) {
+	sink := new(consumertest.MetricsSink)
+	receiverSettings := receivertest.NewNopSettings(receivertest.NopType)
+	core, observedLogs := observer.New(zap.InfoLevel)
+	receiverSettings.Logger = zap.New(core)
+	tr := newTransaction(
+		scrapeCtx,
+		&startTimeAdjuster{startTime: startTimestamp},
+		sink,
+		labels.EmptyLabels(),
+		receiverSettings,
+		nopObsRecv(t),
+		false,
+		enableNativeHistograms,
+	)
+
+	// counter_test is a counter, counter_test_bucket has no metadata and
+	// normalizes to the counter family name.
+	_, err := tr.Append(0, labels.FromStrings(
+		model.InstanceLabel, "0.0.0.0:8855",
+		model.JobLabel, "test",
+		model.MetricNameLabel, "counter_test",
+	), 1917, 1.0)
+	require.NoError(t, err)
+	_, err = tr.Append(0, labels.FromStrings(
+		model.InstanceLabel, "0.0.0.0:8855",
+		model.JobLabel, "test",
+		model.MetricNameLabel, "counter_test_bucket",
+		model.BucketLabel, "0.5",
+	), 1917, 42.0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, observedLogs.FilterMessage("dropping datapoint whose name collides with an incompatible metric family").Len())
+
+	require.NoError(t, tr.Commit())
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "counter_test", metrics.At(0).Name())
+	require.Equal(t, 1, metrics.At(0).Sum().DataPoints().Len())
+	assert.Equal(t, 1.0, metrics.At(0).Sum().DataPoints().At(0).DoubleValue())
+}
+
 func TestTransactionAppendValidAndInvalid(t *testing.T) {
 	for _, enableNativeHistograms := range []bool{true, false} {
 		t.Run(fmt.Sprintf("enableNativeHistograms=%v", enableNativeHistograms), func(t *testing.T) {
@@ -479,6 +583,954 @@ func testTransactionAppendWithEmptyLabelArrayFallbackToTargetLabels(t *testing.T
 	assert.NoError(t, err)
 }
 
+func TestTransactionAppendCounterWithCreatedSeries(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &nopAdjuster{}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+
+	createdSeconds := float64(ts-5000) / 1000
+	_, err := tr.Append(0, labels.FromStrings(
+		model.InstanceLabel, "localhost:8080",
+		model.JobLabel, "test",
+		model.MetricNameLabel, "counter_test",
+		"foo", "bar",
+	), ts, 100)
+	require.NoError(t, err)
+	_, err = tr.Append(0, labels.FromStrings(
+		model.InstanceLabel, "localhost:8080",
+		model.JobLabel, "test",
+		model.MetricNameLabel, "counter_test_created",
+		"foo", "bar",
+	), ts, createdSeconds)
+	require.NoError(t, err)
+	require.NoError(t, tr.Commit())
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	dps := metrics.At(0).Sum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	assert.Equal(t, 100.0, dps.At(0).DoubleValue())
+	assert.Equal(t, timestampFromFloat64(createdSeconds), dps.At(0).StartTimestamp())
+	assert.Equal(t, tsNanos, dps.At(0).Timestamp())
+}
+
+func TestTransactionAlignTimestampsToScrapeStart(t *testing.T) {
+	for _, align := range []bool{true, false} {
+		t.Run(fmt.Sprintf("align=%v", align), func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &nopAdjuster{}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+			tr.opts.AlignTimestampsToScrapeStart = align
+
+			_, err := tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, "counter_test",
+			), ts+10, 1.0)
+			require.NoError(t, err)
+			_, err = tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, "gauge_test",
+			), ts+20, 2.0)
+			require.NoError(t, err)
+			_, err = tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, scrapeUpMetricName,
+			), ts, 1.0)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 3, metrics.Len())
+			for _, metric := range metrics.All() {
+				switch metric.Name() {
+				case "counter_test":
+					dp := metric.Sum().DataPoints().At(0)
+					assert.Equal(t, timestampFromMs(ts+10), dp.StartTimestamp())
+					if align {
+						assert.Equal(t, tsNanos, dp.Timestamp())
+					} else {
+						assert.Equal(t, timestampFromMs(ts+10), dp.Timestamp())
+					}
+				case "gauge_test":
+					dp := metric.Gauge().DataPoints().At(0)
+					if align {
+						assert.Equal(t, tsNanos, dp.Timestamp())
+					} else {
+						assert.Equal(t, timestampFromMs(ts+20), dp.Timestamp())
+					}
+				case scrapeUpMetricName:
+					assert.Equal(t, tsNanos, metric.Gauge().DataPoints().At(0).Timestamp())
+				default:
+					t.Errorf("unexpected metric %q", metric.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionExcludedLabels(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+	tr.opts.ExcludedLabels = []string{"pod_uid", model.BucketLabel, model.JobLabel}
+
+	for _, bucket := range []struct {
+		le    string
+		value float64
+	}{{"1", 1}, {"+Inf", 3}} {
+		_, err := tr.Append(0, labels.FromStrings(
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			model.MetricNameLabel, "hist_test_bucket",
+			model.BucketLabel, bucket.le,
+			"foo", "bar",
+			"pod_uid", "1234",
+		), ts, bucket.value)
+		require.NoError(t, err)
+	}
+	for name, value := range map[string]float64{"hist_test_sum": 5, "hist_test_count": 3} {
+		_, err := tr.Append(0, labels.FromStrings(
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			model.MetricNameLabel, name,
+			"foo", "bar",
+			"pod_uid", "1234",
+		), ts, value)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tr.Commit())
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	dps := metrics.At(0).Histogram().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	dp := dps.At(0)
+	assert.Equal(t, map[string]any{"foo": "bar"}, dp.Attributes().AsRaw())
+	assert.Equal(t, []float64{1}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 2}, dp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(3), dp.Count())
+}
+
+func TestTransactionInternalScrapeMetrics(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+
+	for metricName := range internalMetricMetadata {
+		_, err := tr.Append(0, labels.FromStrings(
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			model.MetricNameLabel, metricName,
+		), ts, 42)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tr.Commit())
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, len(internalMetricMetadata), metrics.Len())
+	for _, metric := range metrics.All() {
+		metadata, ok := internalMetricMetadata[metric.Name()]
+		require.True(t, ok, metric.Name())
+		assert.Equal(t, pmetric.MetricTypeGauge, metric.Type(), metric.Name())
+		assert.Equal(t, metadata.Help, metric.Description(), metric.Name())
+		require.Equal(t, 1, metric.Gauge().DataPoints().Len())
+		assert.Equal(t, 42.0, metric.Gauge().DataPoints().At(0).DoubleValue())
+	}
+
+	// scrape_series_added is emitted like any other gauge, e.g. to monitor the cardinality of targets
+	seriesAdded := pmetric.NewMetric()
+	for _, metric := range metrics.All() {
+		if metric.Name() == "scrape_series_added" {
+			seriesAdded = metric
+		}
+	}
+	require.Equal(t, pmetric.MetricTypeGauge, seriesAdded.Type())
+	assert.Equal(t, "The approximate number of new series in this scrape", seriesAdded.Description())
+}
+
+func TestTransactionMaxHistogramBuckets(t *testing.T) {
+	const numBounds = 1000
+	tests := []struct {
+		maxBuckets  int
+		wantBuckets int
+		wantMerged  int
+	}{
+		{maxBuckets: 0, wantBuckets: numBounds + 1},
+		{maxBuckets: numBounds + 1, wantBuckets: numBounds + 1},
+		{maxBuckets: 10, wantBuckets: 10, wantMerged: numBounds - 9},
+		{maxBuckets: 1, wantBuckets: 1, wantMerged: numBounds},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("maxHistogramBuckets=%d", tt.maxBuckets), func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+			tr.opts.MaxHistogramBuckets = tt.maxBuckets
+
+			appendSample := func(name string, value float64, extraLabels ...string) {
+				_, err := tr.Append(0, labels.FromStrings(append([]string{
+					model.InstanceLabel, "localhost:8080",
+					model.JobLabel, "test",
+					model.MetricNameLabel, name,
+				}, extraLabels...)...), ts, value)
+				require.NoError(t, err)
+			}
+			// each bucket holds a single observation, the +Inf bucket holds 5
+			for i := 1; i <= numBounds; i++ {
+				appendSample("hist_test_bucket", float64(i), model.BucketLabel, strconv.Itoa(i))
+			}
+			appendSample("hist_test_bucket", numBounds+5, model.BucketLabel, "+Inf")
+			appendSample("hist_test_count", numBounds+5)
+			appendSample("hist_test_sum", 1)
+			require.NoError(t, tr.Commit())
+			assert.Equal(t, tt.wantMerged, tr.mergedHistogramBuckets)
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			dp := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+			assert.Equal(t, uint64(numBounds+5), dp.Count())
+			require.Equal(t, tt.wantBuckets, dp.BucketCounts().Len())
+			require.Equal(t, tt.wantBuckets-1, dp.ExplicitBounds().Len())
+
+			var total uint64
+			for i, count := range dp.BucketCounts().AsRaw() {
+				total += count
+				if i < tt.wantBuckets-1 {
+					assert.Equal(t, uint64(1), count)
+					assert.Equal(t, float64(i+1), dp.ExplicitBounds().At(i))
+				}
+			}
+			assert.Equal(t, dp.Count(), total)
+			assert.Equal(t, uint64(5+tt.wantMerged), dp.BucketCounts().At(tt.wantBuckets-1))
+		})
+	}
+}
+
+func TestTransactionPromoteTargetLabels(t *testing.T) {
+	for _, promote := range []bool{false, true} {
+		t.Run(fmt.Sprintf("promoteTargetLabels=%v", promote), func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+			tr.opts.PromoteTargetLabels = promote
+
+			_, err := tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, "counter_test",
+			), ts, 1.0)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			attrs := mds[0].ResourceMetrics().At(0).Resource().Attributes()
+
+			serviceName, ok := attrs.Get(string(conventions.ServiceNameKey))
+			require.True(t, ok)
+			assert.Equal(t, "test", serviceName.Str())
+			serviceInstanceID, ok := attrs.Get(string(conventions.ServiceInstanceIDKey))
+			require.True(t, ok)
+			assert.Equal(t, "localhost:8080", serviceInstanceID.Str())
+
+			for name, want := range map[string]string{
+				model.JobLabel:      "test",
+				model.InstanceLabel: "localhost:8080",
+				"scheme":            "http",
+			} {
+				got, ok := attrs.Get(name)
+				assert.Equal(t, promote, ok, name)
+				if promote {
+					assert.Equal(t, want, got.Str(), name)
+				}
+			}
+			// The test target has no metrics path label.
+			_, ok = attrs.Get("metrics_path")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestTransactionTargetLabelAttributes(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("targetLabelAttributes=%v", enabled), func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+			tr.opts.TargetLabelAttributes = enabled
+
+			appendSample := func(name string, value float64, extraLabels ...string) {
+				_, err := tr.Append(0, labels.FromStrings(append([]string{
+					model.InstanceLabel, "localhost:8080",
+					model.JobLabel, "test",
+					model.SchemeLabel, "https",
+					model.MetricNameLabel, name,
+					"foo", "bar",
+				}, extraLabels...)...), ts, value)
+				require.NoError(t, err)
+			}
+			appendSample("counter_test", 1)
+			appendSample("hist_test_bucket", 1, model.BucketLabel, "1")
+			appendSample("hist_test_bucket", 2, model.BucketLabel, "+Inf")
+			appendSample("hist_test_count", 2)
+			appendSample("hist_test_sum", 3)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 2, metrics.Len())
+
+			want := map[string]any{"foo": "bar"}
+			if enabled {
+				want["target.job"] = "test"
+				want["target.instance"] = "localhost:8080"
+				want["target.scheme"] = "https"
+			}
+			for _, metric := range metrics.All() {
+				switch metric.Name() {
+				case "counter_test":
+					assert.Equal(t, want, metric.Sum().DataPoints().At(0).Attributes().AsRaw())
+				case "hist_test":
+					assert.Equal(t, want, metric.Histogram().DataPoints().At(0).Attributes().AsRaw())
+				default:
+					t.Errorf("unexpected metric %q", metric.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionDisableMetricFamilyNormalization(t *testing.T) {
+	tests := []struct {
+		disable bool
+		want    map[string]pmetric.MetricType
+	}{
+		{
+			disable: false,
+			want:    map[string]pmetric.MetricType{"counter_test": pmetric.MetricTypeSum},
+		},
+		{
+			disable: true,
+			want: map[string]pmetric.MetricType{
+				"counter_test":       pmetric.MetricTypeSum,
+				"counter_test_total": pmetric.MetricTypeGauge,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("disableMetricFamilyNormalization=%v", tt.disable), func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+			tr.opts.DisableMetricFamilyNormalization = tt.disable
+
+			for _, name := range []string{"counter_test", "counter_test_total"} {
+				_, err := tr.Append(0, labels.FromStrings(
+					model.InstanceLabel, "localhost:8080",
+					model.JobLabel, "test",
+					model.MetricNameLabel, name,
+					"series", name,
+				), ts, 1.0)
+				require.NoError(t, err)
+			}
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			got := map[string]pmetric.MetricType{}
+			metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			for i := 0; i < metrics.Len(); i++ {
+				got[metrics.At(i).Name()] = metrics.At(i).Type()
+			}
+			assert.Equal(t, tt.want, got)
+			if !tt.disable {
+				assert.Equal(t, 2, metrics.At(0).Sum().DataPoints().Len())
+			}
+		})
+	}
+}
+
+func TestTransactionNonFiniteValues(t *testing.T) {
+	tests := []struct {
+		name string
+		val  float64
+		mode string
+		want float64
+	}{
+		{name: "NaN drop", val: math.NaN(), mode: NonFiniteValuesDrop},
+		{name: "+Inf drop", val: math.Inf(1), mode: NonFiniteValuesDrop},
+		{name: "NaN clamp", val: math.NaN(), mode: NonFiniteValuesClamp, want: 0},
+		{name: "+Inf clamp", val: math.Inf(1), mode: NonFiniteValuesClamp, want: math.MaxFloat64},
+		{name: "-Inf clamp", val: math.Inf(-1), mode: NonFiniteValuesClamp, want: -math.MaxFloat64},
+		{name: "+Inf keep", val: math.Inf(1), mode: NonFiniteValuesKeep, want: math.Inf(1)},
+	}
+	for _, metricName := range []string{"counter_test", "gauge_test"} {
+		for _, tt := range tests {
+			t.Run(metricName+" "+tt.name, func(t *testing.T) {
+				sink := new(consumertest.MetricsSink)
+				tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+				tr.opts.NonFiniteValues = tt.mode
+
+				_, err := tr.Append(0, labels.FromStrings(
+					model.InstanceLabel, "localhost:8080",
+					model.JobLabel, "test",
+					model.MetricNameLabel, metricName,
+				), ts, tt.val)
+				require.NoError(t, err)
+
+				if tt.mode == NonFiniteValuesDrop {
+					assert.Equal(t, 1, tr.droppedTimeseries[droppedReasonNonFiniteValue])
+					require.NoError(t, tr.Commit())
+					assert.Zero(t, sink.DataPointCount())
+					return
+				}
+				assert.Zero(t, tr.droppedTimeseries[droppedReasonNonFiniteValue])
+				require.NoError(t, tr.Commit())
+
+				mds := sink.AllMetrics()
+				require.Len(t, mds, 1)
+				metric := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+				var dps pmetric.NumberDataPointSlice
+				if metric.Type() == pmetric.MetricTypeSum {
+					dps = metric.Sum().DataPoints()
+				} else {
+					dps = metric.Gauge().DataPoints()
+				}
+				require.Equal(t, 1, dps.Len())
+				assert.Equal(t, tt.want, dps.At(0).DoubleValue())
+			})
+		}
+	}
+}
+
+func TestTransactionMetricNameValidation(t *testing.T) {
+	tests := []struct {
+		metricName string
+		mode       string
+		valid      bool
+	}{
+		{metricName: "http_requests", mode: MetricNameValidationLegacy, valid: true},
+		{metricName: "node:cpu_seconds:rate5m", mode: MetricNameValidationLegacy, valid: true},
+		{metricName: "http.requests", mode: MetricNameValidationLegacy, valid: false},
+		{metricName: "http-requests", mode: MetricNameValidationLegacy, valid: false},
+		{metricName: "1st_metric", mode: MetricNameValidationLegacy, valid: false},
+		{metricName: "http.requests", mode: MetricNameValidationUTF8, valid: true},
+		{metricName: "http_requests_\xff", mode: MetricNameValidationUTF8, valid: false},
+		{metricName: "http-requests", mode: MetricNameValidationNone, valid: true},
+		{metricName: "http-requests", mode: "", valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %q", tt.mode, tt.metricName), func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, true)
+			tr.opts.MetricNameValidation = tt.mode
+
+			_, err := tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, tt.metricName,
+			), ts, 1)
+			require.NoError(t, err)
+			_, err = tr.AppendHistogram(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, tt.metricName+"_hist",
+			), ts, tsdbutil.GenerateTestHistogram(1), nil)
+			require.NoError(t, err)
+			err = tr.Commit()
+
+			if tt.valid {
+				require.NoError(t, err)
+				assert.Zero(t, tr.droppedTimeseries[droppedReasonInvalidMetricName])
+				mds := sink.AllMetrics()
+				require.Len(t, mds, 1)
+				var names []string
+				for _, metric := range mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+					names = append(names, metric.Name())
+				}
+				assert.Contains(t, names, tt.metricName)
+			} else {
+				assert.ErrorIs(t, err, errNoDataToBuild)
+				assert.Equal(t, 2, tr.droppedTimeseries[droppedReasonInvalidMetricName])
+				assert.Zero(t, sink.DataPointCount())
+			}
+		})
+	}
+}
+
+func TestTransactionHistogramRepresentations(t *testing.T) {
+	tests := []struct {
+		mode string
+		want []pmetric.MetricType
+	}{
+		{mode: "", want: []pmetric.MetricType{pmetric.MetricTypeHistogram, pmetric.MetricTypeExponentialHistogram}},
+		{mode: HistogramRepresentationsBoth, want: []pmetric.MetricType{pmetric.MetricTypeHistogram, pmetric.MetricTypeExponentialHistogram}},
+		{mode: HistogramRepresentationsNative, want: []pmetric.MetricType{pmetric.MetricTypeExponentialHistogram}},
+		{mode: HistogramRepresentationsClassic, want: []pmetric.MetricType{pmetric.MetricTypeHistogram}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, true)
+			tr.opts.HistogramRepresentations = tt.mode
+
+			appendSample := func(name string, value float64, extraLabels ...string) {
+				_, err := tr.Append(0, labels.FromStrings(append([]string{
+					model.InstanceLabel, "localhost:8080",
+					model.JobLabel, "test",
+					model.MetricNameLabel, name,
+				}, extraLabels...)...), ts, value)
+				require.NoError(t, err)
+			}
+			appendSample("counter_test", 1)
+			appendSample("hist_test_bucket", 1, model.BucketLabel, "1")
+			appendSample("hist_test_bucket", 2, model.BucketLabel, "+Inf")
+			appendSample("hist_test_count", 2)
+			appendSample("hist_test_sum", 3)
+			_, err := tr.AppendHistogram(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, "hist_test",
+			), ts, tsdbutil.GenerateTestHistogram(1), nil)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			var got []pmetric.MetricType
+			for _, metric := range mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+				if metric.Name() == "hist_test" {
+					got = append(got, metric.Type())
+				} else {
+					assert.Equal(t, "counter_test", metric.Name())
+				}
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestTransactionMetricNameRules(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+	tr.opts.MetricNameRules = []MetricNameRule{
+		{Regex: regexp.MustCompile("^counter_test$"), TargetName: "renamed_counter"},
+		{Regex: regexp.MustCompile("^hist_test(.*)$"), TargetName: "renamed_hist${1}"},
+		{Regex: regexp.MustCompile("^gauge_.*$"), Drop: true},
+		{Regex: regexp.MustCompile("^gauge_test$"), TargetName: "never_applied"},
+	}
+
+	appendSample := func(name string, value float64, extraLabels ...string) {
+		_, err := tr.Append(0, labels.FromStrings(append([]string{
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			model.MetricNameLabel, name,
+		}, extraLabels...)...), ts, value)
+		require.NoError(t, err)
+	}
+	appendSample("counter_test", 1)
+	appendSample("gauge_test", 2)
+	appendSample("hist_test_bucket", 1, model.BucketLabel, "1")
+	appendSample("hist_test_bucket", 2, model.BucketLabel, "+Inf")
+	appendSample("hist_test_count", 2)
+	appendSample("hist_test_sum", 3)
+	require.NoError(t, tr.Commit())
+
+	assert.Equal(t, 1, tr.droppedTimeseries[droppedReasonMetricNameRule])
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	got := map[string]pmetric.MetricType{}
+	for _, metric := range mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+		got[metric.Name()] = metric.Type()
+	}
+	assert.Equal(t, map[string]pmetric.MetricType{
+		"renamed_counter": pmetric.MetricTypeSum,
+		"renamed_hist":    pmetric.MetricTypeHistogram,
+	}, got)
+}
+
+func TestTransactionFutureTimestamps(t *testing.T) {
+	tests := []struct {
+		mode        string
+		wantDropped bool
+		wantClamped bool
+	}{
+		{mode: ""},
+		{mode: FutureTimestampsKeep},
+		{mode: FutureTimestampsDrop, wantDropped: true},
+		{mode: FutureTimestampsClamp, wantClamped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, true)
+			tr.opts.FutureTimestamps = tt.mode
+			tr.opts.FutureTimestampTolerance = time.Minute
+
+			now := time.Now()
+			future := now.Add(time.Hour).UnixMilli()
+			withinTolerance := now.Add(30 * time.Second).UnixMilli()
+			_, err := tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, "counter_test",
+			), future, 1)
+			require.NoError(t, err)
+			_, err = tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, "gauge_test",
+			), withinTolerance, 1)
+			require.NoError(t, err)
+			_, err = tr.AppendHistogram(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, "hist_test",
+			), future, tsdbutil.GenerateTestHistogram(1), nil)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			if tt.wantDropped {
+				assert.Equal(t, 2, tr.droppedTimeseries[droppedReasonFutureTimestamp])
+			} else {
+				assert.Zero(t, tr.droppedTimeseries[droppedReasonFutureTimestamp])
+			}
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			got := map[string]pcommon.Timestamp{}
+			for _, metric := range mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().All() {
+				switch metric.Type() {
+				case pmetric.MetricTypeSum:
+					got[metric.Name()] = metric.Sum().DataPoints().At(0).Timestamp()
+				case pmetric.MetricTypeGauge:
+					got[metric.Name()] = metric.Gauge().DataPoints().At(0).Timestamp()
+				case pmetric.MetricTypeExponentialHistogram:
+					got[metric.Name()] = metric.ExponentialHistogram().DataPoints().At(0).Timestamp()
+				}
+			}
+			assert.Equal(t, timestampFromMs(withinTolerance), got["gauge_test"])
+			switch {
+			case tt.wantDropped:
+				assert.NotContains(t, got, "counter_test")
+				assert.NotContains(t, got, "hist_test")
+			case tt.wantClamped:
+				for _, name := range []string{"counter_test", "hist_test"} {
+					require.Contains(t, got, name)
+					assert.GreaterOrEqual(t, got[name], timestampFromMs(now.UnixMilli()), name)
+					assert.Less(t, got[name], timestampFromMs(withinTolerance), name)
+				}
+			default:
+				assert.Equal(t, timestampFromMs(future), got["counter_test"])
+				assert.Equal(t, timestampFromMs(future), got["hist_test"])
+			}
+		})
+	}
+}
+
+func TestTransactionSummaryAsGauges(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+	tr.opts.SummaryAsGauges = true
+
+	appendSample := func(name string, value float64, extraLabels ...string) {
+		_, err := tr.Append(0, labels.FromStrings(append([]string{
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			model.MetricNameLabel, name,
+			"foo", "bar",
+		}, extraLabels...)...), ts, value)
+		require.NoError(t, err)
+	}
+	appendSample("counter_test", 1)
+	appendSample("summary_test", 5, model.QuantileLabel, "0.5")
+	appendSample("summary_test", 9, model.QuantileLabel, "0.99")
+	appendSample("summary_test_count", 10)
+	appendSample("summary_test_sum", 60)
+	require.NoError(t, tr.Commit())
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	got := map[string]pmetric.Metric{}
+	for _, metric := range metrics.All() {
+		got[metric.Name()] = metric
+	}
+	require.Len(t, got, 4)
+	assert.Equal(t, pmetric.MetricTypeSum, got["counter_test"].Type())
+
+	pointValues := func(metric pmetric.Metric) map[string]float64 {
+		require.Equal(t, pmetric.MetricTypeGauge, metric.Type(), metric.Name())
+		values := map[string]float64{}
+		for _, dp := range metric.Gauge().DataPoints().All() {
+			assert.Equal(t, timestampFromMs(ts), dp.Timestamp())
+			foo, _ := dp.Attributes().Get("foo")
+			assert.Equal(t, "bar", foo.Str())
+			quantile, _ := dp.Attributes().Get(model.QuantileLabel)
+			values[quantile.Str()] = dp.DoubleValue()
+		}
+		return values
+	}
+	assert.Equal(t, map[string]float64{"0.5": 5, "0.99": 9}, pointValues(got["summary_test"]))
+	assert.Equal(t, map[string]float64{"": 10}, pointValues(got["summary_test_count"]))
+	assert.Equal(t, map[string]float64{"": 60}, pointValues(got["summary_test_sum"]))
+}
+
+func TestTransactionExemplarLimits(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+	tr.opts.MaxExemplarsPerDataPoint = 2
+	tr.opts.MaxExemplarLabels = 1
+
+	ls := labels.FromStrings(
+		model.InstanceLabel, "localhost:8080",
+		model.JobLabel, "test",
+		model.MetricNameLabel, "counter_test",
+		"foo", "bar",
+	)
+	_, err := tr.Append(0, ls, ts, 1)
+	require.NoError(t, err)
+	for i := range 4 {
+		_, err = tr.AppendExemplar(0, ls, exemplar.Exemplar{
+			Labels: labels.FromStrings(
+				"a", "1",
+				"b", "2",
+				"span_id", "0102030405060708",
+				"trace_id", "0102030405060708090a0b0c0d0e0f10",
+			),
+			Value: float64(i),
+			Ts:    ts,
+			HasTs: true,
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, tr.Commit())
+	assert.Equal(t, 2, tr.droppedExemplars)
+	assert.Equal(t, 4, tr.droppedExemplarLabels)
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	dp := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+	require.Equal(t, 2, dp.Exemplars().Len())
+	for i, e := range dp.Exemplars().All() {
+		assert.Equal(t, float64(i), e.DoubleValue())
+		assert.Equal(t, map[string]any{"a": "1"}, e.FilteredAttributes().AsRaw())
+		assert.Equal(t, pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, e.TraceID())
+		assert.Equal(t, pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8}, e.SpanID())
+	}
+}
+
+func TestTransactionMaxLabelValueLength(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+	tr.opts.MaxLabelValueLength = 5
+
+	appendSample := func(extraLabels ...string) {
+		_, err := tr.Append(0, labels.FromStrings(append([]string{
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			model.MetricNameLabel, "gauge_test",
+		}, extraLabels...)...), ts, 1)
+		require.NoError(t, err)
+	}
+	appendSample("long", "abcdefgh", "short", "abc", "unicode", "äöüßéèê")
+	appendSample("long", "abcde", "short", "abc")
+	require.NoError(t, tr.Commit())
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	dps := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, map[string]any{
+		"long":                   "abcde",
+		"short":                  "abc",
+		"unicode":                "äöüßé",
+		TruncatedLabelsAttribute: "long,unicode",
+	}, dps.At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]any{
+		"long":  "abcde",
+		"short": "abc",
+	}, dps.At(1).Attributes().AsRaw())
+}
+
+func TestTransactionDroppedTimeseriesByReason(t *testing.T) {
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, consumertest.NewNop(), labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+
+	appendSample := func(metricName string, atMs int64, extraLabels ...string) {
+		lbls := append([]string{
+			model.InstanceLabel, "0.0.0.0:8855",
+			model.JobLabel, "test",
+			model.MetricNameLabel, metricName,
+		}, extraLabels...)
+		_, _ = tr.Append(0, labels.FromStrings(lbls...), atMs, 1.0)
+	}
+
+	// no metric name
+	appendSample("", 1917)
+	// duplicate labels
+	appendSample("counter_test", 1917, "a", "1", "a", "2")
+	// invalid boundaries
+	appendSample("hist_test_bucket", 1917)
+	appendSample("summary_test", 1917, model.QuantileLabel, "2")
+	// incompatible family
+	appendSample("counter_test", 1917)
+	appendSample("counter_test_bucket", 1917, model.BucketLabel, "1")
+	// inconsistent timestamps for the same series
+	appendSample("counter_test", 1918)
+
+	assert.Equal(t, map[droppedReason]int{
+		droppedReasonNoMetricName:       1,
+		droppedReasonDuplicateLabels:    1,
+		droppedReasonInvalidBoundary:    2,
+		droppedReasonIncompatibleFamily: 1,
+		droppedReasonInvalidSample:      1,
+	}, tr.droppedTimeseries)
+}
+
+func TestTransactionDroppedTimeseriesTelemetry(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) }) //nolint:usetesting
+	telemetryBuilder, err := mdata.NewTelemetryBuilder(tel.NewTelemetrySettings())
+	require.NoError(t, err)
+
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, consumertest.NewNop(), labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+	tr.telemetryBuilder = telemetryBuilder
+
+	appendSample := func(metricName string, extraLabels ...string) {
+		lbls := append([]string{
+			model.InstanceLabel, "0.0.0.0:8855",
+			model.JobLabel, "test",
+			model.MetricNameLabel, metricName,
+		}, extraLabels...)
+		_, _ = tr.Append(0, labels.FromStrings(lbls...), ts, 1.0)
+	}
+	appendSample("counter_test")
+	appendSample("counter_test", "a", "1", "a", "2")
+	appendSample("hist_test_bucket")
+	appendSample("summary_test", model.QuantileLabel, "2")
+	require.NoError(t, tr.Commit())
+
+	metadatatest.AssertEqualPrometheusreceiverDroppedTimeseries(t, tel,
+		[]metricdata.DataPoint[int64]{
+			{
+				Value:      1,
+				Attributes: attribute.NewSet(attribute.String("reason", string(droppedReasonDuplicateLabels))),
+			},
+			{
+				Value:      2,
+				Attributes: attribute.NewSet(attribute.String("reason", string(droppedReasonInvalidBoundary))),
+			},
+		},
+		metricdatatest.IgnoreTimestamp())
+}
+
+func TestTransactionDroppedNonMonotonicHistogramBuckets(t *testing.T) {
+	tel := componenttest.NewTelemetry()
+	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) }) //nolint:usetesting
+	telemetryBuilder, err := mdata.NewTelemetryBuilder(tel.NewTelemetrySettings())
+	require.NoError(t, err)
+
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(scrapeCtx, &startTimeAdjuster{startTime: startTimestamp}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+	tr.telemetryBuilder = telemetryBuilder
+
+	appendSample := func(metricName string, value float64, extraLabels ...string) {
+		_, err := tr.Append(0, labels.FromStrings(append([]string{
+			model.InstanceLabel, "0.0.0.0:8855",
+			model.JobLabel, "test",
+			model.MetricNameLabel, metricName,
+		}, extraLabels...)...), ts, value)
+		require.NoError(t, err)
+	}
+	appendSample("counter_test", 1)
+	// the cumulative count of the 1 bucket is lower than the one of the 0.1 bucket
+	appendSample("hist_test_bucket", 5, model.BucketLabel, "0.1")
+	appendSample("hist_test_bucket", 3, model.BucketLabel, "1")
+	appendSample("hist_test_bucket", 10, model.BucketLabel, "+Inf")
+	appendSample("hist_test_count", 10)
+	appendSample("hist_test_sum", 7.5)
+	require.NoError(t, tr.Commit())
+
+	assert.Equal(t, map[droppedReason]int{droppedReasonNonMonotonicBuckets: 1}, tr.droppedTimeseries)
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "counter_test", metrics.At(0).Name())
+
+	metadatatest.AssertEqualPrometheusreceiverDroppedTimeseries(t, tel,
+		[]metricdata.DataPoint[int64]{
+			{
+				Value:      1,
+				Attributes: attribute.NewSet(attribute.String("reason", string(droppedReasonNonMonotonicBuckets))),
+			},
+		},
+		metricdatatest.IgnoreTimestamp())
+}
+
+func TestTransactionDetectMetricTypeChanges(t *testing.T) {
+	store := prometheusreceivertest.NewMetadataStore()
+	store.SetType("flip_test", model.MetricTypeGauge)
+	store.SetType("steady_test", model.MetricTypeGauge)
+	ctx := scrape.ContextWithMetricMetadataStore(scrape.ContextWithTarget(t.Context(), target), store)
+	tracker := newMetricTypeTracker(time.Hour)
+
+	scrapeMetrics := func(atMs int64) (*transaction, *consumertest.MetricsSink) {
+		sink := new(consumertest.MetricsSink)
+		tr := newTransaction(ctx, &nopAdjuster{}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+		tr.typeTracker = tracker
+		for _, metricName := range []string{"flip_test", "steady_test"} {
+			_, err := tr.Append(0, labels.FromStrings(
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				model.MetricNameLabel, metricName,
+			), atMs, 1.0)
+			require.NoError(t, err)
+		}
+		require.NoError(t, tr.Commit())
+		return tr, sink
+	}
+
+	tr, sink := scrapeMetrics(ts)
+	assert.Empty(t, tr.droppedTimeseries)
+	assert.Equal(t, 2, sink.DataPointCount())
+
+	// the type of flip_test changes between scrapes, its samples are dropped
+	store.SetType("flip_test", model.MetricTypeCounter)
+	tr, sink = scrapeMetrics(ts + interval)
+	assert.Equal(t, map[droppedReason]int{droppedReasonTypeChanged: 1}, tr.droppedTimeseries)
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "steady_test", metrics.At(0).Name())
+	assert.Equal(t, pmetric.MetricTypeGauge, metrics.At(0).Type())
+}
+
+func TestTransactionDetectMetricTypeChangesDisabled(t *testing.T) {
+	store := prometheusreceivertest.NewMetadataStore()
+	store.SetType("flip_test", model.MetricTypeGauge)
+	ctx := scrape.ContextWithMetricMetadataStore(scrape.ContextWithTarget(t.Context(), target), store)
+
+	for i, metricType := range []model.MetricType{model.MetricTypeGauge, model.MetricTypeCounter} {
+		store.SetType("flip_test", metricType)
+		sink := new(consumertest.MetricsSink)
+		tr := newTransaction(ctx, &nopAdjuster{}, sink, labels.EmptyLabels(), receivertest.NewNopSettings(receivertest.NopType), nopObsRecv(t), false, false)
+		_, err := tr.Append(0, labels.FromStrings(
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			model.MetricNameLabel, "flip_test",
+		), ts+int64(i)*interval, 1.0)
+		require.NoError(t, err)
+		require.NoError(t, tr.Commit())
+		assert.Equal(t, 1, sink.DataPointCount())
+	}
+}
+
 func TestAppendExemplarWithNoMetricName(t *testing.T) {
 	for _, enableNativeHistograms := range []bool{true, false} {
 		t.Run(fmt.Sprintf("enableNativeHistograms=%v", enableNativeHistograms), func(t *testing.T) {
@@ -1459,6 +2511,45 @@ func TestMetricBuilderHistogram(t *testing.T) {
 				return []pmetric.Metrics{md0}
 			},
 		},
+		{
+			name: "exemplars placed by bucket",
+			inputs: []*testScrapedPage{
+				{
+					pts: []*testDataPoint{
+						createDataPoint("hist_test_bucket", 10, []exemplar.Exemplar{{Value: 7, Ts: 1663113420863}}, "foo", "bar", "le", "+inf"),
+						createDataPoint("hist_test_bucket", 2, []exemplar.Exemplar{{Value: 0.8, Ts: 1663113420863}}, "foo", "bar", "le", "1"),
+						createDataPoint("hist_test_bucket", 1, []exemplar.Exemplar{{Value: 0.3, Ts: 1663113420863}}, "foo", "bar", "le", "0.5"),
+						createDataPoint("hist_test_sum", 99, nil, "foo", "bar"),
+						createDataPoint("hist_test_count", 10, nil, "foo", "bar"),
+					},
+				},
+			},
+			wants: func() []pmetric.Metrics {
+				md0 := pmetric.NewMetrics()
+				mL0 := md0.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+				m0 := mL0.AppendEmpty()
+				m0.SetName("hist_test")
+				m0.Metadata().PutStr("prometheus.type", "histogram")
+				hist0 := m0.SetEmptyHistogram()
+				hist0.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				pt0 := hist0.DataPoints().AppendEmpty()
+				pt0.SetCount(10)
+				pt0.SetSum(99)
+				pt0.ExplicitBounds().FromRaw([]float64{0.5, 1})
+				pt0.BucketCounts().FromRaw([]uint64{1, 1, 8})
+				pt0.SetTimestamp(tsNanos)
+				pt0.SetStartTimestamp(startTimestamp)
+				pt0.Attributes().PutStr("foo", "bar")
+
+				for _, v := range []float64{0.3, 0.8, 7} {
+					e := pt0.Exemplars().AppendEmpty()
+					e.SetTimestamp(timestampFromMs(1663113420863))
+					e.SetDoubleValue(v)
+				}
+
+				return []pmetric.Metrics{md0}
+			},
+		},
 		{
 			name: "multi-groups",
 			inputs: []*testScrapedPage{
@@ -5,6 +5,8 @@ package internal // import "github.com/open-telemetry/opentelemetry-collector-co
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -37,6 +39,7 @@ var (
 	errNoBoundaryLabel    = errors.New("given metricType has no 'le' or 'quantile' label")
 	errEmptyQuantileLabel = errors.New("'quantile' label on summary metric is missing or empty")
 	errEmptyLeLabel       = errors.New("'le' label on histogram metric is missing or empty")
+	errInvalidQuantile    = errors.New("'quantile' label on summary metric must be within [0, 1]")
 	errMetricNameNotFound = errors.New("metricName not found from labels")
 	errTransactionAborted = errors.New("transaction aborted")
 	errNoJobInstance      = errors.New("job or instance cannot be found from labels")
@@ -49,11 +52,111 @@ var (
 	notUsefulLabelsSummary   = sortString(append(notUsefulLabelsOther, model.QuantileLabel))
 )
 
+// droppedReason categorizes why a sample was dropped while building the metrics of a scrape.
+type droppedReason string
+
+const (
+	droppedReasonNoMetricName        droppedReason = "no_metric_name"
+	droppedReasonDuplicateLabels     droppedReason = "duplicate_labels"
+	droppedReasonInvalidBoundary     droppedReason = "invalid_boundary"
+	droppedReasonIncompatibleFamily  droppedReason = "incompatible_family"
+	droppedReasonInvalidSample       droppedReason = "invalid_sample"
+	droppedReasonNonFiniteValue      droppedReason = "non_finite_value"
+	droppedReasonTypeChanged         droppedReason = "type_changed"
+	droppedReasonInvalidMetricName   droppedReason = "invalid_metric_name"
+	droppedReasonMetricNameRule      droppedReason = "metric_name_rule"
+	droppedReasonFutureTimestamp     droppedReason = "future_timestamp"
+	droppedReasonNonMonotonicBuckets droppedReason = "non_monotonic_buckets"
+)
+
+// Handling of non-finite counter and gauge values, see TransactionOptions.NonFiniteValues.
+const (
+	NonFiniteValuesKeep  = "keep"
+	NonFiniteValuesDrop  = "drop"
+	NonFiniteValuesClamp = "clamp"
+)
+
+// Handling of samples with future timestamps, see TransactionOptions.FutureTimestamps.
+const (
+	FutureTimestampsKeep  = "keep"
+	FutureTimestampsDrop  = "drop"
+	FutureTimestampsClamp = "clamp"
+)
+
+// Naming rules metric names are validated against, see TransactionOptions.MetricNameValidation.
+const (
+	MetricNameValidationNone   = "none"
+	MetricNameValidationLegacy = "legacy"
+	MetricNameValidationUTF8   = "utf8"
+)
+
+// Representations kept of histograms exposed with both classic and native buckets, see
+// TransactionOptions.HistogramRepresentations.
+const (
+	HistogramRepresentationsBoth    = "both"
+	HistogramRepresentationsNative  = "native"
+	HistogramRepresentationsClassic = "classic"
+)
+
+// clampNonFinite replaces NaN with 0 and infinite values with the largest finite value of the same sign.
+func clampNonFinite(val float64) float64 {
+	switch {
+	case math.IsNaN(val):
+		return 0
+	case math.IsInf(val, 1):
+		return math.MaxFloat64
+	case math.IsInf(val, -1):
+		return -math.MaxFloat64
+	default:
+		return val
+	}
+}
+
+// droppedReasonForError maps an error returned while adding a sample to its metric family
+// to the reason the sample was dropped.
+func droppedReasonForError(err error) droppedReason {
+	var numErr *strconv.NumError
+	switch {
+	case errors.Is(err, errEmptyLeLabel), errors.Is(err, errEmptyQuantileLabel),
+		errors.Is(err, errInvalidQuantile), errors.Is(err, errNoBoundaryLabel), errors.As(err, &numErr):
+		return droppedReasonInvalidBoundary
+	default:
+		return droppedReasonInvalidSample
+	}
+}
+
 func sortString(strs []string) []string {
 	sort.Strings(strs)
 	return strs
 }
 
+// TruncatedLabelsAttribute is the data point attribute listing the names of the labels whose values
+// were truncated because of TransactionOptions.MaxLabelValueLength. It can't collide with the names
+// of scraped labels, as Prometheus label names must not contain dots.
+const TruncatedLabelsAttribute = "otel.truncated_labels"
+
+// truncateString cuts s to at most maxLength characters.
+func truncateString(s string, maxLength int) string {
+	for i := range s {
+		if maxLength == 0 {
+			return s[:i]
+		}
+		maxLength--
+	}
+	return s
+}
+
+// isRequiredLabel returns true for labels which are needed to build the metrics
+// and therefore must never be removed from a sample.
+func isRequiredLabel(name string) bool {
+	switch name {
+	case model.MetricNameLabel, model.JobLabel, model.InstanceLabel, model.BucketLabel, model.QuantileLabel:
+		return true
+	default:
+		return false
+	}
+}
+
 func getSortedNotUsefulLabels(mType pmetric.MetricType) []string {
 	switch mType {
 	case pmetric.MetricTypeHistogram:
@@ -92,7 +195,14 @@ func getBoundary(metricType pmetric.MetricType, labels labels.Labels) (float64,
 		return 0, errNoBoundaryLabel
 	}
 
-	return strconv.ParseFloat(val, 64)
+	boundary, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, err
+	}
+	if metricType == pmetric.MetricTypeSummary && (boundary < 0 || boundary > 1 || math.IsNaN(boundary)) {
+		return 0, fmt.Errorf("%w: %q", errInvalidQuantile, val)
+	}
+	return boundary, nil
 }
 
 // convToMetricType returns the data type and if it is monotonic
@@ -150,8 +150,20 @@ func TestGetBoundary(t *testing.T) {
 		{
 			name:      "summary with quantile label",
 			mtype:     pmetric.MetricTypeSummary,
-			labels:    labels.FromStrings(model.QuantileLabel, "92.88"),
-			wantValue: 92.88,
+			labels:    labels.FromStrings(model.QuantileLabel, "0.9288"),
+			wantValue: 0.9288,
+		},
+		{
+			name:    "summary with quantile label greater than 1",
+			mtype:   pmetric.MetricTypeSummary,
+			labels:  labels.FromStrings(model.QuantileLabel, "92.88"),
+			wantErr: errInvalidQuantile,
+		},
+		{
+			name:    "summary with negative quantile label",
+			mtype:   pmetric.MetricTypeSummary,
+			labels:  labels.FromStrings(model.QuantileLabel, "-0.5"),
+			wantErr: errInvalidQuantile,
 		},
 		{
 			name:    "gauge histogram mismatched with bucket label",
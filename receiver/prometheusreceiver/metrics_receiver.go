@@ -169,6 +169,11 @@ func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger *slog.L
 		}
 	}
 
+	metricNameRules, err := compileMetricNameRules(r.cfg.MetricNameRules)
+	if err != nil {
+		return err
+	}
+
 	store, err := internal.NewAppendable(
 		r.consumer,
 		r.settings,
@@ -179,6 +184,28 @@ func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger *slog.L
 		enableNativeHistogramsGate.IsEnabled(),
 		r.cfg.PrometheusConfig.GlobalConfig.ExternalLabels,
 		r.cfg.TrimMetricSuffixes,
+		internal.TransactionOptions{
+			AlignTimestampsToScrapeStart:     r.cfg.AlignTimestampsToScrapeStart,
+			ExcludedLabels:                   r.cfg.ExcludedLabels,
+			CumulativeToDelta:                r.cfg.CumulativeToDelta,
+			PromoteTargetLabels:              r.cfg.PromoteTargetLabels,
+			DisableMetricFamilyNormalization: r.cfg.DisableMetricFamilyNormalization,
+			NonFiniteValues:                  r.cfg.NonFiniteValues,
+			TrimTypeSuffixes:                 r.cfg.TrimMetricTypeSuffixes,
+			TrimUnitSuffixes:                 r.cfg.TrimMetricUnitSuffixes,
+			DetectMetricTypeChanges:          r.cfg.DetectMetricTypeChanges,
+			MaxHistogramBuckets:              r.cfg.MaxHistogramBuckets,
+			TargetLabelAttributes:            r.cfg.TargetLabelAttributes,
+			MetricNameValidation:             r.cfg.MetricNameValidation,
+			HistogramRepresentations:         r.cfg.HistogramRepresentations,
+			MetricNameRules:                  metricNameRules,
+			FutureTimestamps:                 r.cfg.FutureTimestamps,
+			FutureTimestampTolerance:         r.cfg.FutureTimestampTolerance,
+			SummaryAsGauges:                  r.cfg.SummaryAsGauges,
+			MaxExemplarsPerDataPoint:         r.cfg.MaxExemplarsPerDataPoint,
+			MaxExemplarLabels:                r.cfg.MaxExemplarLabels,
+			MaxLabelValueLength:              r.cfg.MaxLabelValueLength,
+		},
 	)
 	if err != nil {
 		return err
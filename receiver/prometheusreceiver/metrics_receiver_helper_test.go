@@ -296,7 +296,7 @@ func isFirstFailedScrape(metrics []pmetric.Metric, normalizedNames bool) bool {
 	}
 
 	for _, m := range metrics {
-		if isDefaultMetrics(m, normalizedNames) || isExtraScrapeMetrics(m) {
+		if isDefaultMetrics(m, normalizedNames) || isExtraScrapeMetrics(m, normalizedNames) {
 			continue
 		}
 
@@ -364,7 +364,7 @@ func countScrapeMetricsRM(got pmetric.ResourceMetrics, normalizedNames bool) int
 func countScrapeMetrics(metrics []pmetric.Metric, normalizedNames bool) int {
 	n := 0
 	for _, m := range metrics {
-		if isDefaultMetrics(m, normalizedNames) || isExtraScrapeMetrics(m) {
+		if isDefaultMetrics(m, normalizedNames) || isExtraScrapeMetrics(m, normalizedNames) {
 			n++
 		}
 	}
@@ -386,10 +386,16 @@ func isDefaultMetrics(m pmetric.Metric, normalizedNames bool) bool {
 	return false
 }
 
-func isExtraScrapeMetrics(m pmetric.Metric) bool {
+func isExtraScrapeMetrics(m pmetric.Metric, normalizedNames bool) bool {
 	switch m.Name() {
-	case "scrape_body_size_bytes", "scrape_sample_limit", "scrape_timeout_seconds":
+	case "scrape_sample_limit":
 		return true
+
+	// if normalizedNames is true, we expect the units `_bytes` and `_seconds` to be trimmed.
+	case "scrape_body_size_bytes", "scrape_timeout_seconds":
+		return !normalizedNames
+	case "scrape_body_size", "scrape_timeout":
+		return normalizedNames
 	default:
 		return false
 	}
@@ -243,6 +243,94 @@ func verifyStaleNaNsFailedScrape(t *testing.T, td *testData, resourceMetric pmet
 	doCompare(t, fmt.Sprintf("failedScrape-scrape-%d", iteration), wantAttributes, resourceMetric, e1)
 }
 
+var disappearingSeriesPage1 = `
+# HELP go_threads Number of OS threads created
+# TYPE go_threads gauge
+go_threads 19
+
+# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 100
+http_requests_total{method="post",code="400"} 5
+`
+
+var disappearingSeriesPage2 = `
+# HELP go_threads Number of OS threads created
+# TYPE go_threads gauge
+go_threads 18
+
+# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 120
+`
+
+// TestStaleNaNsDisappearingSeries validates that a staleness marker, converted to a data point
+// flagged with no recorded value, is emitted for a series which is absent from a successful
+// scrape after being present in the previous scrape of the same target.
+func TestStaleNaNsDisappearingSeries(t *testing.T) {
+	targets := []*testData{
+		{
+			name: "target1",
+			pages: []mockPrometheusResponse{
+				{code: 200, data: disappearingSeriesPage1},
+				{code: 200, data: disappearingSeriesPage2},
+			},
+			validateFunc:    verifyStaleNaNsDisappearingSeries,
+			validateScrapes: true,
+		},
+	}
+	testComponent(t, targets, nil)
+}
+
+func verifyStaleNaNsDisappearingSeries(t *testing.T, td *testData, resourceMetrics []pmetric.ResourceMetrics) {
+	verifyNumTotalScrapeResults(t, td, resourceMetrics)
+	startTimestamp := getTS(resourceMetrics[0].ScopeMetrics().At(0).Metrics())
+
+	// the second scrape has 2 metrics + 5 internal scraper metrics
+	assert.Equal(t, 7, metricsCount(resourceMetrics[1]))
+	ts := getTS(resourceMetrics[1].ScopeMetrics().At(0).Metrics())
+	e := []metricExpectation{
+		{
+			"go_threads",
+			pmetric.MetricTypeGauge,
+			"",
+			[]dataPointExpectation{
+				{
+					numberPointComparator: []numberPointComparator{
+						compareTimestamp(ts),
+						compareDoubleValue(18),
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"http_requests_total",
+			pmetric.MetricTypeSum,
+			"",
+			[]dataPointExpectation{
+				{
+					numberPointComparator: []numberPointComparator{
+						compareStartTimestamp(startTimestamp),
+						compareTimestamp(ts),
+						compareDoubleValue(120),
+						compareAttributes(map[string]string{"method": "post", "code": "200"}),
+					},
+				},
+				{
+					numberPointComparator: []numberPointComparator{
+						compareTimestamp(ts),
+						assertNumberPointFlagNoRecordedValue(),
+						compareAttributes(map[string]string{"method": "post", "code": "400"}),
+					},
+				},
+			},
+			nil,
+		},
+	}
+	doCompare(t, "disappearing-series-scrape-2", td.attributes, resourceMetrics[1], e)
+}
+
 // Prometheus gauge metric can be set to NaN, a use case could be when value 0 is not representable
 // Prometheus summary metric quantiles can have NaN after getting expired
 var normalNaNsPage1 = `
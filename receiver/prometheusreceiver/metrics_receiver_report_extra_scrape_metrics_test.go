@@ -172,7 +172,7 @@ func verifyMetrics(t *testing.T, td *testData, resourceMetrics []pmetric.Resourc
 			{
 				"scrape_body_size_bytes",
 				pmetric.MetricTypeGauge,
-				"",
+				"By",
 				nil,
 				nil,
 			},
@@ -186,7 +186,7 @@ func verifyMetrics(t *testing.T, td *testData, resourceMetrics []pmetric.Resourc
 			{
 				"scrape_timeout_seconds",
 				pmetric.MetricTypeGauge,
-				"",
+				"s",
 				nil,
 				nil,
 			},
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusreceivertest // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/prometheusreceivertest"
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/scrape"
+)
+
+var _ scrape.MetricMetadataStore = (*MetadataStore)(nil)
+
+// MetadataStore is an in-memory scrape.MetricMetadataStore. It allows the type, unit and help
+// of metric families to be provided without a live scrape, e.g. in tests which build metrics
+// from samples directly. The store is usually attached to the scrape context with
+// scrape.ContextWithMetricMetadataStore. It is safe for concurrent use.
+type MetadataStore struct {
+	mu       sync.RWMutex
+	metadata map[string]scrape.MetricMetadata
+}
+
+// NewMetadataStore returns an empty MetadataStore.
+func NewMetadataStore() *MetadataStore {
+	return &MetadataStore{metadata: make(map[string]scrape.MetricMetadata)}
+}
+
+// SetMetadata stores the metadata under its metric family name, replacing any existing metadata.
+func (s *MetadataStore) SetMetadata(metadata scrape.MetricMetadata) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metadata[metadata.MetricFamily] = metadata
+}
+
+// SetType sets the type of the metric family, adding the family if it's unknown.
+func (s *MetadataStore) SetType(familyName string, metricType model.MetricType) {
+	s.update(familyName, func(metadata *scrape.MetricMetadata) {
+		metadata.Type = metricType
+	})
+}
+
+// SetUnit sets the unit of the metric family, adding the family if it's unknown.
+func (s *MetadataStore) SetUnit(familyName, unit string) {
+	s.update(familyName, func(metadata *scrape.MetricMetadata) {
+		metadata.Unit = unit
+	})
+}
+
+// SetHelp sets the help text of the metric family, adding the family if it's unknown.
+func (s *MetadataStore) SetHelp(familyName, help string) {
+	s.update(familyName, func(metadata *scrape.MetricMetadata) {
+		metadata.Help = help
+	})
+}
+
+// Delete removes the metadata of the metric family.
+func (s *MetadataStore) Delete(familyName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.metadata, familyName)
+}
+
+func (s *MetadataStore) update(familyName string, fn func(*scrape.MetricMetadata)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	metadata, ok := s.metadata[familyName]
+	if !ok {
+		metadata = scrape.MetricMetadata{MetricFamily: familyName, Type: model.MetricTypeUnknown}
+	}
+	fn(&metadata)
+	s.metadata[familyName] = metadata
+}
+
+// GetMetadata implements scrape.MetricMetadataStore.
+func (s *MetadataStore) GetMetadata(familyName string) (scrape.MetricMetadata, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	metadata, ok := s.metadata[familyName]
+	return metadata, ok
+}
+
+// ListMetadata implements scrape.MetricMetadataStore. The metadata is sorted by metric family name.
+func (s *MetadataStore) ListMetadata() []scrape.MetricMetadata {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]scrape.MetricMetadata, 0, len(s.metadata))
+	for _, metadata := range s.metadata {
+		list = append(list, metadata)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].MetricFamily < list[j].MetricFamily
+	})
+	return list
+}
+
+// SizeMetadata implements scrape.MetricMetadataStore, returning the size of the stored strings in bytes.
+func (s *MetadataStore) SizeMetadata() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	size := 0
+	for _, metadata := range s.metadata {
+		size += len(metadata.MetricFamily) + len(metadata.Type) + len(metadata.Help) + len(metadata.Unit)
+	}
+	return size
+}
+
+// LengthMetadata implements scrape.MetricMetadataStore, returning the number of metric families.
+func (s *MetadataStore) LengthMetadata() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.metadata)
+}
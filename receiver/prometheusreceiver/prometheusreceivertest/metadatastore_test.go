@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusreceivertest
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataStore(t *testing.T) {
+	store := NewMetadataStore()
+	assert.Equal(t, 0, store.LengthMetadata())
+	_, ok := store.GetMetadata("foo")
+	assert.False(t, ok)
+
+	store.SetUnit("foo", "seconds")
+	md, ok := store.GetMetadata("foo")
+	require.True(t, ok)
+	assert.Equal(t, scrape.MetricMetadata{MetricFamily: "foo", Type: model.MetricTypeUnknown, Unit: "seconds"}, md)
+
+	store.SetType("foo", model.MetricTypeGauge)
+	store.SetHelp("foo", "help")
+	store.SetMetadata(scrape.MetricMetadata{MetricFamily: "bar", Type: model.MetricTypeCounter})
+	assert.Equal(t, []scrape.MetricMetadata{
+		{MetricFamily: "bar", Type: model.MetricTypeCounter},
+		{MetricFamily: "foo", Type: model.MetricTypeGauge, Help: "help", Unit: "seconds"},
+	}, store.ListMetadata())
+	assert.Equal(t, 2, store.LengthMetadata())
+	assert.Equal(t, len("bar")+len("counter")+len("foo")+len("gauge")+len("help")+len("seconds"), store.SizeMetadata())
+
+	store.Delete("foo")
+	_, ok = store.GetMetadata("foo")
+	assert.False(t, ok)
+	assert.Equal(t, 1, store.LengthMetadata())
+}
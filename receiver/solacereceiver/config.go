@@ -26,6 +26,28 @@ var (
 	errMissingXauth2Params      = errors.New("missing xauth2 text auth params: Username, Bearer")
 	errMissingFlowControl       = errors.New("missing flow control configuration: DelayedRetry must be selected")
 	errInvalidDelayedRetryDelay = errors.New("delayed_retry.delay must > 0")
+	errInvalidSemConv           = errors.New("traces.semantic_conventions must be one of: current, legacy")
+	errInvalidMaxEnqueueEvents  = errors.New("traces.max_enqueue_events must >= 0")
+	errInvalidTTLAttributes     = errors.New("traces.ttl_attributes must be one of: raw, milliseconds, both")
+)
+
+const (
+	// semConvCurrent maps span attributes to the current messaging and network semantic conventions
+	semConvCurrent = "current"
+	// semConvLegacy maps destination and network span attributes to the legacy semantic conventions
+	semConvLegacy = "legacy"
+
+	// ttlAttrsRaw maps the message TTL to the messaging.solace.ttl span attribute
+	ttlAttrsRaw = "raw"
+	// ttlAttrsMilliseconds maps the message TTL to the unit suffixed messaging.solace.ttl_ms span attribute
+	ttlAttrsMilliseconds = "milliseconds"
+	// ttlAttrsBoth maps the message TTL to both span attributes
+	ttlAttrsBoth = "both"
+
+	// spanNameTopicPlaceholder is replaced by the topic of the message in the receive span name template
+	spanNameTopicPlaceholder = "{topic}"
+	// defaultSpanNameTemplate is the receive span name template used when none is configured
+	defaultSpanNameTemplate = spanNameTopicPlaceholder + " receive"
 )
 
 // Config defines configuration for Solace receiver.
@@ -44,6 +66,8 @@ type Config struct {
 	Auth Authentication `mapstructure:"auth"`
 
 	Flow FlowControl `mapstructure:"flow_control"`
+
+	Traces TracesConfig `mapstructure:"traces"`
 }
 
 // Validate checks the receiver configuration is valid
@@ -72,6 +96,17 @@ func (cfg *Config) Validate() error {
 	} else if cfg.Flow.DelayedRetry.Get().Delay <= 0 {
 		return errInvalidDelayedRetryDelay
 	}
+	if cfg.Traces.SemanticConventions != semConvCurrent && cfg.Traces.SemanticConventions != semConvLegacy {
+		return errInvalidSemConv
+	}
+	if cfg.Traces.MaxEnqueueEvents < 0 {
+		return errInvalidMaxEnqueueEvents
+	}
+	switch cfg.Traces.TTLAttributes {
+	case ttlAttrsRaw, ttlAttrsMilliseconds, ttlAttrsBoth:
+	default:
+		return errInvalidTTLAttributes
+	}
 	return nil
 }
 
@@ -121,3 +156,61 @@ type FlowControlDelayedRetry struct {
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
+
+// TracesConfig defines how the spans received from the Solace broker are mapped to traces
+type TracesConfig struct {
+	// EnqueueEventsOnlyOnFailure only emits enqueue span events for failed enqueues, that is when the
+	// destination rejects all enqueues or an enqueue error is present
+	EnqueueEventsOnlyOnFailure bool `mapstructure:"enqueue_events_only_on_failure"`
+
+	// SemanticConventions selects the semantic conventions of the destination and network span attributes
+	// of all span types, either current (default) or legacy, e.g. messaging.destination.name vs messaging.destination
+	SemanticConventions string `mapstructure:"semantic_conventions"`
+
+	// OmitZeroValueAttributes omits span attributes that are always mapped from the span data when their
+	// numeric value is zero or their boolean value is false, reducing the size of the spans
+	OmitZeroValueAttributes bool `mapstructure:"omit_zero_value_attributes"`
+
+	// MaxEnqueueEvents caps the number of enqueue span events mapped per span, 0 (default) means unlimited.
+	// The number of enqueue events dropped due to the cap is recorded as a span attribute
+	MaxEnqueueEvents int `mapstructure:"max_enqueue_events"`
+
+	// TraceIDUserProperty names a user property holding the hex encoded trace ID, which is used when the
+	// native trace ID of the span data is empty
+	TraceIDUserProperty string `mapstructure:"trace_id_user_property"`
+
+	// SpanIDUserProperty names a user property holding the hex encoded span ID, which is used when the
+	// native span ID of the span data is empty
+	SpanIDUserProperty string `mapstructure:"span_id_user_property"`
+
+	// PriorityBand also maps the message priority to a low (0-3), normal (4) or high (5-9) band,
+	// emitted as a span attribute next to the numeric priority
+	PriorityBand bool `mapstructure:"priority_band"`
+
+	// TTLAttributes selects the span attributes the message TTL, which Solace reports in milliseconds, is
+	// mapped to: raw (default) for messaging.solace.ttl, milliseconds for the unit suffixed
+	// messaging.solace.ttl_ms or both. The TTL override of enqueue events is mapped accordingly
+	TTLAttributes string `mapstructure:"ttl_attributes"`
+
+	// BrokerHostResourceAttributes promotes the IP and port of the broker which received the message onto the
+	// resource as net.host.name and net.host.port, allowing spans to be grouped by broker
+	BrokerHostResourceAttributes bool `mapstructure:"broker_host_resource_attributes"`
+
+	// SpanNameTemplate is the name of receive spans, in which {topic} is replaced by the topic of the
+	// message, or (unknown) if the topic is empty. Defaults to "{topic} receive"
+	SpanNameTemplate string `mapstructure:"span_name_template"`
+
+	// PayloadPartSizeAttributes also maps the sizes of the binary attachment, the XML attachment and the
+	// metadata of the message to separate span attributes, next to the summed body and envelope sizes
+	PayloadPartSizeAttributes bool `mapstructure:"payload_part_size_attributes"`
+
+	// MessagingSystem is the value of the messaging.system attribute of all spans. Defaults to "SolacePubSub+"
+	MessagingSystem string `mapstructure:"messaging_system"`
+
+	// DropSparseSpans drops receive spans which have no parent span and no enqueue or transaction events,
+	// counting them in the solacereceiver_dropped_sparse_spans metric
+	DropSparseSpans bool `mapstructure:"drop_sparse_spans"`
+
+	// prevent unkeyed literal initialization
+	_ struct{}
+}
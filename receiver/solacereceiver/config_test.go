@@ -51,6 +51,19 @@ func TestLoadConfig(t *testing.T) {
 						Delay: 1 * time.Second,
 					}),
 				},
+				Traces: TracesConfig{
+					EnqueueEventsOnlyOnFailure:   true,
+					SemanticConventions:          semConvLegacy,
+					OmitZeroValueAttributes:      true,
+					TraceIDUserProperty:          "app_trace_id",
+					PriorityBand:                 true,
+					TTLAttributes:                ttlAttrsBoth,
+					BrokerHostResourceAttributes: true,
+					SpanNameTemplate:             "receive {topic}",
+					PayloadPartSizeAttributes:    true,
+					DropSparseSpans:              true,
+					MessagingSystem:              "solace",
+				},
 			},
 		},
 		{
@@ -126,6 +139,33 @@ func TestConfigValidateInvalidFlowControlDelayedRetryDelay(t *testing.T) {
 	assert.ErrorContains(t, err, errInvalidDelayedRetryDelay.Error())
 }
 
+func TestConfigValidateInvalidSemanticConventions(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = configoptional.Some(SaslPlainTextConfig{Username: "Username", Password: "Password"})
+	cfg.Traces.SemanticConventions = "v1.0.0"
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, errInvalidSemConv.Error())
+}
+
+func TestConfigValidateInvalidTTLAttributes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = configoptional.Some(SaslPlainTextConfig{Username: "Username", Password: "Password"})
+	cfg.Traces.TTLAttributes = "seconds"
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, errInvalidTTLAttributes.Error())
+}
+
+func TestConfigValidateInvalidMaxEnqueueEvents(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = configoptional.Some(SaslPlainTextConfig{Username: "Username", Password: "Password"})
+	cfg.Traces.MaxEnqueueEvents = -1
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, errInvalidMaxEnqueueEvents.Error())
+}
+
 func TestConfigValidateSuccess(t *testing.T) {
 	successCases := map[string]func(*Config){
 		"With Plaintext Auth": func(c *Config) {
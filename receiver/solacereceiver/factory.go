@@ -48,6 +48,12 @@ func createDefaultConfig() component.Config {
 				Delay: 10 * time.Millisecond,
 			}),
 		},
+		Traces: TracesConfig{
+			SemanticConventions: semConvCurrent,
+			TTLAttributes:       ttlAttrsRaw,
+			SpanNameTemplate:    defaultSpanNameTemplate,
+			MessagingSystem:     systemAttrValue,
+		},
 	}
 }
 
@@ -28,8 +28,10 @@ type TelemetryBuilder struct {
 	registrations                                              []metric.Registration
 	SolacereceiverDroppedEgressSpans                           metric.Int64Counter
 	SolacereceiverDroppedSpanMessages                          metric.Int64Counter
+	SolacereceiverDroppedSparseSpans                           metric.Int64Counter
 	SolacereceiverFailedReconnections                          metric.Int64Counter
 	SolacereceiverFatalUnmarshallingErrors                     metric.Int64Counter
+	SolacereceiverMessagePayloadSize                           metric.Int64Histogram
 	SolacereceiverNeedUpgrade                                  metric.Int64Gauge
 	SolacereceiverReceivedSpanMessages                         metric.Int64Counter
 	SolacereceiverReceiverFlowControlRecentRetries             metric.Int64Gauge
@@ -82,6 +84,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.SolacereceiverDroppedSparseSpans, err = builder.meter.Int64Counter(
+		"otelcol_solacereceiver_dropped_sparse_spans",
+		metric.WithDescription("Number of receive spans dropped for having no parent span and no events [development]"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
 	builder.SolacereceiverFailedReconnections, err = builder.meter.Int64Counter(
 		"otelcol_solacereceiver_failed_reconnections",
 		metric.WithDescription("Number of failed broker reconnections [development]"),
@@ -94,6 +102,13 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.SolacereceiverMessagePayloadSize, err = builder.meter.Int64Histogram(
+		"otelcol_solacereceiver_message_payload_size",
+		metric.WithDescription("Size of the message payload of received spans, including binary and xml attachments and metadata [development]"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries([]float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}...),
+	)
+	errs = errors.Join(errs, err)
 	builder.SolacereceiverNeedUpgrade, err = builder.meter.Int64Gauge(
 		"otelcol_solacereceiver_need_upgrade",
 		metric.WithDescription("Indicates with value 1 that receiver requires an upgrade and is not compatible with messages received from a broker [development]"),
@@ -22,8 +22,10 @@ func TestSetupTelemetry(t *testing.T) {
 	defer tb.Shutdown()
 	tb.SolacereceiverDroppedEgressSpans.Add(context.Background(), 1)
 	tb.SolacereceiverDroppedSpanMessages.Add(context.Background(), 1)
+	tb.SolacereceiverDroppedSparseSpans.Add(context.Background(), 1)
 	tb.SolacereceiverFailedReconnections.Add(context.Background(), 1)
 	tb.SolacereceiverFatalUnmarshallingErrors.Add(context.Background(), 1)
+	tb.SolacereceiverMessagePayloadSize.Record(context.Background(), 1)
 	tb.SolacereceiverNeedUpgrade.Record(context.Background(), 1)
 	tb.SolacereceiverReceivedSpanMessages.Add(context.Background(), 1)
 	tb.SolacereceiverReceiverFlowControlRecentRetries.Record(context.Background(), 1)
@@ -39,12 +41,18 @@ func TestSetupTelemetry(t *testing.T) {
 	AssertEqualSolacereceiverDroppedSpanMessages(t, testTel,
 		[]metricdata.DataPoint[int64]{{Value: 1}},
 		metricdatatest.IgnoreTimestamp())
+	AssertEqualSolacereceiverDroppedSparseSpans(t, testTel,
+		[]metricdata.DataPoint[int64]{{Value: 1}},
+		metricdatatest.IgnoreTimestamp())
 	AssertEqualSolacereceiverFailedReconnections(t, testTel,
 		[]metricdata.DataPoint[int64]{{Value: 1}},
 		metricdatatest.IgnoreTimestamp())
 	AssertEqualSolacereceiverFatalUnmarshallingErrors(t, testTel,
 		[]metricdata.DataPoint[int64]{{Value: 1}},
 		metricdatatest.IgnoreTimestamp())
+	AssertEqualSolacereceiverMessagePayloadSize(t, testTel,
+		[]metricdata.HistogramDataPoint[int64]{{}}, metricdatatest.IgnoreValue(),
+		metricdatatest.IgnoreTimestamp())
 	AssertEqualSolacereceiverNeedUpgrade(t, testTel,
 		[]metricdata.DataPoint[int64]{{Value: 1}},
 		metricdatatest.IgnoreTimestamp())
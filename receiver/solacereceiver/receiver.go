@@ -91,7 +91,7 @@ func newTracesReceiver(config *Config, set receiver.Settings, nextConsumer consu
 		attribute.String(brokerComponentNameAttr, receiverName),
 	)
 
-	unmarshaller := newTracesUnmarshaller(set.Logger, telemetryBuilder, solaceBrokerAttrs)
+	unmarshaller := newTracesUnmarshaller(set.Logger, telemetryBuilder, solaceBrokerAttrs, config.Traces)
 
 	return &solaceTracesReceiver{
 		config:            config,
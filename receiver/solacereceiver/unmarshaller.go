@@ -26,7 +26,7 @@ type tracesUnmarshaller interface {
 }
 
 // newTracesUnmarshaller returns a new unmarshaller ready for message unmarshalling
-func newTracesUnmarshaller(logger *zap.Logger, telemetryBuilder *metadata.TelemetryBuilder, metricAttrs attribute.Set) tracesUnmarshaller {
+func newTracesUnmarshaller(logger *zap.Logger, telemetryBuilder *metadata.TelemetryBuilder, metricAttrs attribute.Set, cfg TracesConfig) tracesUnmarshaller {
 	return &solaceTracesUnmarshaller{
 		logger:           logger,
 		telemetryBuilder: telemetryBuilder,
@@ -35,16 +35,22 @@ func newTracesUnmarshaller(logger *zap.Logger, telemetryBuilder *metadata.Teleme
 			logger:           logger,
 			telemetryBuilder: telemetryBuilder,
 			metricAttrs:      metricAttrs,
+			messagingSystem:  messagingSystem(cfg),
+			semConvKeys:      semConvAttrKeysFor(cfg.SemanticConventions),
 		},
 		receiveUnmarshallerV1: &brokerTraceReceiveUnmarshallerV1{
 			logger:           logger,
 			telemetryBuilder: telemetryBuilder,
 			metricAttrs:      metricAttrs,
+			cfg:              cfg,
+			semConvKeys:      semConvAttrKeysFor(cfg.SemanticConventions),
 		},
 		egressUnmarshallerV1: &brokerTraceEgressUnmarshallerV1{
 			logger:           logger,
 			telemetryBuilder: telemetryBuilder,
 			metricAttrs:      metricAttrs,
+			messagingSystem:  messagingSystem(cfg),
+			semConvKeys:      semConvAttrKeysFor(cfg.SemanticConventions),
 		},
 	}
 }
@@ -145,6 +151,9 @@ const (
 	conversationIDAttrKey              = "messaging.message.conversation_id"
 	messageBodySizeBytesAttrKey        = "messaging.message.body.size"
 	messageEnvelopeSizeBytesAttrKey    = "messaging.message.envelope.size"
+	binaryAttachmentSizeAttrKey        = "messaging.solace.binary_attachment_size"
+	xmlAttachmentSizeAttrKey           = "messaging.solace.xml_attachment_size"
+	metadataSizeAttrKey                = "messaging.solace.metadata_size"
 	destinationNameAttrKey             = "messaging.destination.name"
 	destinationTypeAttrKey             = "messaging.solace.destination.type"
 	clientUsernameAttrKey              = "messaging.solace.client_username"
@@ -152,12 +161,16 @@ const (
 	partitionNumberKey                 = "messaging.solace.partition_number"
 	replicationGroupMessageIDAttrKey   = "messaging.solace.replication_group_message_id"
 	priorityAttrKey                    = "messaging.solace.priority"
+	priorityBandAttrKey                = "messaging.solace.priority_band"
 	ttlAttrKey                         = "messaging.solace.ttl"
+	ttlMsAttrKey                       = "messaging.solace.ttl_ms"
 	dmqEligibleAttrKey                 = "messaging.solace.dmq_eligible"
 	droppedEnqueueEventsSuccessAttrKey = "messaging.solace.dropped_enqueue_events_success"
 	droppedEnqueueEventsFailedAttrKey  = "messaging.solace.dropped_enqueue_events_failed"
+	truncatedEnqueueEventsAttrKey      = "messaging.solace.truncated_enqueue_events"
 	replyToAttrKey                     = "messaging.solace.reply_to_topic"
 	receiveTimeAttrKey                 = "messaging.solace.broker_receive_time_unix_nano"
+	expirationAttrKey                  = "messaging.solace.expiration_unix_nano"
 	droppedUserPropertiesAttrKey       = "messaging.solace.dropped_application_message_properties"
 	deliveryModeAttrKey                = "messaging.solace.delivery_mode"
 	hostIPAttrKey                      = "server.address"
@@ -166,6 +179,47 @@ const (
 	peerPortAttrKey                    = "network.peer.port"
 )
 
+// legacy span keys, used instead of the current keys when the legacy semantic conventions are configured
+const (
+	legacyDestinationNameAttrKey = "messaging.destination"
+	legacyHostIPAttrKey          = "net.host.ip"
+	legacyHostPortAttrKey        = "net.host.port"
+	legacyPeerIPAttrKey          = "net.peer.ip"
+	legacyPeerPortAttrKey        = "net.peer.port"
+)
+
+// semConvAttrKeys holds the span keys which differ between semantic convention versions
+type semConvAttrKeys struct {
+	destinationName string
+	hostIP          string
+	hostPort        string
+	peerIP          string
+	peerPort        string
+}
+
+// semConvAttrKeysFor returns the span keys of the given semantic conventions, defaulting to the current ones
+func semConvAttrKeysFor(semConv string) semConvAttrKeys {
+	if semConv == semConvLegacy {
+		return semConvAttrKeys{
+			destinationName: legacyDestinationNameAttrKey,
+			hostIP:          legacyHostIPAttrKey,
+			hostPort:        legacyHostPortAttrKey,
+			peerIP:          legacyPeerIPAttrKey,
+			peerPort:        legacyPeerPortAttrKey,
+		}
+	}
+	return semConvAttrKeys{
+		destinationName: destinationNameAttrKey,
+		hostIP:          hostIPAttrKey,
+		hostPort:        hostPortAttrKey,
+		peerIP:          peerIPAttrKey,
+		peerPort:        peerPortAttrKey,
+	}
+}
+
+// sampledTraceFlag is the W3C trace flags sampled bit, stored in the low byte of the span flags
+const sampledTraceFlag = uint32(0x01)
+
 // constant attributes
 const (
 	systemAttrKey        = "messaging.system"
@@ -174,6 +228,15 @@ const (
 	operationTypeAttrKey = "messaging.operation.type"
 )
 
+// messagingSystem returns the configured value of the messaging.system span attribute, or the default
+// value if none is configured
+func messagingSystem(cfg TracesConfig) string {
+	if cfg.MessagingSystem == "" {
+		return systemAttrValue
+	}
+	return cfg.MessagingSystem
+}
+
 func setResourceSpanAttributes(attrMap pcommon.Map, routerName, version string, messageVpnName *string) {
 	const (
 		routerNameAttrKey     = "service.name"
@@ -187,9 +250,16 @@ func setResourceSpanAttributes(attrMap pcommon.Map, routerName, version string,
 	}
 }
 
+// replication group message id layout, the version byte is followed by 16 bytes of id.
+// Only version 1 is known, other versions are hex dumped as is.
+const (
+	rgmidVersion1 = 1
+	rgmidLength   = 17
+)
+
 func rgmidToString(rgmid []byte, otelMetricAttrs attribute.Set, telemetryBuilder *metadata.TelemetryBuilder, logger *zap.Logger) string {
 	// rgmid[0] is the version of the rgmid
-	if len(rgmid) != 17 || rgmid[0] != 1 {
+	if len(rgmid) != rgmidLength || rgmid[0] != rgmidVersion1 {
 		// may be cases where the rgmid is empty or nil, len(rgmid) will return 0 if nil
 		if len(rgmid) > 0 {
 			logger.Warn("Received invalid length or version for rgmid", zap.Int8("version", int8(rgmid[0])), zap.Int("length", len(rgmid)))
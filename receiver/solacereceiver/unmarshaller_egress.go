@@ -24,7 +24,9 @@ import (
 type brokerTraceEgressUnmarshallerV1 struct {
 	logger           *zap.Logger
 	telemetryBuilder *metadata.TelemetryBuilder
-	metricAttrs      attribute.Set // other Otel attributes (to add to the metrics)
+	metricAttrs      attribute.Set   // other Otel attributes (to add to the metrics)
+	messagingSystem  string          // value of the messaging.system span attribute
+	semConvKeys      semConvAttrKeys // span keys of the configured semantic conventions
 }
 
 // unmarshal implements tracesUnmarshaller.unmarshal
@@ -142,7 +144,7 @@ func (u *brokerTraceEgressUnmarshallerV1) mapSendSpan(sendSpan *egress_v1.SpanDa
 	span.SetKind(ptrace.SpanKindProducer)
 
 	attributes := span.Attributes()
-	attributes.PutStr(systemAttrKey, systemAttrValue)
+	attributes.PutStr(systemAttrKey, u.messagingSystem)
 	attributes.PutStr(operationNameAttrKey, sendSpanOperationName)
 	attributes.PutStr(operationTypeAttrKey, sendSpanOperationType)
 	attributes.PutStr(protocolAttrKey, sendSpan.Protocol)
@@ -208,7 +210,6 @@ func (u *brokerTraceEgressUnmarshallerV1) mapSendSpan(sendSpan *egress_v1.SpanDa
 
 func (u *brokerTraceEgressUnmarshallerV1) mapDeleteSpan(deleteSpan *egress_v1.SpanData_DeleteSpan, span ptrace.Span) {
 	const (
-		destinationNameKey       = "messaging.destination.name"
 		deleteOperationReasonKey = "messaging.solace.operation.reason"
 	)
 	const (
@@ -231,7 +232,7 @@ func (u *brokerTraceEgressUnmarshallerV1) mapDeleteSpan(deleteSpan *egress_v1.Sp
 	span.SetKind(ptrace.SpanKindInternal)
 
 	attributes := span.Attributes()
-	attributes.PutStr(systemAttrKey, systemAttrValue)
+	attributes.PutStr(systemAttrKey, u.messagingSystem)
 	attributes.PutStr(operationNameAttrKey, spanOperationName)
 	attributes.PutStr(operationTypeAttrKey, spanOperationType)
 
@@ -249,7 +250,7 @@ func (u *brokerTraceEgressUnmarshallerV1) mapDeleteSpan(deleteSpan *egress_v1.Sp
 		} else {
 			endpointName = casted.TopicEndpointName
 		}
-		attributes.PutStr(destinationNameKey, casted.TopicEndpointName)
+		attributes.PutStr(u.semConvKeys.destinationName, casted.TopicEndpointName)
 		attributes.PutStr(destinationTypeAttrKey, topicEndpointKind)
 	case *egress_v1.SpanData_DeleteSpan_QueueName:
 		if isAnonymousQueue(casted.QueueName) {
@@ -257,7 +258,7 @@ func (u *brokerTraceEgressUnmarshallerV1) mapDeleteSpan(deleteSpan *egress_v1.Sp
 		} else {
 			endpointName = casted.QueueName
 		}
-		attributes.PutStr(destinationNameKey, casted.QueueName)
+		attributes.PutStr(u.semConvKeys.destinationName, casted.QueueName)
 		attributes.PutStr(destinationTypeAttrKey, queueKind)
 	default:
 		u.logger.Warn(fmt.Sprintf("Unknown endpoint type %T", casted))
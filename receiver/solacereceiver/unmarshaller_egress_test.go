@@ -736,6 +736,19 @@ func TestEgressUnmarshallerDeleteSpanAttributes(t *testing.T) {
 	}
 }
 
+func TestEgressUnmarshallerDeleteSpanLegacySemanticConventions(t *testing.T) {
+	u, _ := newTestEgressV1Unmarshaller(t)
+	u.semConvKeys = semConvAttrKeysFor(semConvLegacy)
+	actual := ptrace.NewSpan()
+	u.mapDeleteSpan(&egress_v1.SpanData_DeleteSpan{
+		EndpointName: &egress_v1.SpanData_DeleteSpan_QueueName{QueueName: "someQueue"},
+		TypeInfo:     &egress_v1.SpanData_DeleteSpan_TtlExpiredInfo{},
+	}, actual)
+	attrs := actual.Attributes().AsRaw()
+	assert.Equal(t, "someQueue", attrs["messaging.destination"])
+	assert.NotContains(t, attrs, "messaging.destination.name")
+}
+
 func TestEgressUnmarshallerTransactionEvent(t *testing.T) {
 	someErrorString := "some error"
 	tests := []struct {
@@ -913,5 +926,5 @@ func newTestEgressV1Unmarshaller(t *testing.T) (*brokerTraceEgressUnmarshallerV1
 	builder, err := metadata.NewTelemetryBuilder(tt.NewTelemetrySettings())
 	require.NoError(t, err)
 	metricAttr := attribute.NewSet(attribute.String("receiver_name", ""))
-	return &brokerTraceEgressUnmarshallerV1{zap.NewNop(), builder, metricAttr}, tt
+	return &brokerTraceEgressUnmarshallerV1{zap.NewNop(), builder, metricAttr, systemAttrValue, semConvAttrKeysFor(semConvCurrent)}, tt
 }
@@ -21,7 +21,9 @@ import (
 type brokerTraceMoveUnmarshallerV1 struct {
 	logger           *zap.Logger
 	telemetryBuilder *metadata.TelemetryBuilder
-	metricAttrs      attribute.Set // other Otel attributes (to add to the metrics)
+	metricAttrs      attribute.Set   // other Otel attributes (to add to the metrics)
+	messagingSystem  string          // value of the messaging.system span attribute
+	semConvKeys      semConvAttrKeys // span keys of the configured semantic conventions
 }
 
 // unmarshal implements tracesUnmarshaller.unmarshal
@@ -97,7 +99,6 @@ func (u *brokerTraceMoveUnmarshallerV1) mapClientSpanData(moveSpan *move_v1.Span
 	const (
 		sourceNameKey                 = "messaging.source.name"
 		sourceKindKey                 = "messaging.solace.source.kind"
-		destinationNameKey            = "messaging.destination.name"
 		moveOperationReasonKey        = "messaging.solace.operation.reason"
 		sourcePartitionNumberKey      = "messaging.solace.source.partition_number"
 		destinationPartitionNumberKey = "messaging.solace.destination.partition_number"
@@ -117,7 +118,7 @@ func (u *brokerTraceMoveUnmarshallerV1) mapClientSpanData(moveSpan *move_v1.Span
 	)
 
 	attributes := span.Attributes()
-	attributes.PutStr(systemAttrKey, systemAttrValue)
+	attributes.PutStr(systemAttrKey, u.messagingSystem)
 	attributes.PutStr(operationNameAttrKey, spanOperationName)
 	attributes.PutStr(operationTypeAttrKey, spanOperationType)
 
@@ -168,10 +169,10 @@ func (u *brokerTraceMoveUnmarshallerV1) mapClientSpanData(moveSpan *move_v1.Span
 	// don't fatal out when we receive invalid endpoint name, instead just log and increment stats
 	switch casted := moveSpan.Destination.(type) {
 	case *move_v1.SpanData_DestinationTopicEndpointName:
-		attributes.PutStr(destinationNameKey, casted.DestinationTopicEndpointName)
+		attributes.PutStr(u.semConvKeys.destinationName, casted.DestinationTopicEndpointName)
 		attributes.PutStr(destinationTypeAttrKey, topicEndpointKind)
 	case *move_v1.SpanData_DestinationQueueName:
-		attributes.PutStr(destinationNameKey, casted.DestinationQueueName)
+		attributes.PutStr(u.semConvKeys.destinationName, casted.DestinationQueueName)
 		attributes.PutStr(destinationTypeAttrKey, queueKind)
 	default:
 		u.logger.Warn(fmt.Sprintf("Unknown endpoint type %T", casted))
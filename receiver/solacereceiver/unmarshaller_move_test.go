@@ -376,11 +376,25 @@ func TestMoveUnmarshallerMapClientSpanAttributes(t *testing.T) {
 	}
 }
 
+func TestMoveUnmarshallerLegacySemanticConventions(t *testing.T) {
+	u, _ := newTestMoveV1Unmarshaller(t)
+	u.semConvKeys = semConvAttrKeysFor(semConvLegacy)
+	actual := ptrace.NewSpan()
+	u.mapClientSpanData(&move_v1.SpanData{
+		Source:      &move_v1.SpanData_SourceQueueName{SourceQueueName: "sourceQueue"},
+		Destination: &move_v1.SpanData_DestinationQueueName{DestinationQueueName: "destQueue"},
+		TypeInfo:    &move_v1.SpanData_TtlExpiredInfo{},
+	}, actual)
+	attrs := actual.Attributes().AsRaw()
+	assert.Equal(t, "destQueue", attrs["messaging.destination"])
+	assert.NotContains(t, attrs, "messaging.destination.name")
+}
+
 func newTestMoveV1Unmarshaller(t *testing.T) (*brokerTraceMoveUnmarshallerV1, *componenttest.Telemetry) {
 	tel := componenttest.NewTelemetry()
 	t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) }) //nolint:usetesting
 	builder, err := metadata.NewTelemetryBuilder(tel.NewTelemetrySettings())
 	require.NoError(t, err)
 	metricAttr := attribute.NewSet(attribute.String("receiver_name", metadata.Type.String()))
-	return &brokerTraceMoveUnmarshallerV1{zap.NewNop(), builder, metricAttr}, tel
+	return &brokerTraceMoveUnmarshallerV1{zap.NewNop(), builder, metricAttr, systemAttrValue, semConvAttrKeysFor(semConvCurrent)}, tel
 }
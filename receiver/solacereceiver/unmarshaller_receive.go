@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -26,6 +27,8 @@ type brokerTraceReceiveUnmarshallerV1 struct {
 	logger           *zap.Logger
 	telemetryBuilder *metadata.TelemetryBuilder
 	metricAttrs      attribute.Set // other Otel attributes (to add to the metrics)
+	cfg              TracesConfig
+	semConvKeys      semConvAttrKeys // span keys of the configured semantic conventions
 }
 
 // unmarshal implements tracesUnmarshaller.unmarshal
@@ -57,6 +60,10 @@ func (*brokerTraceReceiveUnmarshallerV1) unmarshalToSpanData(message *inboundMes
 // This will set all required fields such as name version, trace and span ID, parent span ID (if applicable),
 // timestamps, errors and states.
 func (u *brokerTraceReceiveUnmarshallerV1) populateTraces(spanData *receive_v1.SpanData, traces ptrace.Traces) {
+	if u.cfg.DropSparseSpans && isSparseSpan(spanData) {
+		u.telemetryBuilder.SolacereceiverDroppedSparseSpans.Add(context.Background(), 1, metric.WithAttributeSet(u.metricAttrs))
+		return
+	}
 	// Append new resource span and map any attributes
 	resourceSpan := traces.ResourceSpans().AppendEmpty()
 	u.mapResourceSpanAttributes(spanData, resourceSpan.Resource().Attributes())
@@ -71,28 +78,56 @@ func (u *brokerTraceReceiveUnmarshallerV1) populateTraces(spanData *receive_v1.S
 	u.mapEvents(spanData, clientSpan)
 }
 
-func (*brokerTraceReceiveUnmarshallerV1) mapResourceSpanAttributes(spanData *receive_v1.SpanData, attrMap pcommon.Map) {
+// isSparseSpan returns true if the span has no parent span and neither enqueue nor transaction events.
+// Like in mapClientSpanData, a parent span ID which isn't 8 bytes long counts as no parent span.
+func isSparseSpan(spanData *receive_v1.SpanData) bool {
+	return len(spanData.GetParentSpanId()) != 8 && len(spanData.GetEnqueueEvents()) == 0 && spanData.GetTransactionEvent() == nil
+}
+
+func (u *brokerTraceReceiveUnmarshallerV1) mapResourceSpanAttributes(spanData *receive_v1.SpanData, attrMap pcommon.Map) {
+	const (
+		brokerHostNameAttrKey = "net.host.name"
+		brokerHostPortAttrKey = "net.host.port"
+	)
 	setResourceSpanAttributes(attrMap, spanData.RouterName, spanData.SolosVersion, spanData.MessageVpnName)
+	if !u.cfg.BrokerHostResourceAttributes {
+		return
+	}
+	// the host IP is optional, it is only promoted when present
+	if hostIPLen := len(spanData.HostIp); hostIPLen == 4 || hostIPLen == 16 {
+		attrMap.PutStr(brokerHostNameAttrKey, net.IP(spanData.HostIp).String())
+		attrMap.PutInt(brokerHostPortAttrKey, int64(spanData.HostPort))
+	}
 }
 
-func (*brokerTraceReceiveUnmarshallerV1) mapClientSpanData(spanData *receive_v1.SpanData, clientSpan ptrace.Span) {
+func (u *brokerTraceReceiveUnmarshallerV1) mapClientSpanData(spanData *receive_v1.SpanData, clientSpan ptrace.Span) {
 	// Set client span name
-	if spanData.Topic != "" {
-		clientSpan.SetName(spanData.Topic + " receive")
-	} else {
-		clientSpan.SetName("(unknown) receive")
+	topic := spanData.Topic
+	if topic == "" {
+		topic = "(unknown)"
 	}
+	spanNameTemplate := u.cfg.SpanNameTemplate
+	if spanNameTemplate == "" {
+		spanNameTemplate = defaultSpanNameTemplate
+	}
+	clientSpan.SetName(strings.ReplaceAll(spanNameTemplate, spanNameTopicPlaceholder, topic))
 
 	// SPAN_KIND_CONSUMER == 5
 	clientSpan.SetKind(ptrace.SpanKindConsumer)
 
-	// map trace ID
+	// map trace ID, falling back to the configured user property if the native trace ID is empty
 	var traceID [16]byte
 	copy(traceID[:16], spanData.TraceId)
+	if pcommon.TraceID(traceID).IsEmpty() && u.cfg.TraceIDUserProperty != "" {
+		u.copyIDFromUserProperty(spanData, u.cfg.TraceIDUserProperty, traceID[:])
+	}
 	clientSpan.SetTraceID(traceID)
-	// map span ID
+	// map span ID, falling back to the configured user property if the native span ID is empty
 	var spanID [8]byte
 	copy(spanID[:8], spanData.SpanId)
+	if pcommon.SpanID(spanID).IsEmpty() && u.cfg.SpanIDUserProperty != "" {
+		u.copyIDFromUserProperty(spanData, u.cfg.SpanIDUserProperty, spanID[:])
+	}
 	clientSpan.SetSpanID(spanID)
 	// conditional parent-span-id
 	if len(spanData.ParentSpanId) == 8 {
@@ -113,6 +148,28 @@ func (*brokerTraceReceiveUnmarshallerV1) mapClientSpanData(spanData *receive_v1.
 	if spanData.TraceState != nil {
 		clientSpan.TraceState().FromRaw(*spanData.TraceState)
 	}
+	// trace flags, the span data does not carry any sampling flags and the broker only
+	// generates spans for messages that are traced, so the span is always marked as sampled
+	clientSpan.SetFlags(clientSpan.Flags() | sampledTraceFlag)
+}
+
+// copyIDFromUserProperty decodes the hex encoded ID held by the named string user property into id.
+// id is left unchanged if the user property is absent, and a recoverable error is recorded if it is not
+// a hex string of the same length as id.
+func (u *brokerTraceReceiveUnmarshallerV1) copyIDFromUserProperty(spanData *receive_v1.SpanData, name string, id []byte) {
+	property, ok := spanData.UserProperties[name]
+	if !ok || property == nil {
+		return
+	}
+	if value, ok := property.Value.(*receive_v1.SpanData_UserPropertyValue_StringValue); ok {
+		decoded, err := hex.DecodeString(value.StringValue)
+		if err == nil && len(decoded) == len(id) {
+			copy(id, decoded)
+			return
+		}
+	}
+	u.logger.Warn("Received invalid ID in user property", zap.String("user_property", name))
+	u.telemetryBuilder.SolacereceiverRecoverableUnmarshallingErrors.Add(context.Background(), 1, metric.WithAttributeSet(u.metricAttrs))
 }
 
 // mapAttributes takes a set of attributes from SpanData and maps them to ClientSpan.Attributes().
@@ -120,7 +177,7 @@ func (*brokerTraceReceiveUnmarshallerV1) mapClientSpanData(spanData *receive_v1.
 func (u *brokerTraceReceiveUnmarshallerV1) mapClientSpanAttributes(spanData *receive_v1.SpanData, attrMap pcommon.Map) {
 	// receive operation
 	const operationTypeAttrValue = "receive"
-	attrMap.PutStr(systemAttrKey, systemAttrValue)
+	attrMap.PutStr(systemAttrKey, messagingSystem(u.cfg))
 	attrMap.PutStr(operationNameAttrKey, operationTypeAttrValue)
 	attrMap.PutStr(operationTypeAttrKey, operationTypeAttrValue)
 
@@ -134,12 +191,19 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapClientSpanAttributes(spanData *rec
 	if spanData.CorrelationId != nil {
 		attrMap.PutStr(conversationIDAttrKey, *spanData.CorrelationId)
 	}
-	attrMap.PutInt(messageBodySizeBytesAttrKey, int64(spanData.BinaryAttachmentSize+spanData.XmlAttachmentSize))                           // only message payload
-	attrMap.PutInt(messageEnvelopeSizeBytesAttrKey, int64(spanData.BinaryAttachmentSize+spanData.XmlAttachmentSize+spanData.MetadataSize)) // payload with metadata
+	payloadSize := int64(spanData.BinaryAttachmentSize + spanData.XmlAttachmentSize + spanData.MetadataSize)
+	u.putInt(attrMap, messageBodySizeBytesAttrKey, int64(spanData.BinaryAttachmentSize+spanData.XmlAttachmentSize)) // only message payload
+	u.putInt(attrMap, messageEnvelopeSizeBytesAttrKey, payloadSize)                                                 // payload with metadata
+	if u.cfg.PayloadPartSizeAttributes {
+		u.putInt(attrMap, binaryAttachmentSizeAttrKey, int64(spanData.BinaryAttachmentSize))
+		u.putInt(attrMap, xmlAttachmentSizeAttrKey, int64(spanData.XmlAttachmentSize))
+		u.putInt(attrMap, metadataSizeAttrKey, int64(spanData.MetadataSize))
+	}
+	u.telemetryBuilder.SolacereceiverMessagePayloadSize.Record(context.Background(), payloadSize, metric.WithAttributeSet(u.metricAttrs))
 	attrMap.PutStr(clientUsernameAttrKey, spanData.ClientUsername)
 	attrMap.PutStr(clientNameAttrKey, spanData.ClientName)
-	attrMap.PutInt(receiveTimeAttrKey, spanData.BrokerReceiveTimeUnixNano)
-	attrMap.PutStr(destinationNameAttrKey, spanData.Topic)
+	u.putInt(attrMap, receiveTimeAttrKey, spanData.BrokerReceiveTimeUnixNano)
+	attrMap.PutStr(u.semConvKeys.destinationName, spanData.Topic)
 
 	var deliveryMode string
 	switch spanData.DeliveryMode {
@@ -156,7 +220,6 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapClientSpanAttributes(spanData *rec
 	}
 	attrMap.PutStr(deliveryModeAttrKey, deliveryMode)
 
-	// rgmid := u.rgmidToString(spanData.ReplicationGroupMessageId)
 	rgmid := rgmidToString(spanData.ReplicationGroupMessageId, u.metricAttrs, u.telemetryBuilder, u.logger)
 	if rgmid != "" {
 		attrMap.PutStr(replicationGroupMessageIDAttrKey, rgmid)
@@ -164,30 +227,39 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapClientSpanAttributes(spanData *rec
 
 	if spanData.Priority != nil {
 		attrMap.PutInt(priorityAttrKey, int64(*spanData.Priority))
+		if u.cfg.PriorityBand {
+			if band, ok := priorityBand(*spanData.Priority); ok {
+				attrMap.PutStr(priorityBandAttrKey, band)
+			}
+		}
 	}
 	if spanData.Ttl != nil {
-		attrMap.PutInt(ttlAttrKey, *spanData.Ttl)
+		u.putTTL(attrMap, ttlAttrKey, ttlMsAttrKey, *spanData.Ttl)
+		// a TTL of zero means the message never expires
+		if *spanData.Ttl > 0 && spanData.BrokerReceiveTimeUnixNano != 0 {
+			attrMap.PutInt(expirationAttrKey, spanData.BrokerReceiveTimeUnixNano+*spanData.Ttl*int64(time.Millisecond))
+		}
 	}
 	if spanData.ReplyToTopic != nil {
 		attrMap.PutStr(replyToAttrKey, *spanData.ReplyToTopic)
 	}
-	attrMap.PutBool(dmqEligibleAttrKey, spanData.DmqEligible)
-	attrMap.PutInt(droppedEnqueueEventsSuccessAttrKey, int64(spanData.DroppedEnqueueEventsSuccess))
-	attrMap.PutInt(droppedEnqueueEventsFailedAttrKey, int64(spanData.DroppedEnqueueEventsFailed))
+	u.putBool(attrMap, dmqEligibleAttrKey, spanData.DmqEligible)
+	u.putInt(attrMap, droppedEnqueueEventsSuccessAttrKey, int64(spanData.DroppedEnqueueEventsSuccess))
+	u.putInt(attrMap, droppedEnqueueEventsFailedAttrKey, int64(spanData.DroppedEnqueueEventsFailed))
 
 	// The IPs are now optional meaning we will not include them if they are zero length
 	hostIPLen := len(spanData.HostIp)
 	if hostIPLen == 4 || hostIPLen == 16 {
-		attrMap.PutStr(hostIPAttrKey, net.IP(spanData.HostIp).String())
-		attrMap.PutInt(hostPortAttrKey, int64(spanData.HostPort))
+		attrMap.PutStr(u.semConvKeys.hostIP, net.IP(spanData.HostIp).String())
+		attrMap.PutInt(u.semConvKeys.hostPort, int64(spanData.HostPort))
 	} else {
 		u.logger.Debug("Host ip not included", zap.Int("length", hostIPLen))
 	}
 
 	peerIPLen := len(spanData.PeerIp)
 	if peerIPLen == 4 || peerIPLen == 16 {
-		attrMap.PutStr(peerIPAttrKey, net.IP(spanData.PeerIp).String())
-		attrMap.PutInt(peerPortAttrKey, int64(spanData.PeerPort))
+		attrMap.PutStr(u.semConvKeys.peerIP, net.IP(spanData.PeerIp).String())
+		attrMap.PutInt(u.semConvKeys.peerPort, int64(spanData.PeerPort))
 	} else {
 		u.logger.Debug("Peer IP not included", zap.Int("length", peerIPLen))
 	}
@@ -200,7 +272,7 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapClientSpanAttributes(spanData *rec
 		}
 	}
 
-	attrMap.PutBool(droppedUserPropertiesAttrKey, spanData.DroppedApplicationMessageProperties)
+	u.putBool(attrMap, droppedUserPropertiesAttrKey, spanData.DroppedApplicationMessageProperties)
 	for key, value := range spanData.UserProperties {
 		if value != nil {
 			u.insertUserProperty(attrMap, key, value.Value)
@@ -208,11 +280,65 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapClientSpanAttributes(spanData *rec
 	}
 }
 
+// putInt inserts the always mapped int attribute, unless it is zero and zero value attributes are omitted
+func (u *brokerTraceReceiveUnmarshallerV1) putInt(attrMap pcommon.Map, key string, value int64) {
+	if value == 0 && u.cfg.OmitZeroValueAttributes {
+		return
+	}
+	attrMap.PutInt(key, value)
+}
+
+// putBool inserts the always mapped bool attribute, unless it is false and zero value attributes are omitted
+func (u *brokerTraceReceiveUnmarshallerV1) putBool(attrMap pcommon.Map, key string, value bool) {
+	if !value && u.cfg.OmitZeroValueAttributes {
+		return
+	}
+	attrMap.PutBool(key, value)
+}
+
+// putTTL puts the TTL in milliseconds under the raw key, the unit suffixed msKey or both, as configured
+func (u *brokerTraceReceiveUnmarshallerV1) putTTL(attrMap pcommon.Map, key, msKey string, ttl int64) {
+	if u.cfg.TTLAttributes != ttlAttrsMilliseconds {
+		attrMap.PutInt(key, ttl)
+	}
+	if u.cfg.TTLAttributes == ttlAttrsMilliseconds || u.cfg.TTLAttributes == ttlAttrsBoth {
+		attrMap.PutInt(msKey, ttl)
+	}
+}
+
+// priorityBand returns the band of a Solace message priority, which ranges from 0 (lowest) to 9 (highest)
+// with 4 being the default priority. It returns false for priorities out of that range.
+func priorityBand(priority uint32) (string, bool) {
+	switch {
+	case priority < 4:
+		return "low", true
+	case priority == 4:
+		return "normal", true
+	case priority <= 9:
+		return "high", true
+	default:
+		return "", false
+	}
+}
+
 // mapEvents maps all events contained in SpanData to relevant events within clientSpan.Events()
 func (u *brokerTraceReceiveUnmarshallerV1) mapEvents(spanData *receive_v1.SpanData, clientSpan ptrace.Span) {
-	// handle enqueue events
+	// handle enqueue events, stopping once the configured maximum is reached and
+	// recording how many were dropped
+	mapped, truncated := 0, 0
 	for _, enqueueEvent := range spanData.EnqueueEvents {
-		u.mapEnqueueEvent(enqueueEvent, clientSpan.Events())
+		if maxEvents := u.cfg.MaxEnqueueEvents; maxEvents > 0 && mapped >= maxEvents {
+			if u.isMappedEnqueueEvent(enqueueEvent) {
+				truncated++
+			}
+			continue
+		}
+		if u.mapEnqueueEvent(enqueueEvent, clientSpan.Events()) {
+			mapped++
+		}
+	}
+	if truncated > 0 {
+		clientSpan.Attributes().PutInt(truncatedEnqueueEventsAttrKey, int64(truncated))
 	}
 
 	// handle transaction events
@@ -221,15 +347,37 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapEvents(spanData *receive_v1.SpanDa
 	}
 }
 
-// mapEnqueueEvent maps a SpanData_EnqueueEvent to a ClientSpan.Event
-func (u *brokerTraceReceiveUnmarshallerV1) mapEnqueueEvent(enqueueEvent *receive_v1.SpanData_EnqueueEvent, clientSpanEvents ptrace.SpanEventSlice) {
+// isMappedEnqueueEvent reports whether mapEnqueueEvent maps the enqueue event to a span event,
+// that is whether its destination type is known and it isn't omitted as a successful enqueue.
+func (u *brokerTraceReceiveUnmarshallerV1) isMappedEnqueueEvent(enqueueEvent *receive_v1.SpanData_EnqueueEvent) bool {
+	switch enqueueEvent.Dest.(type) {
+	case *receive_v1.SpanData_EnqueueEvent_TopicEndpointName, *receive_v1.SpanData_EnqueueEvent_QueueName:
+		return !u.cfg.EnqueueEventsOnlyOnFailure || isFailedEnqueueEvent(enqueueEvent)
+	default:
+		return false
+	}
+}
+
+// isFailedEnqueueEvent reports whether the enqueue failed, that is whether the destination
+// rejects all enqueues or an enqueue error is present.
+func isFailedEnqueueEvent(enqueueEvent *receive_v1.SpanData_EnqueueEvent) bool {
+	return enqueueEvent.RejectsAllEnqueues || enqueueEvent.ErrorDescription != nil
+}
+
+// mapEnqueueEvent maps a SpanData_EnqueueEvent to a ClientSpan.Event. It returns false if no
+// event was added.
+func (u *brokerTraceReceiveUnmarshallerV1) mapEnqueueEvent(enqueueEvent *receive_v1.SpanData_EnqueueEvent, clientSpanEvents ptrace.SpanEventSlice) bool {
 	const (
 		enqueueEventSuffix               = " enqueue" // Final should be `<dest> enqueue`
 		messagingDestinationTypeEventKey = "messaging.solace.destination.type"
 		statusMessageEventKey            = "messaging.solace.enqueue_error_message"
 		rejectsAllEnqueuesKey            = "messaging.solace.rejects_all_enqueues"
+		enqueueOutcomeKey                = "messaging.solace.enqueue_outcome"
+		enqueueOutcomeSuccess            = "success"
+		enqueueOutcomeFailure            = "failure"
 		partitionNumberKey               = "messaging.solace.partition_number"
 		ttlOverrideKey                   = "messaging.solace.ttl_override"
+		ttlOverrideMsKey                 = "messaging.solace.ttl_override_ms"
 	)
 	var destinationName string
 	var destinationType string
@@ -243,14 +391,24 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapEnqueueEvent(enqueueEvent *receive
 	default:
 		u.logger.Warn(fmt.Sprintf("Unknown destination type %T", casted))
 		u.telemetryBuilder.SolacereceiverRecoverableUnmarshallingErrors.Add(context.Background(), 1, metric.WithAttributeSet(u.metricAttrs))
-		return
+		return false
+	}
+	failed := isFailedEnqueueEvent(enqueueEvent)
+	// successful enqueues are omitted if only failed enqueues are requested
+	if u.cfg.EnqueueEventsOnlyOnFailure && !failed {
+		return false
 	}
 	clientEvent := clientSpanEvents.AppendEmpty()
 	clientEvent.SetName(destinationName + enqueueEventSuffix)
 	clientEvent.SetTimestamp(pcommon.Timestamp(enqueueEvent.TimeUnixNano))
-	clientEvent.Attributes().EnsureCapacity(3)
+	clientEvent.Attributes().EnsureCapacity(4)
 	clientEvent.Attributes().PutStr(messagingDestinationTypeEventKey, destinationType)
 	clientEvent.Attributes().PutBool(rejectsAllEnqueuesKey, enqueueEvent.RejectsAllEnqueues)
+	if failed {
+		clientEvent.Attributes().PutStr(enqueueOutcomeKey, enqueueOutcomeFailure)
+	} else {
+		clientEvent.Attributes().PutStr(enqueueOutcomeKey, enqueueOutcomeSuccess)
+	}
 	if enqueueEvent.ErrorDescription != nil {
 		clientEvent.Attributes().PutStr(statusMessageEventKey, enqueueEvent.GetErrorDescription())
 	}
@@ -258,8 +416,9 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapEnqueueEvent(enqueueEvent *receive
 		clientEvent.Attributes().PutInt(partitionNumberKey, int64(*enqueueEvent.PartitionNumber))
 	}
 	if enqueueEvent.Ttl != nil {
-		clientEvent.Attributes().PutInt(ttlOverrideKey, *enqueueEvent.Ttl)
+		u.putTTL(clientEvent.Attributes(), ttlOverrideKey, ttlOverrideMsKey, *enqueueEvent.Ttl)
 	}
+	return true
 }
 
 // mapTransactionEvent maps a SpanData_TransactionEvent to a ClientSpan.Event
@@ -325,23 +484,6 @@ func (u *brokerTraceReceiveUnmarshallerV1) mapTransactionEvent(transactionEvent
 	}
 }
 
-func (u *brokerTraceReceiveUnmarshallerV1) rgmidToString(rgmid []byte) string {
-	// rgmid[0] is the version of the rgmid
-	if len(rgmid) != 17 || rgmid[0] != 1 {
-		// may be cases where the rgmid is empty or nil, len(rgmid) will return 0 if nil
-		if len(rgmid) > 0 {
-			u.logger.Warn("Received invalid length or version for rgmid", zap.Int8("version", int8(rgmid[0])), zap.Int("length", len(rgmid)))
-			u.telemetryBuilder.SolacereceiverRecoverableUnmarshallingErrors.Add(context.Background(), 1, metric.WithAttributeSet(u.metricAttrs))
-		}
-		return hex.EncodeToString(rgmid)
-	}
-	rgmidEncoded := make([]byte, 32)
-	hex.Encode(rgmidEncoded, rgmid[1:])
-	// format: rmid1:aaaaa-bbbbbbbbbbb-cccccccc-dddddddd
-	rgmidString := "rmid1:" + string(rgmidEncoded[0:5]) + "-" + string(rgmidEncoded[5:16]) + "-" + string(rgmidEncoded[16:24]) + "-" + string(rgmidEncoded[24:32])
-	return rgmidString
-}
-
 // unmarshalBaggage will unmarshal a baggage string
 // See spec https://github.com/open-telemetry/opentelemetry-go/blob/v1.11.1/baggage/baggage.go
 func (*brokerTraceReceiveUnmarshallerV1) unmarshalBaggage(toMap pcommon.Map, baggageString string) error {
@@ -7,7 +7,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/Azure/go-amqp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -23,6 +25,34 @@ import (
 	receive_v1 "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver/internal/model/receive/v1"
 )
 
+func TestReceiveUnmarshallerEmptyMessageData(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *inboundMessage
+	}{
+		{
+			name:    "Nil Data",
+			message: &amqp.Message{},
+		},
+		{
+			name:    "Nil Data Section",
+			message: &amqp.Message{Data: [][]byte{nil}},
+		},
+		{
+			name:    "Empty Data",
+			message: &amqp.Message{Data: [][]byte{{}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			traces, err := u.unmarshal(tt.message)
+			assert.ErrorIs(t, err, errEmptyPayload)
+			assert.Equal(t, ptrace.Traces{}, traces)
+		})
+	}
+}
+
 func TestReceiveUnmarshallerMapResourceSpan(t *testing.T) {
 	var (
 		routerName = "someRouterName"
@@ -30,10 +60,11 @@ func TestReceiveUnmarshallerMapResourceSpan(t *testing.T) {
 		version    = "10.0.0"
 	)
 	tests := []struct {
-		name                        string
-		spanData                    *receive_v1.SpanData
-		want                        map[string]any
-		expectedUnmarshallingErrors int64
+		name                         string
+		spanData                     *receive_v1.SpanData
+		brokerHostResourceAttributes bool
+		want                         map[string]any
+		expectedUnmarshallingErrors  int64
 	}{
 		{
 			name: "Maps All Fields When Present",
@@ -56,10 +87,68 @@ func TestReceiveUnmarshallerMapResourceSpan(t *testing.T) {
 				"service.name":    "",
 			},
 		},
+		{
+			name: "Does Not Map Broker Host When Disabled",
+			spanData: &receive_v1.SpanData{
+				RouterName:   routerName,
+				SolosVersion: version,
+				HostIp:       []byte{1, 2, 3, 4},
+				HostPort:     55555,
+			},
+			want: map[string]any{
+				"service.name":    routerName,
+				"service.version": version,
+			},
+		},
+		{
+			name: "Maps Broker Host When Enabled",
+			spanData: &receive_v1.SpanData{
+				RouterName:   routerName,
+				SolosVersion: version,
+				HostIp:       []byte{1, 2, 3, 4},
+				HostPort:     55555,
+			},
+			brokerHostResourceAttributes: true,
+			want: map[string]any{
+				"service.name":    routerName,
+				"service.version": version,
+				"net.host.name":   "1.2.3.4",
+				"net.host.port":   int64(55555),
+			},
+		},
+		{
+			name: "Maps IPv6 Broker Host When Enabled",
+			spanData: &receive_v1.SpanData{
+				RouterName:   routerName,
+				SolosVersion: version,
+				HostIp:       []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+				HostPort:     55443,
+			},
+			brokerHostResourceAttributes: true,
+			want: map[string]any{
+				"service.name":    routerName,
+				"service.version": version,
+				"net.host.name":   "2001:db8::1",
+				"net.host.port":   int64(55443),
+			},
+		},
+		{
+			name: "Does Not Map Absent Broker Host When Enabled",
+			spanData: &receive_v1.SpanData{
+				RouterName:   routerName,
+				SolosVersion: version,
+			},
+			brokerHostResourceAttributes: true,
+			want: map[string]any{
+				"service.name":    routerName,
+				"service.version": version,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			u, tel := newTestReceiveV1Unmarshaller(t)
+			u.cfg.BrokerHostResourceAttributes = tt.brokerHostResourceAttributes
 			actual := pcommon.NewMap()
 			u.mapResourceSpanAttributes(tt.spanData, actual)
 			assert.Equal(t, tt.want, actual.AsRaw())
@@ -101,6 +190,7 @@ func TestReceiveUnmarshallerMapClientSpanData(t *testing.T) {
 				// expect some constants
 				span.SetKind(5)
 				span.SetName("(unknown) receive")
+				span.SetFlags(sampledTraceFlag)
 				span.Status().SetCode(ptrace.StatusCodeUnset)
 			},
 		},
@@ -128,6 +218,7 @@ func TestReceiveUnmarshallerMapClientSpanData(t *testing.T) {
 				// expect some constants
 				span.SetKind(5)
 				span.SetName("(unknown) receive")
+				span.SetFlags(sampledTraceFlag)
 			},
 		},
 	}
@@ -139,6 +230,79 @@ func TestReceiveUnmarshallerMapClientSpanData(t *testing.T) {
 			expected := ptrace.NewTraces().ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
 			tt.want(expected)
 			assert.Equal(t, expected, actual)
+			assert.Equal(t, sampledTraceFlag, actual.Flags()&sampledTraceFlag)
+		})
+	}
+}
+
+func TestReceiveUnmarshallerMapClientSpanDataIDUserProperties(t *testing.T) {
+	stringProperty := func(value string) *receive_v1.SpanData_UserPropertyValue {
+		return &receive_v1.SpanData_UserPropertyValue{Value: &receive_v1.SpanData_UserPropertyValue_StringValue{StringValue: value}}
+	}
+	tests := []struct {
+		name                   string
+		data                   *receive_v1.SpanData
+		wantTraceID            pcommon.TraceID
+		wantSpanID             pcommon.SpanID
+		wantUnmarshallingError bool
+	}{
+		{
+			name: "Native IDs Present",
+			data: &receive_v1.SpanData{
+				TraceId: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+				SpanId:  []byte{7, 6, 5, 4, 3, 2, 1, 0},
+				UserProperties: map[string]*receive_v1.SpanData_UserPropertyValue{
+					"app_trace_id": stringProperty("0f0e0d0c0b0a09080706050403020100"),
+					"app_span_id":  stringProperty("0001020304050607"),
+				},
+			},
+			wantTraceID: [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			wantSpanID:  [8]byte{7, 6, 5, 4, 3, 2, 1, 0},
+		},
+		{
+			name: "Native IDs Absent With User Properties",
+			data: &receive_v1.SpanData{
+				UserProperties: map[string]*receive_v1.SpanData_UserPropertyValue{
+					"app_trace_id": stringProperty("0f0e0d0c0b0a09080706050403020100"),
+					"app_span_id":  stringProperty("0001020304050607"),
+				},
+			},
+			wantTraceID: [16]byte{15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+			wantSpanID:  [8]byte{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name: "Native IDs And User Properties Absent",
+			data: &receive_v1.SpanData{},
+		},
+		{
+			name: "Invalid User Property",
+			data: &receive_v1.SpanData{
+				SpanId: []byte{7, 6, 5, 4, 3, 2, 1, 0},
+				UserProperties: map[string]*receive_v1.SpanData_UserPropertyValue{
+					"app_trace_id": stringProperty("not hex"),
+				},
+			},
+			wantSpanID:             [8]byte{7, 6, 5, 4, 3, 2, 1, 0},
+			wantUnmarshallingError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, tel := newTestReceiveV1Unmarshaller(t)
+			u.cfg.TraceIDUserProperty = "app_trace_id"
+			u.cfg.SpanIDUserProperty = "app_span_id"
+			actual := ptrace.NewTraces().ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			u.mapClientSpanData(tt.data, actual)
+			assert.Equal(t, tt.wantTraceID, actual.TraceID())
+			assert.Equal(t, tt.wantSpanID, actual.SpanID())
+			if tt.wantUnmarshallingError {
+				metadatatest.AssertEqualSolacereceiverRecoverableUnmarshallingErrors(t, tel, []metricdata.DataPoint[int64]{
+					{
+						Value:      1,
+						Attributes: u.metricAttrs,
+					},
+				}, metricdatatest.IgnoreTimestamp())
+			}
 		})
 	}
 }
@@ -213,6 +377,7 @@ func TestReceiveUnmarshallerMapClientSpanAttributes(t *testing.T) {
 				"messaging.solace.replication_group_message_id":           "rmid1:00010-40910192431-40516479-90a9c4e1",
 				"messaging.solace.priority":                               int64(1),
 				"messaging.solace.ttl":                                    int64(86000),
+				"messaging.solace.expiration_unix_nano":                   int64(87357924680),
 				"messaging.solace.dmq_eligible":                           true,
 				"messaging.solace.dropped_enqueue_events_success":         int64(42),
 				"messaging.solace.dropped_enqueue_events_failed":          int64(24),
@@ -340,6 +505,420 @@ func TestReceiveUnmarshallerMapClientSpanAttributes(t *testing.T) {
 	}
 }
 
+func TestReceiveUnmarshallerMapClientSpanAttributesSemanticConventions(t *testing.T) {
+	spanData := &receive_v1.SpanData{
+		Topic:    "someTopic",
+		HostIp:   []byte{1, 2, 3, 4},
+		HostPort: 55555,
+		PeerIp:   []byte{5, 6, 7, 8},
+		PeerPort: 12345,
+	}
+	tests := []struct {
+		name       string
+		semConv    string
+		want       map[string]any
+		wantAbsent []string
+	}{
+		{
+			name:    "Current",
+			semConv: semConvCurrent,
+			want: map[string]any{
+				"messaging.destination.name": "someTopic",
+				"server.address":             "1.2.3.4",
+				"server.port":                int64(55555),
+				"network.peer.address":       "5.6.7.8",
+				"network.peer.port":          int64(12345),
+			},
+			wantAbsent: []string{"messaging.destination", "net.host.ip", "net.host.port", "net.peer.ip", "net.peer.port"},
+		},
+		{
+			name:    "Legacy",
+			semConv: semConvLegacy,
+			want: map[string]any{
+				"messaging.destination": "someTopic",
+				"net.host.ip":           "1.2.3.4",
+				"net.host.port":         int64(55555),
+				"net.peer.ip":           "5.6.7.8",
+				"net.peer.port":         int64(12345),
+			},
+			wantAbsent: []string{"messaging.destination.name", "server.address", "server.port", "network.peer.address", "network.peer.port"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.SemanticConventions = tt.semConv
+			u.semConvKeys = semConvAttrKeysFor(tt.semConv)
+			actual := pcommon.NewMap()
+			u.mapClientSpanAttributes(spanData, actual)
+			raw := actual.AsRaw()
+			for key, value := range tt.want {
+				assert.Equal(t, value, raw[key], key)
+			}
+			for _, key := range tt.wantAbsent {
+				assert.NotContains(t, raw, key)
+			}
+		})
+	}
+}
+
+func TestReceiveUnmarshallerMapClientSpanAttributesPriorityBand(t *testing.T) {
+	tests := []struct {
+		priority uint32
+		want     string
+	}{
+		{priority: 0, want: "low"},
+		{priority: 3, want: "low"},
+		{priority: 4, want: "normal"},
+		{priority: 5, want: "high"},
+		{priority: 9, want: "high"},
+		{priority: 10},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("priority %d", tt.priority), func(t *testing.T) {
+			for _, enabled := range []bool{false, true} {
+				u, _ := newTestReceiveV1Unmarshaller(t)
+				u.cfg.PriorityBand = enabled
+				actual := pcommon.NewMap()
+				u.mapClientSpanAttributes(&receive_v1.SpanData{Priority: &tt.priority}, actual)
+
+				// the numeric priority is always kept
+				priority, ok := actual.Get("messaging.solace.priority")
+				require.True(t, ok)
+				assert.Equal(t, int64(tt.priority), priority.Int())
+
+				band, ok := actual.Get("messaging.solace.priority_band")
+				if !enabled || tt.want == "" {
+					assert.False(t, ok)
+					continue
+				}
+				require.True(t, ok)
+				assert.Equal(t, tt.want, band.Str())
+			}
+		})
+	}
+}
+
+func TestReceiveUnmarshallerTTLAttributes(t *testing.T) {
+	ttl := int64(86400000)
+	tests := []struct {
+		ttlAttributes string
+		want          map[string]any
+		wantAbsent    []string
+	}{
+		{
+			ttlAttributes: "",
+			want:          map[string]any{"messaging.solace.ttl": ttl, "messaging.solace.ttl_override": ttl},
+			wantAbsent:    []string{"messaging.solace.ttl_ms", "messaging.solace.ttl_override_ms"},
+		},
+		{
+			ttlAttributes: ttlAttrsRaw,
+			want:          map[string]any{"messaging.solace.ttl": ttl, "messaging.solace.ttl_override": ttl},
+			wantAbsent:    []string{"messaging.solace.ttl_ms", "messaging.solace.ttl_override_ms"},
+		},
+		{
+			ttlAttributes: ttlAttrsMilliseconds,
+			want:          map[string]any{"messaging.solace.ttl_ms": ttl, "messaging.solace.ttl_override_ms": ttl},
+			wantAbsent:    []string{"messaging.solace.ttl", "messaging.solace.ttl_override"},
+		},
+		{
+			ttlAttributes: ttlAttrsBoth,
+			want: map[string]any{
+				"messaging.solace.ttl":             ttl,
+				"messaging.solace.ttl_ms":          ttl,
+				"messaging.solace.ttl_override":    ttl,
+				"messaging.solace.ttl_override_ms": ttl,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("ttl_attributes="+tt.ttlAttributes, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.TTLAttributes = tt.ttlAttributes
+
+			spanAttrs := pcommon.NewMap()
+			u.mapClientSpanAttributes(&receive_v1.SpanData{Ttl: &ttl}, spanAttrs)
+			events := ptrace.NewSpanEventSlice()
+			u.mapEnqueueEvent(&receive_v1.SpanData_EnqueueEvent{
+				Dest: &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue"},
+				Ttl:  &ttl,
+			}, events)
+			require.Equal(t, 1, events.Len())
+
+			raw := spanAttrs.AsRaw()
+			for key, value := range events.At(0).Attributes().AsRaw() {
+				raw[key] = value
+			}
+			for key, value := range tt.want {
+				assert.Equal(t, value, raw[key], key)
+			}
+			for _, key := range tt.wantAbsent {
+				assert.NotContains(t, raw, key)
+			}
+		})
+	}
+}
+
+func TestReceiveUnmarshallerExpirationAttribute(t *testing.T) {
+	ttl := int64(1500)
+	zeroTTL := int64(0)
+	tests := []struct {
+		name     string
+		spanData *receive_v1.SpanData
+		want     any
+	}{
+		{
+			name:     "ttl and receive time",
+			spanData: &receive_v1.SpanData{Ttl: &ttl, BrokerReceiveTimeUnixNano: 1357924680},
+			want:     int64(1357924680 + 1500*int64(time.Millisecond)),
+		},
+		{
+			name:     "no ttl",
+			spanData: &receive_v1.SpanData{BrokerReceiveTimeUnixNano: 1357924680},
+		},
+		{
+			name:     "zero ttl",
+			spanData: &receive_v1.SpanData{Ttl: &zeroTTL, BrokerReceiveTimeUnixNano: 1357924680},
+		},
+		{
+			name:     "no receive time",
+			spanData: &receive_v1.SpanData{Ttl: &ttl},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			attrs := pcommon.NewMap()
+			u.mapClientSpanAttributes(tt.spanData, attrs)
+			got, ok := attrs.AsRaw()["messaging.solace.expiration_unix_nano"]
+			if tt.want == nil {
+				assert.False(t, ok)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReceiveUnmarshallerSpanNameTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		topic    string
+		want     string
+	}{
+		{name: "default", topic: "someTopic", want: "someTopic receive"},
+		{name: "default unknown topic", want: "(unknown) receive"},
+		{name: "templated", template: "receive {topic}", topic: "someTopic", want: "receive someTopic"},
+		{name: "templated unknown topic", template: "receive {topic}", want: "receive (unknown)"},
+		{name: "repeated placeholder", template: "{topic}/{topic}", topic: "a/b", want: "a/b/a/b"},
+		{name: "fixed name", template: "solace receive", topic: "someTopic", want: "solace receive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.SpanNameTemplate = tt.template
+			span := ptrace.NewSpan()
+			u.mapClientSpanData(&receive_v1.SpanData{Topic: tt.topic}, span)
+			assert.Equal(t, tt.want, span.Name())
+		})
+	}
+}
+
+func TestReceiveUnmarshallerPayloadPartSizeAttributes(t *testing.T) {
+	spanData := &receive_v1.SpanData{
+		BinaryAttachmentSize: 1000,
+		XmlAttachmentSize:    200,
+		MetadataSize:         30,
+	}
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.PayloadPartSizeAttributes = enabled
+			attrs := pcommon.NewMap()
+			u.mapClientSpanAttributes(spanData, attrs)
+			raw := attrs.AsRaw()
+			assert.Equal(t, int64(1200), raw["messaging.message.body.size"])
+			assert.Equal(t, int64(1230), raw["messaging.message.envelope.size"])
+			for key, want := range map[string]int64{
+				"messaging.solace.binary_attachment_size": 1000,
+				"messaging.solace.xml_attachment_size":    200,
+				"messaging.solace.metadata_size":          30,
+			} {
+				got, ok := raw[key]
+				if !enabled {
+					assert.False(t, ok, key)
+					continue
+				}
+				assert.Equal(t, want, got, key)
+			}
+		})
+	}
+}
+
+func TestReceiveUnmarshallerMessagingSystem(t *testing.T) {
+	tests := []struct {
+		name            string
+		messagingSystem string
+		want            string
+	}{
+		{name: "default", want: "SolacePubSub+"},
+		{name: "configured", messagingSystem: "solace", want: "solace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.MessagingSystem = tt.messagingSystem
+			traces := ptrace.NewTraces()
+			u.populateTraces(&receive_v1.SpanData{Topic: "someTopic"}, traces)
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			got, ok := span.Attributes().Get("messaging.system")
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got.Str())
+		})
+	}
+}
+
+func TestReceiveUnmarshallerDropSparseSpans(t *testing.T) {
+	sparse := &receive_v1.SpanData{Topic: "someTopic"}
+	withParent := &receive_v1.SpanData{Topic: "someTopic", ParentSpanId: []byte{1, 2, 3, 4, 5, 6, 7, 8}}
+	withEvent := &receive_v1.SpanData{Topic: "someTopic", EnqueueEvents: []*receive_v1.SpanData_EnqueueEvent{{
+		Dest: &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "someQueue"},
+	}}}
+	tests := []struct {
+		name            string
+		dropSparseSpans bool
+		spanData        *receive_v1.SpanData
+		wantDropped     bool
+	}{
+		{name: "sparse span kept by default", spanData: sparse},
+		{name: "sparse span dropped", dropSparseSpans: true, spanData: sparse, wantDropped: true},
+		{name: "span with parent kept", dropSparseSpans: true, spanData: withParent},
+		{name: "span with events kept", dropSparseSpans: true, spanData: withEvent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, tel := newTestReceiveV1Unmarshaller(t)
+			u.cfg.DropSparseSpans = tt.dropSparseSpans
+			traces := ptrace.NewTraces()
+			u.populateTraces(tt.spanData, traces)
+			if !tt.wantDropped {
+				assert.Equal(t, 1, traces.SpanCount())
+				_, err := tel.GetMetric("otelcol_solacereceiver_dropped_sparse_spans")
+				assert.Error(t, err)
+				return
+			}
+			assert.Equal(t, 0, traces.SpanCount())
+			metadatatest.AssertEqualSolacereceiverDroppedSparseSpans(t, tel, []metricdata.DataPoint[int64]{
+				{
+					Value:      1,
+					Attributes: u.metricAttrs,
+				},
+			}, metricdatatest.IgnoreTimestamp())
+		})
+	}
+}
+
+func TestReceiveUnmarshallerMapClientSpanAttributesOmitZeroValueAttributes(t *testing.T) {
+	zeroValueKeys := []string{
+		"messaging.message.body.size",
+		"messaging.message.envelope.size",
+		"messaging.solace.broker_receive_time_unix_nano",
+		"messaging.solace.dmq_eligible",
+		"messaging.solace.dropped_enqueue_events_success",
+		"messaging.solace.dropped_enqueue_events_failed",
+		"messaging.solace.dropped_application_message_properties",
+	}
+	tests := []struct {
+		name                    string
+		omitZeroValueAttributes bool
+		spanData                *receive_v1.SpanData
+		want                    map[string]any
+		wantAbsent              []string
+	}{
+		{
+			name:     "zero values kept by default",
+			spanData: &receive_v1.SpanData{Topic: "someTopic"},
+			want: map[string]any{
+				"messaging.message.body.size":                             int64(0),
+				"messaging.message.envelope.size":                         int64(0),
+				"messaging.solace.broker_receive_time_unix_nano":          int64(0),
+				"messaging.solace.dmq_eligible":                           false,
+				"messaging.solace.dropped_enqueue_events_success":         int64(0),
+				"messaging.solace.dropped_enqueue_events_failed":          int64(0),
+				"messaging.solace.dropped_application_message_properties": false,
+			},
+		},
+		{
+			name:                    "zero values omitted",
+			omitZeroValueAttributes: true,
+			spanData:                &receive_v1.SpanData{Topic: "someTopic"},
+			want: map[string]any{
+				"messaging.destination.name": "someTopic",
+			},
+			wantAbsent: zeroValueKeys,
+		},
+		{
+			name:                    "non-zero values kept",
+			omitZeroValueAttributes: true,
+			spanData: &receive_v1.SpanData{
+				Topic:                               "someTopic",
+				BinaryAttachmentSize:                10,
+				MetadataSize:                        5,
+				BrokerReceiveTimeUnixNano:           1234567890,
+				DmqEligible:                         true,
+				DroppedEnqueueEventsSuccess:         42,
+				DroppedEnqueueEventsFailed:          24,
+				DroppedApplicationMessageProperties: true,
+			},
+			want: map[string]any{
+				"messaging.message.body.size":                             int64(10),
+				"messaging.message.envelope.size":                         int64(15),
+				"messaging.solace.broker_receive_time_unix_nano":          int64(1234567890),
+				"messaging.solace.dmq_eligible":                           true,
+				"messaging.solace.dropped_enqueue_events_success":         int64(42),
+				"messaging.solace.dropped_enqueue_events_failed":          int64(24),
+				"messaging.solace.dropped_application_message_properties": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.OmitZeroValueAttributes = tt.omitZeroValueAttributes
+			actual := pcommon.NewMap()
+			u.mapClientSpanAttributes(tt.spanData, actual)
+			raw := actual.AsRaw()
+			for key, value := range tt.want {
+				assert.Equal(t, value, raw[key], key)
+			}
+			for _, key := range tt.wantAbsent {
+				assert.NotContains(t, raw, key)
+			}
+		})
+	}
+}
+
+func TestReceiveUnmarshallerRecordsPayloadSize(t *testing.T) {
+	u, tel := newTestReceiveV1Unmarshaller(t)
+	u.mapClientSpanAttributes(&receive_v1.SpanData{
+		BinaryAttachmentSize: 1000,
+		XmlAttachmentSize:    200,
+		MetadataSize:         34,
+	}, pcommon.NewMap())
+	metadatatest.AssertEqualSolacereceiverMessagePayloadSize(t, tel, []metricdata.HistogramDataPoint[int64]{
+		{
+			Attributes:   u.metricAttrs,
+			Count:        1,
+			Bounds:       []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304},
+			BucketCounts: []uint64{0, 0, 1, 0, 0, 0, 0, 0, 0},
+			Min:          metricdata.NewExtrema[int64](1234),
+			Max:          metricdata.NewExtrema[int64](1234),
+			Sum:          1234,
+		},
+	}, metricdatatest.IgnoreTimestamp())
+}
+
 // Validate that all event types are properly handled and appended into the span data
 func TestReceiveUnmarshallerEvents(t *testing.T) {
 	someErrorString := "some error"
@@ -370,6 +949,7 @@ func TestReceiveUnmarshallerEvents(t *testing.T) {
 				populateEvent(t, span, "somequeue enqueue", 123456789, map[string]any{
 					"messaging.solace.destination.type":     "queue",
 					"messaging.solace.rejects_all_enqueues": false,
+					"messaging.solace.enqueue_outcome":      "success",
 					"messaging.solace.partition_number":     345,
 				})
 			},
@@ -391,6 +971,7 @@ func TestReceiveUnmarshallerEvents(t *testing.T) {
 					"messaging.solace.destination.type":      "topic-endpoint",
 					"messaging.solace.enqueue_error_message": someErrorString,
 					"messaging.solace.rejects_all_enqueues":  true,
+					"messaging.solace.enqueue_outcome":       "failure",
 				})
 			},
 		},
@@ -412,10 +993,12 @@ func TestReceiveUnmarshallerEvents(t *testing.T) {
 				populateEvent(t, span, "somequeue enqueue", 123456789, map[string]any{
 					"messaging.solace.destination.type":     "queue",
 					"messaging.solace.rejects_all_enqueues": false,
+					"messaging.solace.enqueue_outcome":      "success",
 				})
 				populateEvent(t, span, "sometopic enqueue", 2345678, map[string]any{
 					"messaging.solace.destination.type":     "topic-endpoint",
 					"messaging.solace.rejects_all_enqueues": false,
+					"messaging.solace.enqueue_outcome":      "success",
 				})
 			},
 		},
@@ -568,10 +1151,12 @@ func TestReceiveUnmarshallerEvents(t *testing.T) {
 				populateEvent(t, span, "somequeue enqueue", 123456789, map[string]any{
 					"messaging.solace.destination.type":     "queue",
 					"messaging.solace.rejects_all_enqueues": false,
+					"messaging.solace.enqueue_outcome":      "success",
 				})
 				populateEvent(t, span, "sometopic enqueue", 2345678, map[string]any{
 					"messaging.solace.destination.type":     "topic-endpoint",
 					"messaging.solace.rejects_all_enqueues": true,
+					"messaging.solace.enqueue_outcome":      "failure",
 				})
 				populateEvent(t, span, "rollback_only", 123456789, map[string]any{
 					"messaging.solace.transaction_initiator":   "client",
@@ -616,11 +1201,17 @@ func TestReceiveUnmarshallerRGMID(t *testing.T) {
 			expected: "rmid1:00010-40910192431-40516479-90a9c4e1",
 		},
 		{
-			name:     "Bad RGMID Version",
+			name:     "Unknown RGMID Version 2",
 			in:       []byte{0x02, 0x00, 0x01, 0x04, 0x09, 0x10, 0x19, 0x24, 0x31, 0x40, 0x51, 0x64, 0x79, 0x90, 0xa9, 0xc4, 0xe1},
 			expected: "0200010409101924314051647990a9c4e1", // expect default behavior of hex dump
 			numErr:   1,
 		},
+		{
+			name:     "Unknown RGMID Version 3",
+			in:       []byte{0x03, 0x00, 0x01, 0x04, 0x09, 0x10, 0x19, 0x24, 0x31, 0x40, 0x51, 0x64, 0x79, 0x90, 0xa9, 0xc4, 0xe1},
+			expected: "0300010409101924314051647990a9c4e1", // expect default behavior of hex dump
+			numErr:   1,
+		},
 		{
 			name:     "Bad RGMID length",
 			in:       []byte{0x00, 0x01, 0x04, 0x09, 0x10, 0x19, 0x24, 0x31, 0x40, 0x51, 0x64, 0x79, 0x90, 0xa9, 0xc4, 0xe1},
@@ -636,7 +1227,7 @@ func TestReceiveUnmarshallerRGMID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			u, tel := newTestReceiveV1Unmarshaller(t)
-			actual := u.rgmidToString(tt.in)
+			actual := rgmidToString(tt.in, u.metricAttrs, u.telemetryBuilder, u.logger)
 			assert.Equal(t, tt.expected, actual)
 			if tt.numErr > 0 {
 				metadatatest.AssertEqualSolacereceiverRecoverableUnmarshallingErrors(t, tel, []metricdata.DataPoint[int64]{
@@ -857,6 +1448,98 @@ func TestReceiveUnmarshallerInsertUserProperty(t *testing.T) {
 	}
 }
 
+func TestReceiveUnmarshallerEnqueueEventsOnlyOnFailure(t *testing.T) {
+	someErrorString := "some error"
+	spanData := &receive_v1.SpanData{
+		EnqueueEvents: []*receive_v1.SpanData_EnqueueEvent{
+			{
+				Dest:         &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "successqueue"},
+				TimeUnixNano: 123456789,
+			},
+			{
+				Dest:               &receive_v1.SpanData_EnqueueEvent_TopicEndpointName{TopicEndpointName: "rejectingtopic"},
+				TimeUnixNano:       2345678,
+				RejectsAllEnqueues: true,
+			},
+			{
+				Dest:             &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "failedqueue"},
+				TimeUnixNano:     3456789,
+				ErrorDescription: &someErrorString,
+			},
+		},
+	}
+	tests := []struct {
+		name                       string
+		enqueueEventsOnlyOnFailure bool
+		want                       []string
+	}{
+		{
+			name: "All Enqueue Events",
+			want: []string{"successqueue enqueue", "rejectingtopic enqueue", "failedqueue enqueue"},
+		},
+		{
+			name:                       "Failed Enqueue Events Only",
+			enqueueEventsOnlyOnFailure: true,
+			want:                       []string{"rejectingtopic enqueue", "failedqueue enqueue"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.EnqueueEventsOnlyOnFailure = tt.enqueueEventsOnlyOnFailure
+			actual := ptrace.NewTraces().ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			u.mapEvents(spanData, actual)
+			var names []string
+			for _, event := range actual.Events().All() {
+				names = append(names, event.Name())
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestReceiveUnmarshallerEnqueueOutcome(t *testing.T) {
+	someErrorString := "some error"
+	tests := []struct {
+		name  string
+		event *receive_v1.SpanData_EnqueueEvent
+		want  string
+	}{
+		{
+			name:  "Successful Enqueue",
+			event: &receive_v1.SpanData_EnqueueEvent{Dest: &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue"}},
+			want:  "success",
+		},
+		{
+			name: "Enqueue Error",
+			event: &receive_v1.SpanData_EnqueueEvent{
+				Dest:             &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue"},
+				ErrorDescription: &someErrorString,
+			},
+			want: "failure",
+		},
+		{
+			name: "Rejects All Enqueues",
+			event: &receive_v1.SpanData_EnqueueEvent{
+				Dest:               &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue"},
+				RejectsAllEnqueues: true,
+			},
+			want: "failure",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			events := ptrace.NewSpanEventSlice()
+			u.mapEnqueueEvent(tt.event, events)
+			require.Equal(t, 1, events.Len())
+			outcome, ok := events.At(0).Attributes().Get("messaging.solace.enqueue_outcome")
+			require.True(t, ok)
+			assert.Equal(t, tt.want, outcome.Str())
+		})
+	}
+}
+
 func TestSolaceMessageReceiveUnmarshallerV1InsertUserPropertyUnsupportedType(t *testing.T) {
 	u, tt := newTestReceiveV1Unmarshaller(t)
 	const key = "some-property"
@@ -872,11 +1555,73 @@ func TestSolaceMessageReceiveUnmarshallerV1InsertUserPropertyUnsupportedType(t *
 	}, metricdatatest.IgnoreTimestamp())
 }
 
+func TestReceiveUnmarshallerMaxEnqueueEvents(t *testing.T) {
+	spanData := &receive_v1.SpanData{
+		EnqueueEvents: []*receive_v1.SpanData_EnqueueEvent{
+			{Dest: &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue1"}},
+			{Dest: &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue2"}, RejectsAllEnqueues: true},
+			{Dest: &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue3"}},
+			{Dest: &receive_v1.SpanData_EnqueueEvent_QueueName{QueueName: "queue4"}, RejectsAllEnqueues: true},
+		},
+		TransactionEvent: &receive_v1.SpanData_TransactionEvent{
+			Type:          receive_v1.SpanData_TransactionEvent_COMMIT,
+			Initiator:     receive_v1.SpanData_TransactionEvent_CLIENT,
+			TransactionId: &receive_v1.SpanData_TransactionEvent_LocalId{LocalId: &receive_v1.SpanData_TransactionEvent_LocalTransactionId{}},
+		},
+	}
+	tests := []struct {
+		name             string
+		maxEnqueueEvents int
+		onlyOnFailure    bool
+		want             []string
+		wantTruncated    any
+	}{
+		{
+			name: "Unlimited",
+			want: []string{"queue1 enqueue", "queue2 enqueue", "queue3 enqueue", "queue4 enqueue", "commit"},
+		},
+		{
+			name:             "Under Limit",
+			maxEnqueueEvents: 4,
+			want:             []string{"queue1 enqueue", "queue2 enqueue", "queue3 enqueue", "queue4 enqueue", "commit"},
+		},
+		{
+			name:             "Over Limit",
+			maxEnqueueEvents: 2,
+			want:             []string{"queue1 enqueue", "queue2 enqueue", "commit"},
+			wantTruncated:    int64(2),
+		},
+		{
+			// omitted successful enqueues don't count towards the limit or the truncated events
+			name:             "Over Limit Only On Failure",
+			maxEnqueueEvents: 1,
+			onlyOnFailure:    true,
+			want:             []string{"queue2 enqueue", "commit"},
+			wantTruncated:    int64(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := newTestReceiveV1Unmarshaller(t)
+			u.cfg.MaxEnqueueEvents = tt.maxEnqueueEvents
+			u.cfg.EnqueueEventsOnlyOnFailure = tt.onlyOnFailure
+			actual := ptrace.NewTraces().ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			u.mapEvents(spanData, actual)
+			var names []string
+			for _, event := range actual.Events().All() {
+				names = append(names, event.Name())
+			}
+			assert.Equal(t, tt.want, names)
+			assert.Equal(t, tt.wantTruncated, actual.Attributes().AsRaw()["messaging.solace.truncated_enqueue_events"])
+		})
+	}
+}
+
 func newTestReceiveV1Unmarshaller(t *testing.T) (*brokerTraceReceiveUnmarshallerV1, *componenttest.Telemetry) {
 	tt := componenttest.NewTelemetry()
 	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) }) //nolint:usetesting
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(tt.NewTelemetrySettings())
 	require.NoError(t, err)
 	metricAttr := attribute.NewSet(attribute.String("receiver_name", ""))
-	return &brokerTraceReceiveUnmarshallerV1{zap.NewNop(), telemetryBuilder, metricAttr}, tt
+	return &brokerTraceReceiveUnmarshallerV1{zap.NewNop(), telemetryBuilder, metricAttr, TracesConfig{}, semConvAttrKeysFor(semConvCurrent)}, tt
 }
@@ -221,6 +221,7 @@ func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 				// expect some constants
 				span.SetKind(5)
 				span.SetName("someTopic receive")
+				span.SetFlags(sampledTraceFlag)
 				span.Status().SetCode(ptrace.StatusCodeUnset)
 				spanAttrs := span.Attributes()
 				populateAttributes(t, spanAttrs, map[string]any{
@@ -239,6 +240,7 @@ func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 					"messaging.solace.replication_group_message_id":           "rmid1:00010-40910192431-40516479-90a9c4e1",
 					"messaging.solace.priority":                               int64(1),
 					"messaging.solace.ttl":                                    int64(86000),
+					"messaging.solace.expiration_unix_nano":                   int64(87357924680),
 					"messaging.solace.dmq_eligible":                           true,
 					"messaging.solace.dropped_enqueue_events_success":         int64(42),
 					"messaging.solace.dropped_enqueue_events_failed":          int64(24),
@@ -255,10 +257,12 @@ func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 				populateEvent(t, span, "somequeue enqueue", 123456789, map[string]any{
 					"messaging.solace.destination.type":     "queue",
 					"messaging.solace.rejects_all_enqueues": false,
+					"messaging.solace.enqueue_outcome":      "success",
 				})
 				populateEvent(t, span, "sometopic enqueue", 2345678, map[string]any{
 					"messaging.solace.destination.type":     "topic-endpoint",
 					"messaging.solace.rejects_all_enqueues": false,
+					"messaging.solace.enqueue_outcome":      "success",
 				})
 				populateEvent(t, span, "session_timeout", 123456789, map[string]any{
 					"messaging.solace.transaction_initiator":   "client",
@@ -324,7 +328,7 @@ func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 			telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
 			require.NoError(t, err)
 			metricAttr := attribute.NewSet(attribute.String("receiver_name", metadata.Type.String()))
-			u := newTracesUnmarshaller(zap.NewNop(), telemetryBuilder, metricAttr)
+			u := newTracesUnmarshaller(zap.NewNop(), telemetryBuilder, metricAttr, TracesConfig{})
 			traces, err := u.unmarshal(tt.message)
 			if tt.err != nil {
 				assert.ErrorContains(t, err, tt.err.Error())
@@ -357,6 +361,36 @@ func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 
 // common helpers
 
+func TestNewTracesUnmarshallerMessagingSystem(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	metricAttr := attribute.NewSet(attribute.String("receiver_name", metadata.Type.String()))
+	for cfg, want := range map[TracesConfig]string{
+		{}:                          "SolacePubSub+",
+		{MessagingSystem: "solace"}: "solace",
+	} {
+		u := newTracesUnmarshaller(zap.NewNop(), telemetryBuilder, metricAttr, cfg).(*solaceTracesUnmarshaller)
+		assert.Equal(t, want, u.egressUnmarshallerV1.(*brokerTraceEgressUnmarshallerV1).messagingSystem)
+		assert.Equal(t, want, u.moveUnmarshallerV1.(*brokerTraceMoveUnmarshallerV1).messagingSystem)
+		assert.Equal(t, want, messagingSystem(u.receiveUnmarshallerV1.(*brokerTraceReceiveUnmarshallerV1).cfg))
+	}
+}
+
+func TestNewTracesUnmarshallerSemanticConventions(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	metricAttr := attribute.NewSet(attribute.String("receiver_name", metadata.Type.String()))
+	for semConv, want := range map[string]string{
+		semConvCurrent: "messaging.destination.name",
+		semConvLegacy:  "messaging.destination",
+	} {
+		u := newTracesUnmarshaller(zap.NewNop(), telemetryBuilder, metricAttr, TracesConfig{SemanticConventions: semConv}).(*solaceTracesUnmarshaller)
+		assert.Equal(t, want, u.egressUnmarshallerV1.(*brokerTraceEgressUnmarshallerV1).semConvKeys.destinationName)
+		assert.Equal(t, want, u.moveUnmarshallerV1.(*brokerTraceMoveUnmarshallerV1).semConvKeys.destinationName)
+		assert.Equal(t, want, u.receiveUnmarshallerV1.(*brokerTraceReceiveUnmarshallerV1).semConvKeys.destinationName)
+	}
+}
+
 func compareSpans(t *testing.T, expected, actual ptrace.Span) {
 	assert.Equal(t, expected.Name(), actual.Name())
 	assert.Equal(t, expected.TraceID(), actual.TraceID())